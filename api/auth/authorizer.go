@@ -5,21 +5,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/failuretoload/datamonster/request"
 	"github.com/failuretoload/datamonster/response"
+	tokendomain "github.com/failuretoload/datamonster/token/domain"
+	"golang.org/x/sync/singleflight"
 )
 
 type SessionData struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	IDToken      string `json:"id_token"`
-	UserID       string `json:"user_id"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	IDToken      string    `json:"id_token"`
+	UserID       string    `json:"user_id"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
 }
 
 type SessionStore interface {
@@ -27,11 +30,25 @@ type SessionStore interface {
 	Get(ctx context.Context, sessionID string) ([]byte, error)
 	Set(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error
 	Exists(ctx context.Context, sessionID string) (bool, error)
+	IndexSession(ctx context.Context, userID, sessionID string, device []byte) error
+	IndexedSessions(ctx context.Context, userID string) (map[string][]byte, error)
+	UnindexSession(ctx context.Context, userID, sessionID string) error
+}
+
+type TokenStore interface {
+	Authenticate(ctx context.Context, secret string) (*tokendomain.Token, error)
+}
+
+type IntrospectionCache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, data []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
 }
 
 type authorizationResponse struct {
 	Active bool   `json:"active"`
 	Sub    string `json:"sub"`
+	Exp    int64  `json:"exp,omitempty"`
 }
 
 type refreshTokenResponse struct {
@@ -42,15 +59,21 @@ type refreshTokenResponse struct {
 }
 
 type Authorizer struct {
-	clientID      string
-	clientSecret  string
-	introspectURL string
-	client        *http.Client
-	sessions      SessionStore
-	tokenURL      string
+	clientID       string
+	clientSecret   string
+	introspectURL  string
+	client         *http.Client
+	sessions       SessionStore
+	tokens         TokenStore
+	tokenURL       string
+	cache          IntrospectionCache
+	cacheTTL       time.Duration
+	introspections *singleflight.Group
+	jwtVerifier    *oidc.IDTokenVerifier
+	touches        *touchLimiter
 }
 
-func newAuthorizer(id, secret, introspectURL, tokenURL string, sessions SessionStore) (Authorizer, error) {
+func newAuthorizer(id, secret, introspectURL, tokenURL string, sessions SessionStore, tokens TokenStore) (Authorizer, error) {
 	if id == "" {
 		return Authorizer{}, ErrFieldMissing("clientID")
 	}
@@ -71,6 +94,10 @@ func newAuthorizer(id, secret, introspectURL, tokenURL string, sessions SessionS
 		return Authorizer{}, ErrFieldMissing("sessions")
 	}
 
+	if tokens == nil {
+		return Authorizer{}, ErrFieldMissing("tokens")
+	}
+
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second,
 		Transport: &http.Transport{
@@ -81,18 +108,27 @@ func newAuthorizer(id, secret, introspectURL, tokenURL string, sessions SessionS
 	}
 
 	return Authorizer{
-		clientID:      id,
-		clientSecret:  secret,
-		introspectURL: introspectURL,
-		tokenURL:      tokenURL,
-		client:        httpClient,
-		sessions:      sessions,
+		clientID:       id,
+		clientSecret:   secret,
+		introspectURL:  introspectURL,
+		tokenURL:       tokenURL,
+		client:         httpClient,
+		sessions:       sessions,
+		tokens:         tokens,
+		cacheTTL:       defaultIntrospectionTTL,
+		introspections: &singleflight.Group{},
+		touches:        newTouchLimiter(touchInterval),
 	}, nil
 }
 
 func (a Authorizer) AuthorizeRequest(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if header := r.Header.Get("Authorization"); header != "" {
+			a.authorizeToken(w, r, next, header)
+			return
+		}
+
 		cookie, err := r.Cookie(sessionCookieName)
 		if err != nil {
 			response.Unauthorized(ctx, w, fmt.Errorf("unable to read cookie: %w", err))
@@ -122,6 +158,7 @@ func (a Authorizer) AuthorizeRequest(next http.Handler) http.Handler {
 		}
 
 		if a.isActiveToken(ctx, session.AccessToken) {
+			a.touchSession(ctx, r, sessionID, session)
 			ctx = request.SetUserID(ctx, session.UserID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 			return
@@ -130,45 +167,46 @@ func (a Authorizer) AuthorizeRequest(next http.Handler) http.Handler {
 		age, err := a.refreshSession(ctx, session, sessionID)
 		if err != nil {
 			_ = a.sessions.Delete(ctx, cookie.Value)
+			_ = a.sessions.UnindexSession(ctx, session.UserID, cookie.Value)
 			response.Unauthorized(ctx, w, fmt.Errorf("session expired"))
 			return
 		}
 
 		setCookie(w, sessionID, age)
+		a.touchSession(ctx, r, sessionID, session)
 
 		ctx = request.SetUserID(ctx, session.UserID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func (a Authorizer) isActiveToken(ctx context.Context, token string) bool {
-	data := url.Values{}
-	data.Set("token", token)
-	data.Set("client_id", a.clientID)
-	data.Set("client_secret", a.clientSecret)
+func (a Authorizer) authorizeToken(w http.ResponseWriter, r *http.Request, next http.Handler, header string) {
+	ctx := r.Context()
+	scheme, secret, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || !tokendomain.IsSecret(secret) {
+		response.Unauthorized(ctx, w, fmt.Errorf("unsupported authorization header"))
+		return
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", a.introspectURL, strings.NewReader(data.Encode()))
+	token, err := a.tokens.Authenticate(ctx, secret)
 	if err != nil {
-		slog.Error("creating introspection request", slog.Any("error", err))
-		return false
+		response.Unauthorized(ctx, w, fmt.Errorf("unable to authenticate access token: %w", err))
+		return
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := a.client.Do(req)
-	if err != nil {
-		slog.Error("performing token introspection", slog.Any("error", err))
-		return false
+	if token == nil {
+		response.Unauthorized(ctx, w, fmt.Errorf("access token is invalid or expired"))
+		return
 	}
-	defer resp.Body.Close()
 
-	var authorization authorizationResponse
-	if err := json.NewDecoder(resp.Body).Decode(&authorization); err != nil {
-		slog.Error("decoding authorization response", slog.Any("error", err))
-		return false
+	if !tokendomain.Allows(token.Scope, r.Method) {
+		response.Forbidden(ctx, w, fmt.Errorf("access token scope %s does not allow %s", token.Scope, r.Method))
+		return
 	}
 
-	return authorization.Active
+	ctx = request.SetUserID(ctx, token.UserID)
+	ctx = request.SetTokenScope(ctx, token.Scope)
+	next.ServeHTTP(w, r.WithContext(ctx))
 }
 
 func (a Authorizer) refreshSession(ctx context.Context, sessionData SessionData, sessionID string) (int, error) {
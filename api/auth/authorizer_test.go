@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/store/memory"
+	tokendomain "github.com/failuretoload/datamonster/token/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tokenStoreFake map[string]tokendomain.Token
+
+func (f tokenStoreFake) Authenticate(_ context.Context, secret string) (*tokendomain.Token, error) {
+	t, ok := f[secret]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+func newSessionStore(t *testing.T) *memory.SessionStore {
+	store := memory.NewSessionStore(memory.DefaultSweepInterval)
+	t.Cleanup(store.Close)
+	return store
+}
+
+func indexed(t *testing.T, store SessionStore, userID string) map[string][]byte {
+	entries, err := store.IndexedSessions(context.Background(), userID)
+	require.NoError(t, err)
+	return entries
+}
+
+func TestAuthorizeRequest_BearerToken(t *testing.T) {
+	tokens := tokenStoreFake{
+		"dmp_reader": {UserID: "reader", Scope: tokendomain.ScopeRead},
+		"dmp_writer": {UserID: "writer", Scope: tokendomain.ScopeReadWrite},
+	}
+	a, err := newAuthorizer("id", "secret", "http://introspect", "http://token", newSessionStore(t), tokens)
+	require.NoError(t, err)
+
+	var gotUser, gotScope string
+	handler := a.AuthorizeRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = request.UserID(r.Context())
+		gotScope = request.TokenScope(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		status int
+		user   string
+	}{
+		{"read token reads", http.MethodGet, "Bearer dmp_reader", http.StatusOK, "reader"},
+		{"read token cannot write", http.MethodPost, "Bearer dmp_reader", http.StatusForbidden, ""},
+		{"write token writes", http.MethodPatch, "bearer dmp_writer", http.StatusOK, "writer"},
+		{"unknown token", http.MethodGet, "Bearer dmp_unknown", http.StatusUnauthorized, ""},
+		{"not a personal token", http.MethodGet, "Bearer eyJhbGciOi", http.StatusUnauthorized, ""},
+		{"basic auth", http.MethodGet, "Basic dXNlcjpwYXNz", http.StatusUnauthorized, ""},
+		{"no session cookie", http.MethodGet, "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUser, gotScope = "", ""
+			req := httptest.NewRequest(tt.method, "/settlements", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.user, gotUser)
+			if tt.user != "" {
+				assert.Equal(t, tokens["dmp_"+tt.user].Scope, gotScope)
+			}
+		})
+	}
+}
+
+func TestNewAuthorizer_RequiresTokens(t *testing.T) {
+	_, err := newAuthorizer("id", "secret", "http://introspect", "http://token", newSessionStore(t), nil)
+	assert.Error(t, err)
+}
+
+func TestConfigAuthorizer_JWKSRequiresCache(t *testing.T) {
+	config := Config{
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		IssuerURL:     "http://issuer",
+		IntrospectURL: "http://introspect",
+		TokenURL:      "http://token",
+		Sessions:      newSessionStore(t),
+		Tokens:        tokenStoreFake{},
+		JWKSURL:       "http://issuer/jwks",
+	}
+
+	_, err := config.Authorizer()
+	assert.Error(t, err)
+
+	config.IntrospectionCache = newIntrospectionCacheFake()
+	_, err = config.Authorizer()
+	assert.NoError(t, err)
+}
@@ -2,11 +2,10 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
-	"strings"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -21,14 +20,19 @@ type Claims struct {
 }
 
 type Config struct {
-	ClientID      string
-	ClientSecret  string
-	IssuerURL     string
-	RedirectURL   string
-	IntrospectURL string
-	ClientURL     string
-	TokenURL      string
-	Sessions      SessionStore
+	ClientID           string
+	ClientSecret       string
+	IssuerURL          string
+	RedirectURL        string
+	IntrospectURL      string
+	ClientURL          string
+	TokenURL           string
+	Sessions           SessionStore
+	Tokens             TokenStore
+	IntrospectionCache IntrospectionCache
+	IntrospectionTTL   time.Duration
+	JWKSURL            string
+	Audience           string
 }
 
 func (c Config) Validate() error {
@@ -68,24 +72,50 @@ func (c Config) Validate() error {
 }
 
 func (c Config) Authorizer() (Authorizer, error) {
-	return newAuthorizer(
+	if c.JWKSURL != "" && c.IntrospectionCache == nil {
+		return Authorizer{}, ErrFieldMissing("introspectionCache")
+	}
+
+	a, err := newAuthorizer(
 		c.ClientID,
 		c.ClientSecret,
 		c.IntrospectURL,
 		c.TokenURL,
 		c.Sessions,
+		c.Tokens,
 	)
+	if err != nil {
+		return Authorizer{}, err
+	}
+
+	a.cache = c.IntrospectionCache
+	if c.IntrospectionTTL > 0 {
+		a.cacheTTL = c.IntrospectionTTL
+	}
+
+	if c.JWKSURL != "" {
+		keys := oidc.NewRemoteKeySet(context.Background(), c.JWKSURL)
+		audience := c.Audience
+		if audience == "" {
+			audience = c.ClientID
+		}
+		a.jwtVerifier = oidc.NewVerifier(c.IssuerURL, keys, &oidc.Config{ClientID: audience})
+	}
+
+	return a, nil
 }
 
 type Controller struct {
-	clientID     string
-	clientSecret string
-	issuerURL    string
-	clientURL    string
-	oauth2Config *oauth2.Config
-	verifier     *oidc.IDTokenVerifier
-	httpClient   *http.Client
-	sessions     SessionStore
+	clientID      string
+	clientSecret  string
+	clientURL     string
+	oauth2Config  *oauth2.Config
+	verifier      *oidc.IDTokenVerifier
+	httpClient    *http.Client
+	sessions      SessionStore
+	cache         IntrospectionCache
+	revocationURL string
+	endSessionURL string
 }
 
 func (c Config) Controller() (*Controller, error) {
@@ -109,14 +139,23 @@ func (c Config) Controller() (*Controller, error) {
 
 	verifier := provider.Verifier(&oidc.Config{ClientID: c.ClientID})
 
+	metadata, err := discoverProviderMetadata(provider)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Controller{
 		clientID:     c.ClientID,
 		clientSecret: c.ClientSecret,
-		issuerURL:    c.IssuerURL,
 		clientURL:    c.ClientURL,
 		oauth2Config: oauth2Config,
 		verifier:     verifier,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
 		sessions:     c.Sessions,
+		cache:        c.IntrospectionCache,
+
+		revocationURL: metadata.RevocationEndpoint,
+		endSessionURL: metadata.EndSessionEndpoint,
 	}, nil
 }
 
@@ -136,20 +175,24 @@ func (c *Controller) callbackHandler() http.HandlerFunc {
 			return
 		}
 
-		if r.URL.Query().Get("state") != stateCookie.Value {
+		login, err := decodeLoginState(stateCookie.Value)
+		if err != nil {
+			response.BadRequest(ctx, w, fmt.Errorf("invalid state cookie: %w", err))
+			return
+		}
+
+		if r.URL.Query().Get("state") != login.State {
 			response.BadRequest(ctx, w, fmt.Errorf("state mismatch in callback handler"))
 			return
 		}
 
-		http.SetCookie(w, &http.Cookie{
-			Name:     stateCookieName,
-			Value:    "",
-			MaxAge:   -1,
-			HttpOnly: true,
-			Secure:   true,
-			SameSite: http.SameSiteLaxMode,
-			Path:     "/",
-		})
+		setStateCookie(w, "", -1)
+
+		returnTo, err := resolveReturnTo(c.clientURL, login.ReturnTo)
+		if err != nil {
+			response.BadRequest(ctx, w, err)
+			return
+		}
 
 		code := r.URL.Query().Get("code")
 		if code == "" {
@@ -157,7 +200,7 @@ func (c *Controller) callbackHandler() http.HandlerFunc {
 			return
 		}
 
-		token, err := c.oauth2Config.Exchange(r.Context(), code)
+		token, err := c.oauth2Config.Exchange(r.Context(), code, oauth2.VerifierOption(login.Verifier))
 		if err != nil {
 			response.InternalServerError(ctx, w, fmt.Errorf("token exchange failed: %w", err))
 			return
@@ -175,6 +218,11 @@ func (c *Controller) callbackHandler() http.HandlerFunc {
 			return
 		}
 
+		if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(login.Nonce)) != 1 {
+			response.BadRequest(ctx, w, fmt.Errorf("nonce mismatch in callback handler"))
+			return
+		}
+
 		var claims Claims
 		if err := idToken.Claims(&claims); err != nil {
 			response.InternalServerError(ctx, w, fmt.Errorf("parsing token claims: %w", err))
@@ -187,11 +235,13 @@ func (c *Controller) callbackHandler() http.HandlerFunc {
 			return
 		}
 
+		now := time.Now()
 		sessionData := SessionData{
 			AccessToken:  token.AccessToken,
 			RefreshToken: token.RefreshToken,
 			IDToken:      rawIDToken,
 			UserID:       claims.Subject,
+			CreatedAt:    now,
 		}
 
 		data, err := json.Marshal(sessionData)
@@ -206,6 +256,10 @@ func (c *Controller) callbackHandler() http.HandlerFunc {
 			return
 		}
 
+		if err := indexSession(ctx, c.sessions, r, sessionID, sessionData, now); err != nil {
+			logger.Warn(ctx, "indexing new session", logger.ErrorField(err))
+		}
+
 		http.SetCookie(w, &http.Cookie{
 			Name:     sessionCookieName,
 			Value:    sessionID,
@@ -216,7 +270,7 @@ func (c *Controller) callbackHandler() http.HandlerFunc {
 			Path:     "/",
 		})
 
-		http.Redirect(w, r, c.clientURL, http.StatusTemporaryRedirect)
+		http.Redirect(w, r, returnTo, http.StatusTemporaryRedirect)
 	}
 }
 
@@ -224,80 +278,66 @@ func (c *Controller) logoutHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		cookie, err := r.Cookie(sessionCookieName)
-		if err != nil {
-			response.InternalServerError(ctx, w, err)
-		}
-		w.WriteHeader(http.StatusNoContent)
-
-		if cookie.Value == "" {
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, c.logoutURL(""), http.StatusTemporaryRedirect)
 			return
 		}
 		expireCookie(w)
 
-		sessionBytes, err := c.sessions.Get(r.Context(), cookie.Value)
-		if err != nil {
+		var sessionData SessionData
+		sessionBytes, err := c.sessions.Get(ctx, cookie.Value)
+		switch {
+		case err != nil:
 			logger.Warn(ctx, "fetching session for logout", logger.ErrorField(err))
-			return
-		}
-		if len(sessionBytes) == 0 {
+		case len(sessionBytes) == 0:
 			logger.Warn(ctx, "logout: session is empty")
-			return
+		default:
+			if err := json.Unmarshal(sessionBytes, &sessionData); err != nil {
+				logger.Warn(ctx, "unmarshaling session for logout", logger.ErrorField(err))
+			}
 		}
 
-		var sessionData SessionData
-		if err := json.Unmarshal(sessionBytes, &sessionData); err != nil {
-			logger.Warn(ctx, "unmarshaling session for logout", logger.ErrorField(err))
-			return
-		}
-		if sessionData.AccessToken != "" {
-			c.revokeToken(r.Context(), sessionData.AccessToken)
-		}
+		c.revokeSessionTokens(ctx, sessionData)
 
 		if err := c.sessions.Delete(ctx, cookie.Value); err != nil {
 			logger.Warn(ctx, "logout: failed to delete session", logger.ErrorField(err))
 		}
-	}
-}
-
-func (c *Controller) revokeToken(ctx context.Context, token string) {
-	data := url.Values{}
-	data.Set("token", token)
-	data.Set("client_id", c.clientID)
-	data.Set("client_secret", c.clientSecret)
-
-	revocationURL := c.issuerURL + "/api/oidc/revocation"
-	req, err := http.NewRequestWithContext(ctx, "POST", revocationURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		logger.Warn(ctx, "creating token revocation request", logger.ErrorField(err))
-		return
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if sessionData.UserID != "" {
+			if err := c.sessions.UnindexSession(ctx, sessionData.UserID, cookie.Value); err != nil {
+				logger.Warn(ctx, "logout: failed to unindex session", logger.ErrorField(err))
+			}
+		}
 
-	if _, err := c.httpClient.Do(req); err != nil {
-		logger.Warn(ctx, "executing token revocation request", logger.ErrorField(err))
+		http.Redirect(w, r, c.logoutURL(sessionData.IDToken), http.StatusTemporaryRedirect)
 	}
 }
 
 func (c *Controller) loginHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		state, err := generateSessionID()
+		ctx := r.Context()
+		returnTo, err := resolveReturnTo(c.clientURL, r.URL.Query().Get("returnTo"))
 		if err != nil {
-			response.InternalServerError(r.Context(), w, fmt.Errorf("failed to generate state: %w", err))
+			response.BadRequest(ctx, w, err)
 			return
 		}
 
-		http.SetCookie(w, &http.Cookie{
-			Name:     stateCookieName,
-			Value:    state,
-			MaxAge:   300,
-			HttpOnly: true,
-			Secure:   isSecureCookie(),
-			SameSite: http.SameSiteLaxMode,
-			Path:     "/",
-		})
+		login, err := newLoginState(returnTo)
+		if err != nil {
+			response.InternalServerError(ctx, w, err)
+			return
+		}
+
+		value, err := login.encode()
+		if err != nil {
+			response.InternalServerError(ctx, w, fmt.Errorf("failed to encode login state: %w", err))
+			return
+		}
+		setStateCookie(w, value, loginStateMaxAge)
 
-		authURL := c.oauth2Config.AuthCodeURL(state)
+		authURL := c.oauth2Config.AuthCodeURL(login.State,
+			oauth2.S256ChallengeOption(login.Verifier),
+			oidc.Nonce(login.Nonce),
+		)
 		http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
 	}
 }
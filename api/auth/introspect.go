@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/failuretoload/datamonster/logger"
+)
+
+const defaultIntrospectionTTL = time.Minute
+
+func (a Authorizer) isActiveToken(ctx context.Context, token string) bool {
+	if a.jwtVerifier != nil && isJWT(token) {
+		if _, err := a.jwtVerifier.Verify(ctx, token); err != nil {
+			logger.Debug(ctx, "access token failed local verification", logger.ErrorField(err))
+			return false
+		}
+		return !isRevoked(ctx, a.cache, token)
+	}
+
+	key := introspectionKey(token)
+	if a.cache != nil {
+		data, err := a.cache.Get(ctx, key)
+		if err != nil {
+			logger.Warn(ctx, "reading cached introspection", logger.ErrorField(err))
+		}
+		var cached authorizationResponse
+		if len(data) > 0 && json.Unmarshal(data, &cached) == nil && cached.Active {
+			return true
+		}
+	}
+
+	result, err, _ := a.introspections.Do(key, func() (any, error) {
+		return a.introspect(context.WithoutCancel(ctx), token)
+	})
+	if err != nil {
+		logger.Error(ctx, "introspecting access token", logger.ErrorField(err))
+		return false
+	}
+
+	authorization := result.(authorizationResponse)
+	if authorization.Active && a.cache != nil {
+		a.remember(ctx, key, authorization)
+	}
+
+	return authorization.Active
+}
+
+func (a Authorizer) introspect(ctx context.Context, token string) (authorizationResponse, error) {
+	data := url.Values{}
+	data.Set("token", token)
+	data.Set("client_id", a.clientID)
+	data.Set("client_secret", a.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.introspectURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return authorizationResponse{}, fmt.Errorf("creating introspection request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return authorizationResponse{}, fmt.Errorf("performing token introspection: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return authorizationResponse{}, fmt.Errorf("introspection request failed: status=%d", resp.StatusCode)
+	}
+
+	var authorization authorizationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authorization); err != nil {
+		return authorizationResponse{}, fmt.Errorf("decoding authorization response: %w", err)
+	}
+
+	return authorization, nil
+}
+
+func (a Authorizer) remember(ctx context.Context, key string, authorization authorizationResponse) {
+	ttl := cacheTTL(a.cacheTTL, authorization.Exp, time.Now())
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(authorization)
+	if err != nil {
+		logger.Warn(ctx, "serializing introspection", logger.ErrorField(err))
+		return
+	}
+
+	if err := a.cache.Set(ctx, key, data, ttl); err != nil {
+		logger.Warn(ctx, "caching introspection", logger.ErrorField(err))
+	}
+}
+
+func forgetIntrospection(ctx context.Context, cache IntrospectionCache, token string) {
+	if cache == nil {
+		return
+	}
+
+	if err := cache.Delete(ctx, introspectionKey(token)); err != nil {
+		logger.Warn(ctx, "removing cached introspection", logger.ErrorField(err))
+	}
+
+	if !isJWT(token) {
+		return
+	}
+	ttl := time.Until(time.Unix(jwtExpiry(token), 0))
+	if ttl <= 0 {
+		return
+	}
+	if err := cache.Set(ctx, revokedKey(token), []byte("revoked"), ttl); err != nil {
+		logger.Warn(ctx, "caching token revocation", logger.ErrorField(err))
+	}
+}
+
+func isRevoked(ctx context.Context, cache IntrospectionCache, token string) bool {
+	if cache == nil {
+		return false
+	}
+
+	data, err := cache.Get(ctx, revokedKey(token))
+	if err != nil {
+		logger.Warn(ctx, "reading cached token revocation", logger.ErrorField(err))
+	}
+	return len(data) > 0
+}
+
+func jwtExpiry(token string) int64 {
+	parts := strings.Split(token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil {
+		return 0
+	}
+	return claims.Exp
+}
+
+func cacheTTL(limit time.Duration, exp int64, now time.Time) time.Duration {
+	if exp == 0 {
+		return limit
+	}
+
+	return min(limit, time.Unix(exp, 0).Sub(now))
+}
+
+func introspectionKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func revokedKey(token string) string {
+	return "revoked:" + introspectionKey(token)
+}
+
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheEntry struct {
+	data []byte
+	ttl  time.Duration
+}
+
+type introspectionCacheFake struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newIntrospectionCacheFake() *introspectionCacheFake {
+	return &introspectionCacheFake{entries: map[string]cacheEntry{}}
+}
+
+func (f *introspectionCacheFake) Get(_ context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.entries[key].data, nil
+}
+
+func (f *introspectionCacheFake) Set(_ context.Context, key string, data []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries[key] = cacheEntry{data: data, ttl: ttl}
+	return nil
+}
+
+func (f *introspectionCacheFake) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.entries, key)
+	return nil
+}
+
+// introspectionServer answers every introspection with body after release is
+// closed, counting the calls it receives.
+func introspectionServer(t *testing.T, body string, release <-chan struct{}) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func testAuthorizer(t *testing.T, introspectURL string, cache IntrospectionCache) Authorizer {
+	a, err := newAuthorizer("id", "secret", introspectURL, "http://token", newSessionStore(t), tokenStoreFake{})
+	require.NoError(t, err)
+	a.cache = cache
+	return a
+}
+
+func TestIsActiveToken_CachesActiveResults(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	exp := time.Now().Add(time.Hour).Unix()
+	srv, calls := introspectionServer(t, fmt.Sprintf(`{"active":true,"sub":"user","exp":%d}`, exp), release)
+
+	cache := newIntrospectionCacheFake()
+	a := testAuthorizer(t, srv.URL, cache)
+
+	assert.True(t, a.isActiveToken(context.Background(), "opaque-token"))
+	assert.True(t, a.isActiveToken(context.Background(), "opaque-token"))
+	assert.Equal(t, int32(1), calls.Load())
+
+	entry, ok := cache.entries[introspectionKey("opaque-token")]
+	require.True(t, ok)
+	assert.Equal(t, defaultIntrospectionTTL, entry.ttl)
+	assert.NotContains(t, cache.entries, "opaque-token", "raw tokens are not used as keys")
+
+	forgetIntrospection(context.Background(), cache, "opaque-token")
+	assert.True(t, a.isActiveToken(context.Background(), "opaque-token"))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIsActiveToken_DoesNotCacheInactive(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	srv, calls := introspectionServer(t, `{"active":false}`, release)
+
+	cache := newIntrospectionCacheFake()
+	a := testAuthorizer(t, srv.URL, cache)
+
+	assert.False(t, a.isActiveToken(context.Background(), "expired-token"))
+	assert.False(t, a.isActiveToken(context.Background(), "expired-token"))
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Empty(t, cache.entries)
+}
+
+func TestIsActiveToken_CollapsesConcurrentIntrospections(t *testing.T) {
+	release := make(chan struct{})
+	srv, calls := introspectionServer(t, `{"active":true,"sub":"user"}`, release)
+	a := testAuthorizer(t, srv.URL, nil)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]bool, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = a.isActiveToken(context.Background(), "shared-token")
+		}()
+	}
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, active := range results {
+		assert.True(t, active)
+	}
+}
+
+func TestIsActiveToken_ProviderErrorIsInactive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	cache := newIntrospectionCacheFake()
+	a := testAuthorizer(t, srv.URL, cache)
+	assert.False(t, a.isActiveToken(context.Background(), "token"))
+	assert.Empty(t, cache.entries)
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	assert.Equal(t, time.Minute, cacheTTL(time.Minute, 0, now), "no expiry reported")
+	assert.Equal(t, time.Minute, cacheTTL(time.Minute, now.Add(time.Hour).Unix(), now))
+	assert.Equal(t, 10*time.Second, cacheTTL(time.Minute, now.Add(10*time.Second).Unix(), now))
+	assert.LessOrEqual(t, cacheTTL(time.Minute, now.Add(-time.Second).Unix(), now), time.Duration(0))
+}
+
+// jwtSigner returns a function that signs access tokens for audience and a
+// verifier that expects the "id" audience.
+func jwtSigner(t *testing.T) (func(audience string, exp time.Time) string, *oidc.IDTokenVerifier) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, nil)
+	require.NoError(t, err)
+
+	sign := func(audience string, exp time.Time) string {
+		claims, err := json.Marshal(map[string]any{"iss": "https://issuer", "aud": audience, "sub": "user", "exp": exp.Unix()})
+		require.NoError(t, err)
+		signed, err := signer.Sign(claims)
+		require.NoError(t, err)
+		token, err := signed.CompactSerialize()
+		require.NoError(t, err)
+		return token
+	}
+
+	keys := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{&key.PublicKey}}
+	return sign, oidc.NewVerifier("https://issuer", keys, &oidc.Config{ClientID: "id"})
+}
+
+func TestIsActiveToken_VerifiesJWTLocally(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	srv, calls := introspectionServer(t, `{"active":false}`, release)
+
+	sign, verifier := jwtSigner(t)
+	a := testAuthorizer(t, srv.URL, nil)
+	a.jwtVerifier = verifier
+
+	assert.True(t, a.isActiveToken(context.Background(), sign("id", time.Now().Add(time.Hour))))
+	assert.False(t, a.isActiveToken(context.Background(), sign("id", time.Now().Add(-time.Hour))))
+	assert.False(t, a.isActiveToken(context.Background(), sign("other", time.Now().Add(time.Hour))), "wrong audience")
+	assert.Zero(t, calls.Load(), "JWTs never reach the introspection endpoint")
+
+	assert.False(t, a.isActiveToken(context.Background(), "opaque"))
+	assert.Equal(t, int32(1), calls.Load(), "opaque tokens are still introspected")
+}
+
+func TestIsActiveToken_RevokedJWT(t *testing.T) {
+	sign, verifier := jwtSigner(t)
+	cache := newIntrospectionCacheFake()
+	a := testAuthorizer(t, "http://introspect", cache)
+	a.jwtVerifier = verifier
+
+	token := sign("id", time.Now().Add(time.Hour))
+	require.True(t, a.isActiveToken(context.Background(), token))
+
+	forgetIntrospection(context.Background(), cache, token)
+	assert.False(t, a.isActiveToken(context.Background(), token))
+
+	entry := cache.entries[revokedKey(token)]
+	assert.InDelta(t, time.Hour.Seconds(), entry.ttl.Seconds(), 5, "kept until the token expires")
+}
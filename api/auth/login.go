@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+const loginStateMaxAge = 300
+
+type loginState struct {
+	State    string `json:"state"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	ReturnTo string `json:"return_to,omitempty"`
+}
+
+func newLoginState(returnTo string) (loginState, error) {
+	state, err := generateSessionID()
+	if err != nil {
+		return loginState{}, fmt.Errorf("failed to generate state: %w", err)
+	}
+
+	nonce, err := generateSessionID()
+	if err != nil {
+		return loginState{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return loginState{
+		State:    state,
+		Verifier: oauth2.GenerateVerifier(),
+		Nonce:    nonce,
+		ReturnTo: returnTo,
+	}, nil
+}
+
+func (s loginState) encode() (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeLoginState(value string) (loginState, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return loginState{}, err
+	}
+
+	var s loginState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return loginState{}, err
+	}
+	if s.State == "" || s.Verifier == "" || s.Nonce == "" {
+		return loginState{}, errors.New("incomplete login state")
+	}
+	return s, nil
+}
+
+func setStateCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    value,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   isSecureCookie(),
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+	})
+}
+
+func resolveReturnTo(clientURL, returnTo string) (string, error) {
+	base, err := url.Parse(clientURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid client url: %w", err)
+	}
+	if returnTo == "" {
+		return base.String(), nil
+	}
+
+	if strings.ContainsAny(returnTo, "\\\r\n\t") {
+		return "", errors.New("invalid returnTo")
+	}
+
+	target, err := url.Parse(returnTo)
+	if err != nil {
+		return "", errors.New("invalid returnTo")
+	}
+
+	if !target.IsAbs() {
+		if target.Host != "" || !strings.HasPrefix(target.Path, "/") {
+			return "", errors.New("returnTo must be an absolute path")
+		}
+		target = base.ResolveReference(&url.URL{Path: target.Path, RawQuery: target.RawQuery, Fragment: target.Fragment})
+	}
+
+	if target.Scheme != base.Scheme || target.Host != base.Host || target.User != nil {
+		return "", errors.New("returnTo must stay on the client")
+	}
+
+	basePath := strings.TrimSuffix(base.Path, "/")
+	if target.Path != basePath && !strings.HasPrefix(target.Path, basePath+"/") {
+		return "", errors.New("returnTo must stay on the client")
+	}
+
+	return target.String(), nil
+}
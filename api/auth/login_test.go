@@ -0,0 +1,283 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/failuretoload/datamonster/store/memory"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveReturnTo(t *testing.T) {
+	tests := []struct {
+		name      string
+		clientURL string
+		returnTo  string
+		want      string
+	}{
+		{"default", "https://app.example.com", "", "https://app.example.com"},
+		{"path", "https://app.example.com", "/settlements/1?tab=survivors#top", "https://app.example.com/settlements/1?tab=survivors#top"},
+		{"absolute on client", "https://app.example.com", "https://app.example.com/settlements", "https://app.example.com/settlements"},
+		{"under base path", "https://example.com/dm/", "/dm/settlements", "https://example.com/dm/settlements"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveReturnTo(tt.clientURL, tt.returnTo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveReturnToRejectsOtherOrigins(t *testing.T) {
+	for _, returnTo := range []string{
+		"https://evil.example.com/",
+		"http://app.example.com/",
+		"https://app.example.com.evil.com/",
+		"https://user@app.example.com/",
+		"//evil.example.com/",
+		"/\\evil.example.com",
+		"settlements",
+		"javascript:alert(1)",
+		"/other/path",
+	} {
+		t.Run(returnTo, func(t *testing.T) {
+			_, err := resolveReturnTo("https://app.example.com/dm", returnTo)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoginStateRoundTrip(t *testing.T) {
+	login, err := newLoginState("https://app.example.com/settlements")
+	require.NoError(t, err)
+	assert.NotEmpty(t, login.State)
+	assert.NotEmpty(t, login.Verifier)
+	assert.NotEmpty(t, login.Nonce)
+
+	value, err := login.encode()
+	require.NoError(t, err)
+	decoded, err := decodeLoginState(value)
+	require.NoError(t, err)
+	assert.Equal(t, login, decoded)
+
+	for _, bad := range []string{"", "not base64!", base64.RawURLEncoding.EncodeToString([]byte(`{"state":"x"}`))} {
+		_, err := decodeLoginState(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// fakeProvider is an OIDC provider that checks the PKCE verifier on the token
+// endpoint, signs ID tokens with whatever nonce the test asks for and records
+// token revocations.
+type fakeProvider struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+	revoked   []string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &fakeProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                p.URL,
+			"authorization_endpoint":                p.URL + "/authorize",
+			"token_endpoint":                        p.URL + "/token",
+			"jwks_uri":                              p.URL + "/jwks",
+			"revocation_endpoint":                   p.URL + "/revoke",
+			"end_session_endpoint":                  p.URL + "/logout",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/revoke", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		p.revoked = append(p.revoked, r.PostForm.Get("token_type_hint")+":"+r.PostForm.Get("token"))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access",
+			"refresh_token": "refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"id_token":      p.idToken(t),
+		})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func (p *fakeProvider) idToken(t *testing.T) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithHeader("kid", "test"),
+	)
+	require.NoError(t, err)
+
+	claims, err := json.Marshal(map[string]any{
+		"iss":   p.URL,
+		"aud":   "client",
+		"sub":   "user",
+		"nonce": p.nonce,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	signed, err := signer.Sign(claims)
+	require.NoError(t, err)
+	token, err := signed.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+type loginFixture struct {
+	provider   *fakeProvider
+	controller *Controller
+	store      *memory.SessionStore
+}
+
+func newLoginFixture(t *testing.T) loginFixture {
+	provider := newFakeProvider(t)
+	store := newSessionStore(t)
+	c, err := Config{
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		IssuerURL:     provider.URL,
+		RedirectURL:   "https://api.example.com/auth/callback",
+		IntrospectURL: provider.URL + "/introspect",
+		TokenURL:      provider.URL + "/token",
+		ClientURL:     "https://app.example.com",
+		Sessions:      store,
+	}.Controller()
+	require.NoError(t, err)
+
+	return loginFixture{provider: provider, controller: c, store: store}
+}
+
+// login starts a login and returns the state cookie and the authorization
+// request's query.
+func (f loginFixture) login(t *testing.T, returnTo string) (*http.Cookie, url.Values) {
+	req := httptest.NewRequest(http.MethodGet, "/login?returnTo="+url.QueryEscape(returnTo), nil)
+	w := httptest.NewRecorder()
+	f.controller.loginHandler()(w, req)
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	require.Len(t, w.Result().Cookies(), 1)
+
+	return w.Result().Cookies()[0], location.Query()
+}
+
+func (f loginFixture) callback(state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/callback?code=abc&state="+url.QueryEscape(state), nil)
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	f.controller.callbackHandler()(w, req)
+	return w
+}
+
+func TestLoginFlow_PKCEAndNonce(t *testing.T) {
+	f := newLoginFixture(t)
+	cookie, query := f.login(t, "/settlements/42")
+
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.NotEmpty(t, query.Get("code_challenge"))
+	require.NotEmpty(t, query.Get("nonce"))
+	assert.NotContains(t, query.Encode(), "verifier")
+
+	f.provider.challenge = query.Get("code_challenge")
+	f.provider.nonce = query.Get("nonce")
+
+	w := f.callback(query.Get("state"), cookie)
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code, w.Body.String())
+	assert.Equal(t, "https://app.example.com/settlements/42", w.Header().Get("Location"))
+
+	var session *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == sessionCookieName {
+			session = c
+		}
+	}
+	require.NotNil(t, session)
+	assert.NotEmpty(t, session.Value)
+}
+
+func TestLoginFlow_RejectsNonceMismatch(t *testing.T) {
+	f := newLoginFixture(t)
+	cookie, query := f.login(t, "")
+	f.provider.challenge = query.Get("code_challenge")
+	f.provider.nonce = "replayed"
+
+	w := f.callback(query.Get("state"), cookie)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginFlow_RejectsWrongVerifier(t *testing.T) {
+	f := newLoginFixture(t)
+	cookie, query := f.login(t, "")
+	f.provider.nonce = query.Get("nonce")
+
+	other, _ := f.login(t, "")
+	login, err := decodeLoginState(cookie.Value)
+	require.NoError(t, err)
+	stolen, err := decodeLoginState(other.Value)
+	require.NoError(t, err)
+	login.Verifier = stolen.Verifier
+	f.provider.challenge = query.Get("code_challenge")
+
+	cookie.Value, err = login.encode()
+	require.NoError(t, err)
+
+	w := f.callback(query.Get("state"), cookie)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "the provider refuses the exchange")
+}
+
+func TestLoginFlow_RejectsStateMismatch(t *testing.T) {
+	f := newLoginFixture(t)
+	cookie, _ := f.login(t, "")
+
+	w := f.callback("forged", cookie)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLogin_RejectsForeignReturnTo(t *testing.T) {
+	f := newLoginFixture(t)
+	req := httptest.NewRequest(http.MethodGet, "/login?returnTo="+url.QueryEscape("https://evil.example.com"), nil)
+	w := httptest.NewRecorder()
+	f.controller.loginHandler()(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Result().Cookies())
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/failuretoload/datamonster/logger"
+)
+
+type providerMetadata struct {
+	RevocationEndpoint string `json:"revocation_endpoint"`
+	EndSessionEndpoint string `json:"end_session_endpoint"`
+}
+
+func discoverProviderMetadata(provider *oidc.Provider) (providerMetadata, error) {
+	var metadata providerMetadata
+	if err := provider.Claims(&metadata); err != nil {
+		return providerMetadata{}, fmt.Errorf("reading provider metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// revokeSessionTokens revokes the session's refresh token, then its access
+// token. The refresh token goes first so a provider that cascades revocation
+// cannot mint a new access token in between.
+func (c *Controller) revokeSessionTokens(ctx context.Context, session SessionData) {
+	if session.RefreshToken != "" {
+		c.revokeToken(ctx, session.RefreshToken, "refresh_token")
+	}
+
+	if session.AccessToken != "" {
+		c.revokeToken(ctx, session.AccessToken, "access_token")
+		forgetIntrospection(ctx, c.cache, session.AccessToken)
+	}
+}
+
+func (c *Controller) revokeToken(ctx context.Context, token, hint string) {
+	if c.revocationURL == "" {
+		return
+	}
+
+	data := url.Values{}
+	data.Set("token", token)
+	data.Set("token_type_hint", hint)
+	data.Set("client_id", c.clientID)
+	data.Set("client_secret", c.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.revocationURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		logger.Warn(ctx, "creating token revocation request", logger.ErrorField(err))
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Warn(ctx, "executing token revocation request", logger.ErrorField(err))
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Warn(ctx, "token revocation rejected", slog.Int("status", resp.StatusCode), slog.String("token_type_hint", hint))
+	}
+}
+
+func (c *Controller) logoutURL(idToken string) string {
+	if c.endSessionURL == "" {
+		return c.clientURL
+	}
+
+	u, err := url.Parse(c.endSessionURL)
+	if err != nil {
+		return c.clientURL
+	}
+
+	query := u.Query()
+	if idToken != "" {
+		query.Set("id_token_hint", idToken)
+	}
+	query.Set("client_id", c.clientID)
+	query.Set("post_logout_redirect_uri", c.clientURL)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f loginFixture) logout(sessionID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/logout", nil)
+	if sessionID != "" {
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	}
+	w := httptest.NewRecorder()
+	f.controller.logoutHandler()(w, req)
+	return w
+}
+
+func TestLogout_EndsSessionAtProvider(t *testing.T) {
+	f := newLoginFixture(t)
+	saveSession(t, f.store, "session-1", SessionData{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		IDToken:      "id-token",
+		UserID:       "user",
+	}, &device{UserAgent: "Firefox"})
+
+	w := f.logout("session-1")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, f.provider.URL+"/logout", location.Scheme+"://"+location.Host+location.Path)
+	assert.Equal(t, "id-token", location.Query().Get("id_token_hint"))
+	assert.Equal(t, "https://app.example.com", location.Query().Get("post_logout_redirect_uri"))
+	assert.Equal(t, "client", location.Query().Get("client_id"))
+
+	assert.Equal(t, []string{"refresh_token:refresh", "access_token:access"}, f.provider.revoked)
+
+	data, err := f.store.Get(t.Context(), "session-1")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Empty(t, indexed(t, f.store, "user"))
+
+	require.Len(t, w.Result().Cookies(), 1)
+	assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+}
+
+func TestLogout_WithoutSession(t *testing.T) {
+	f := newLoginFixture(t)
+
+	w := f.logout("")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Empty(t, location.Query().Get("id_token_hint"))
+	assert.Equal(t, "https://app.example.com", location.Query().Get("post_logout_redirect_uri"))
+	assert.Empty(t, f.provider.revoked)
+}
+
+func TestLogout_ProviderWithoutEndSession(t *testing.T) {
+	f := newLoginFixture(t)
+	f.controller.endSessionURL = ""
+	f.controller.revocationURL = ""
+	saveSession(t, f.store, "session-1", SessionData{AccessToken: "access", IDToken: "id-token", UserID: "user"}, nil)
+
+	w := f.logout("session-1")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Location"))
+	assert.Empty(t, f.provider.revoked)
+}
+
+func TestLogoutURL_KeepsProviderQuery(t *testing.T) {
+	c := &Controller{
+		clientID:      "client",
+		clientURL:     "https://app.example.com",
+		endSessionURL: "https://idp.example.com/logout?tenant=dm",
+	}
+
+	location, err := url.Parse(c.logoutURL("id-token"))
+	require.NoError(t, err)
+	assert.Equal(t, "dm", location.Query().Get("tenant"))
+	assert.Equal(t, "id-token", location.Query().Get("id_token_hint"))
+}
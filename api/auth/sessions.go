@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	touchInterval   = time.Minute
+	maxTrackedTouch = 10000
+)
+
+var errSessionNotFound = errors.New("session not found")
+
+type device struct {
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+type Session struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"userAgent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Current   bool      `json:"current"`
+}
+
+func sessionHandle(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:16])
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func indexSession(ctx context.Context, sessions SessionStore, r *http.Request, sessionID string, session SessionData, now time.Time) error {
+	data, err := json.Marshal(device{
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r),
+		CreatedAt: session.CreatedAt,
+		LastSeen:  now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return sessions.IndexSession(ctx, session.UserID, sessionID, data)
+}
+
+type touchLimiter struct {
+	mu       sync.Mutex
+	seen     map[string]time.Time
+	interval time.Duration
+}
+
+func newTouchLimiter(interval time.Duration) *touchLimiter {
+	return &touchLimiter{seen: map[string]time.Time{}, interval: interval}
+}
+
+func (l *touchLimiter) due(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.seen[key]; ok && now.Sub(last) < l.interval {
+		return false
+	}
+	if len(l.seen) >= maxTrackedTouch {
+		for k, last := range l.seen {
+			if now.Sub(last) >= l.interval {
+				delete(l.seen, k)
+			}
+		}
+	}
+	l.seen[key] = now
+
+	return true
+}
+
+func (a Authorizer) touchSession(ctx context.Context, r *http.Request, sessionID string, session SessionData) {
+	now := time.Now()
+	if !a.touches.due(sessionID, now) {
+		return
+	}
+
+	if err := indexSession(ctx, a.sessions, r, sessionID, session, now); err != nil {
+		logger.Warn(ctx, "updating session index", logger.ErrorField(err))
+	}
+}
+
+func (c *Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.RequireSession)
+		gr.Get("/sessions", c.listSessions)
+		gr.Delete("/sessions", c.endAllSessions)
+		gr.Delete("/sessions/{id}", c.endSessionByID)
+	})
+}
+
+func (c *Controller) userSessions(ctx context.Context, userID string) (map[string]device, error) {
+	indexed, err := c.sessions.IndexedSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]device, len(indexed))
+	for sessionID, data := range indexed {
+		exists, err := c.sessions.Exists(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if err := c.sessions.UnindexSession(ctx, userID, sessionID); err != nil {
+				logger.Warn(ctx, "pruning expired session from index", logger.ErrorField(err))
+			}
+			continue
+		}
+
+		var d device
+		if err := json.Unmarshal(data, &d); err != nil {
+			logger.Warn(ctx, "unmarshaling indexed session", logger.ErrorField(err))
+		}
+		live[sessionID] = d
+	}
+
+	return live, nil
+}
+
+func (c *Controller) listSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	live, err := c.userSessions(ctx, request.UserID(ctx))
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving sessions: %w", err))
+		return
+	}
+
+	current := currentSessionID(r)
+	sessions := make([]Session, 0, len(live))
+	for sessionID, d := range live {
+		sessions = append(sessions, Session{
+			ID:        sessionHandle(sessionID),
+			UserAgent: d.UserAgent,
+			IP:        d.IP,
+			CreatedAt: d.CreatedAt,
+			LastSeen:  d.LastSeen,
+			Current:   sessionID == current,
+		})
+	}
+	slices.SortFunc(sessions, func(a, b Session) int {
+		return cmp.Or(b.LastSeen.Compare(a.LastSeen), cmp.Compare(a.ID, b.ID))
+	})
+
+	response.OK(ctx, w, sessions)
+}
+
+func (c *Controller) endSessionByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := request.UserID(ctx)
+	live, err := c.userSessions(ctx, userID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving sessions: %w", err))
+		return
+	}
+
+	handle := chi.URLParam(r, "id")
+	for sessionID := range live {
+		if sessionHandle(sessionID) != handle {
+			continue
+		}
+
+		if err := c.endSession(ctx, userID, sessionID); err != nil {
+			response.InternalServerError(ctx, w, fmt.Errorf("error ending session: %w", err))
+			return
+		}
+		if sessionID == currentSessionID(r) {
+			expireCookie(w)
+		}
+		response.NoContent(w)
+		return
+	}
+
+	response.NotFound(ctx, w, errSessionNotFound)
+}
+
+func (c *Controller) endAllSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := request.UserID(ctx)
+	live, err := c.userSessions(ctx, userID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving sessions: %w", err))
+		return
+	}
+
+	for sessionID := range live {
+		if err := c.endSession(ctx, userID, sessionID); err != nil {
+			response.InternalServerError(ctx, w, fmt.Errorf("error ending session: %w", err))
+			return
+		}
+	}
+
+	if current := currentSessionID(r); current != "" {
+		if err := c.endSession(ctx, userID, current); err != nil {
+			response.InternalServerError(ctx, w, fmt.Errorf("error ending session: %w", err))
+			return
+		}
+	}
+
+	expireCookie(w)
+	response.NoContent(w)
+}
+
+func (c *Controller) endSession(ctx context.Context, userID, sessionID string) error {
+	data, err := c.sessions.Get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > 0 {
+		var session SessionData
+		if err := json.Unmarshal(data, &session); err != nil {
+			logger.Warn(ctx, "unmarshaling session to end", logger.ErrorField(err))
+		} else if session.UserID != userID {
+			return errSessionNotFound
+		} else {
+			c.revokeSessionTokens(ctx, session)
+		}
+	}
+
+	if err := c.sessions.Delete(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return c.sessions.UnindexSession(ctx, userID, sessionID)
+}
+
+func currentSessionID(r *http.Request) string {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
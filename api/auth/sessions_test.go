@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/store/memory"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func saveSession(t *testing.T, store *memory.SessionStore, sessionID string, session SessionData, d *device) {
+	data, err := json.Marshal(session)
+	require.NoError(t, err)
+	require.NoError(t, store.Set(context.Background(), sessionID, data, time.Hour))
+
+	if d != nil {
+		raw, err := json.Marshal(d)
+		require.NoError(t, err)
+		require.NoError(t, store.IndexSession(context.Background(), session.UserID, sessionID, raw))
+	}
+}
+
+func TestAuthorizeRequest_TouchesSessionIndex(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	srv, _ := introspectionServer(t, `{"active":true,"sub":"user"}`, release)
+
+	store := newSessionStore(t)
+	created := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	saveSession(t, store, "session-1", SessionData{AccessToken: "access", UserID: "user", CreatedAt: created}, nil)
+
+	a, err := newAuthorizer("id", "secret", srv.URL, "http://token", store, tokenStoreFake{})
+	require.NoError(t, err)
+	handler := a.AuthorizeRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(userAgent string) {
+		req := httptest.NewRequest(http.MethodGet, "/settlements", nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "session-1"})
+		req.Header.Set("User-Agent", userAgent)
+		req.RemoteAddr = "203.0.113.7:5555"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	send("Firefox")
+	var d device
+	require.NoError(t, json.Unmarshal(indexed(t, store, "user")["session-1"], &d))
+	assert.Equal(t, "Firefox", d.UserAgent)
+	assert.Equal(t, "203.0.113.7", d.IP)
+	assert.True(t, created.Equal(d.CreatedAt))
+	assert.WithinDuration(t, time.Now(), d.LastSeen, time.Minute)
+
+	send("Chrome")
+	require.NoError(t, json.Unmarshal(indexed(t, store, "user")["session-1"], &d))
+	assert.Equal(t, "Firefox", d.UserAgent, "last seen is only updated once per interval")
+}
+
+func TestTouchLimiter(t *testing.T) {
+	l := newTouchLimiter(time.Minute)
+	now := time.Now()
+	assert.True(t, l.due("a", now))
+	assert.False(t, l.due("a", now.Add(30*time.Second)))
+	assert.True(t, l.due("b", now.Add(30*time.Second)))
+	assert.True(t, l.due("a", now.Add(time.Minute)))
+}
+
+type sessionsFixture struct {
+	store       *memory.SessionStore
+	revocations *atomic.Int32
+	router      chi.Router
+}
+
+func newSessionsFixture(t *testing.T) sessionsFixture {
+	var revocations atomic.Int32
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/revoke" {
+			revocations.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(provider.Close)
+
+	store := newSessionStore(t)
+	c := &Controller{
+		clientID:      "id",
+		clientSecret:  "secret",
+		revocationURL: provider.URL + "/revoke",
+		httpClient:    provider.Client(),
+		sessions:      store,
+	}
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := request.SetUserID(r.Context(), r.Header.Get("X-User"))
+			if scope := r.Header.Get("X-Token-Scope"); scope != "" {
+				ctx = request.SetTokenScope(ctx, scope)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	c.RegisterRoutes(router)
+
+	return sessionsFixture{store: store, revocations: &revocations, router: router}
+}
+
+func (f sessionsFixture) do(method, path, userID, sessionID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("X-User", userID)
+	if sessionID != "" {
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func (f sessionsFixture) list(t *testing.T, userID, sessionID string) []Session {
+	w := f.do(http.MethodGet, "/sessions", userID, sessionID)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var sessions []Session
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	return sessions
+}
+
+func TestSessions_ListAndEnd(t *testing.T) {
+	f := newSessionsFixture(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	saveSession(t, f.store, "laptop", SessionData{AccessToken: "a1", UserID: "user"}, &device{UserAgent: "Firefox", IP: "203.0.113.1", CreatedAt: now.Add(-time.Hour), LastSeen: now})
+	saveSession(t, f.store, "phone", SessionData{AccessToken: "a2", UserID: "user"}, &device{UserAgent: "Safari", IP: "203.0.113.2", CreatedAt: now.Add(-2 * time.Hour), LastSeen: now.Add(-time.Minute)})
+	saveSession(t, f.store, "elsewhere", SessionData{AccessToken: "a3", UserID: "other"}, &device{UserAgent: "Edge"})
+	require.NoError(t, f.store.IndexSession(context.Background(), "user", "expired", []byte(`{}`)))
+
+	sessions := f.list(t, "user", "laptop")
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "Firefox", sessions[0].UserAgent)
+	assert.True(t, sessions[0].Current)
+	assert.Equal(t, "Safari", sessions[1].UserAgent)
+	assert.False(t, sessions[1].Current)
+	assert.NotContains(t, indexed(t, f.store, "user"), "expired", "expired sessions are pruned from the index")
+
+	body := f.do(http.MethodGet, "/sessions", "user", "laptop").Body.String()
+	assert.NotContains(t, body, "laptop", "session IDs are never exposed")
+	assert.NotContains(t, body, "phone")
+
+	w := f.do(http.MethodDelete, "/sessions/"+sessionHandle("elsewhere"), "user", "laptop")
+	assert.Equal(t, http.StatusNotFound, w.Code, "other users' sessions cannot be ended")
+	w = f.do(http.MethodDelete, "/sessions/unknown", "user", "laptop")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = f.do(http.MethodDelete, "/sessions/"+sessions[1].ID, "user", "laptop")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Result().Cookies(), "ending another device keeps this cookie")
+	assert.Equal(t, int32(1), f.revocations.Load())
+
+	exists, err := f.store.Exists(context.Background(), "phone")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Len(t, f.list(t, "user", "laptop"), 1)
+}
+
+func TestSessions_EndCurrentExpiresCookie(t *testing.T) {
+	f := newSessionsFixture(t)
+	saveSession(t, f.store, "laptop", SessionData{AccessToken: "a1", UserID: "user"}, &device{UserAgent: "Firefox"})
+
+	w := f.do(http.MethodDelete, "/sessions/"+sessionHandle("laptop"), "user", "laptop")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, w.Result().Cookies(), 1)
+	assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+}
+
+func TestSessions_LogOutEverywhere(t *testing.T) {
+	f := newSessionsFixture(t)
+	saveSession(t, f.store, "laptop", SessionData{AccessToken: "a1", UserID: "user"}, &device{UserAgent: "Firefox"})
+	saveSession(t, f.store, "phone", SessionData{AccessToken: "a2", UserID: "user"}, &device{UserAgent: "Safari"})
+	saveSession(t, f.store, "unindexed", SessionData{AccessToken: "a3", UserID: "user"}, nil)
+	saveSession(t, f.store, "elsewhere", SessionData{AccessToken: "a4", UserID: "other"}, &device{UserAgent: "Edge"})
+
+	w := f.do(http.MethodDelete, "/sessions", "user", "unindexed")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, w.Result().Cookies(), 1)
+	assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+	assert.Equal(t, int32(3), f.revocations.Load())
+
+	for _, id := range []string{"laptop", "phone", "unindexed"} {
+		exists, err := f.store.Exists(context.Background(), id)
+		require.NoError(t, err)
+		assert.False(t, exists, id)
+	}
+	assert.Empty(t, f.list(t, "user", ""))
+	assert.Len(t, f.list(t, "other", ""), 1)
+}
+
+func TestSessions_RejectAccessTokens(t *testing.T) {
+	f := newSessionsFixture(t)
+	req := httptest.NewRequest(http.MethodDelete, "/sessions", nil)
+	req.Header.Set("X-User", "user")
+	req.Header.Set("X-Token-Scope", "read-write")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
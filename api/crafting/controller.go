@@ -0,0 +1,224 @@
+package crafting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/failuretoload/datamonster/crafting/domain"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+const maxAdjustment = 1000
+
+type (
+	Repo interface {
+		BuiltLocations(ctx context.Context, settlementID uuid.UUID) (map[string]bool, error)
+		SetLocation(ctx context.Context, settlementID uuid.UUID, locationID string, built bool) error
+		Storage(ctx context.Context, settlementID uuid.UUID) ([]domain.Item, error)
+		Adjust(ctx context.Context, settlementID uuid.UUID, kind, itemID string, delta int) (domain.Item, error)
+		Craft(ctx context.Context, settlementID uuid.UUID, recipe domain.Recipe, keywords map[string][]string) (domain.CraftResult, error)
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Glossary interface {
+		Locations() []glossary.Location
+		Location(id string) (glossary.Location, bool)
+		Resources() []glossary.Resource
+		Resource(id string) (glossary.Resource, bool)
+		GearRecipe(id string) (glossary.GearRecipe, bool)
+		Gear(id string) (glossary.Gear, bool)
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+		glossary    Glossary
+	}
+	LocationRequest struct {
+		Built bool `json:"built"`
+	}
+	StorageRequest struct {
+		Kind     string `json:"kind"`
+		ID       string `json:"id"`
+		Quantity int    `json:"quantity"`
+	}
+	CraftRequest struct {
+		Recipe string `json:"recipe"`
+	}
+)
+
+func NewController(r Repo, s Settlements, g Glossary) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("glossary cannot be nil")
+	}
+
+	return &Controller{records: r, settlements: s, glossary: g}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Get("/settlements/{id}/locations", c.getLocations)
+		gr.Put("/settlements/{id}/locations/{locationID}", c.setLocation)
+		gr.Get("/settlements/{id}/storage", c.getStorage)
+		gr.Post("/settlements/{id}/storage", c.adjustStorage)
+		gr.Post("/settlements/{id}/craft", c.craft)
+	})
+}
+
+func (c Controller) getLocations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	built, err := c.records.BuiltLocations(ctx, request.SettlementID(ctx))
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving settlement locations: %w", err))
+		return
+	}
+
+	all := c.glossary.Locations()
+	locations := make([]domain.Location, len(all))
+	for i, l := range all {
+		locations[i] = domain.Location{ID: l.ID, Name: l.Name, Built: built[l.ID]}
+	}
+
+	response.OK(ctx, w, locations)
+}
+
+func (c Controller) setLocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	location, ok := c.glossary.Location(chi.URLParam(r, "locationID"))
+	if !ok {
+		response.NotFound(ctx, w, fmt.Errorf("location not found"))
+		return
+	}
+
+	var body LocationRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	if err := c.records.SetLocation(ctx, request.SettlementID(ctx), location.ID, body.Built); err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error updating settlement location: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, domain.Location{ID: location.ID, Name: location.Name, Built: body.Built})
+}
+
+func (c Controller) getStorage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storage, err := c.records.Storage(ctx, request.SettlementID(ctx))
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving settlement storage: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, storage)
+}
+
+func (c Controller) adjustStorage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body StorageRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	if !domain.ValidKind(body.Kind) {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid storage kind: %s", body.Kind))
+		return
+	}
+	if body.ID == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("id is required"))
+		return
+	}
+	if body.Kind == domain.KindResource {
+		if _, ok := c.glossary.Resource(body.ID); !ok {
+			response.BadRequest(ctx, w, fmt.Errorf("unknown resource: %s", body.ID))
+			return
+		}
+	}
+	if body.Kind == domain.KindGear {
+		if _, ok := c.glossary.Gear(body.ID); !ok {
+			response.BadRequest(ctx, w, fmt.Errorf("unknown gear: %s", body.ID))
+			return
+		}
+	}
+	if body.Quantity == 0 || body.Quantity < -maxAdjustment || body.Quantity > maxAdjustment {
+		response.BadRequest(ctx, w, fmt.Errorf("quantity must be between -%d and %d and not zero", maxAdjustment, maxAdjustment))
+		return
+	}
+
+	item, err := c.records.Adjust(ctx, request.SettlementID(ctx), body.Kind, body.ID, body.Quantity)
+	if errors.Is(err, domain.ErrInsufficientStock) || errors.Is(err, domain.ErrGearEquipped) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error adjusting settlement storage: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, item)
+}
+
+func (c Controller) craft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body CraftRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	entry, ok := c.glossary.GearRecipe(body.Recipe)
+	if !ok {
+		response.BadRequest(ctx, w, fmt.Errorf("unknown recipe: %s", body.Recipe))
+		return
+	}
+
+	recipe := fromRecipe(entry)
+	if err := recipe.Validate(); err != nil {
+		response.InternalServerError(ctx, w, err)
+		return
+	}
+
+	keywords := map[string][]string{}
+	for _, res := range c.glossary.Resources() {
+		keywords[res.ID] = res.Keywords
+	}
+
+	result, err := c.records.Craft(ctx, request.SettlementID(ctx), recipe, keywords)
+	if errors.Is(err, domain.ErrLocationNotBuilt) || errors.Is(err, domain.ErrInsufficientResources) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error crafting gear: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, result)
+}
+
+func fromRecipe(r glossary.GearRecipe) domain.Recipe {
+	costs := make([]domain.Cost, len(r.Costs))
+	for i, c := range r.Costs {
+		costs[i] = domain.Cost{Resource: c.Resource, Keyword: c.Keyword, Quantity: c.Quantity}
+	}
+	return domain.Recipe{ID: r.ID, Location: r.Location, Gear: r.Gear, Costs: costs}
+}
@@ -0,0 +1,252 @@
+package crafting_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/crafting"
+	"github.com/failuretoload/datamonster/crafting/domain"
+	craftingRepo "github.com/failuretoload/datamonster/crafting/repo"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for crafting tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryStub := testenv.NewGlossaryStub(`{
+		"locations":[
+			{"id":"bone-smith","name":"Bone Smith","source":"core"},
+			{"id":"skinnery","name":"Skinnery","source":"core"}
+		],
+		"resources":[
+			{"id":"bone","name":"Bone","source":"core","keywords":["bone"]},
+			{"id":"hide","name":"Hide","source":"core","keywords":["hide"]},
+			{"id":"cat-bones","name":"Great Cat Bones","source":"core","keywords":["bone"]}
+		],
+		"recipes":[
+			{"id":"dagger","name":"Bone Dagger","source":"core","location":"bone-smith","gear":"bone-dagger",
+				"costs":[{"keyword":"bone","quantity":2}]},
+			{"id":"blade","name":"Bone Blade","source":"core","location":"bone-smith","gear":"bone-blade",
+				"costs":[{"resource":"bone","quantity":1},{"resource":"hide","quantity":1}]},
+			{"id":"headband","name":"Rawhide Headband","source":"core","location":"skinnery","gear":"headband",
+				"costs":[{"keyword":"hide","quantity":1}]}
+		],
+		"gear":[
+			{"id":"lantern","name":"Lantern","source":"core","keywords":["item"],"affinities":{}}
+		]
+	}`)
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	workshopRepo, err := craftingRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	craftingController, err := crafting.NewController(workshopRepo, settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, craftingController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func stock(t *testing.T, userID, settlementID, body string) {
+	raw, status := requester.AdjustStorage(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+}
+
+func storage(t *testing.T, userID, settlementID string) map[string]int {
+	raw, status := requester.GetStorage(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+
+	var items []domain.Item
+	require.NoError(t, json.NewDecoder(raw).Decode(&items))
+
+	quantities := map[string]int{}
+	for _, i := range items {
+		quantities[i.Kind+":"+i.ID] = i.Quantity
+	}
+	return quantities
+}
+
+func TestLocations_BuildAndList(t *testing.T) {
+	userID := "crafting-locations-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	raw, status := requester.GetLocations(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var locations []domain.Location
+	require.NoError(t, json.NewDecoder(raw).Decode(&locations))
+	require.Len(t, locations, 2)
+	for _, l := range locations {
+		assert.False(t, l.Built, l.Name)
+	}
+
+	_, status = requester.SetLocation(userID, settlementID, "skinnery", true)
+	require.Equal(t, http.StatusOK, status)
+
+	raw, status = requester.GetLocations(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.NewDecoder(raw).Decode(&locations))
+	built := map[string]bool{}
+	for _, l := range locations {
+		built[l.ID] = l.Built
+	}
+	assert.Equal(t, map[string]bool{"bone-smith": false, "skinnery": true}, built)
+
+	_, status = requester.SetLocation(userID, settlementID, "skinnery", false)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.SetLocation(userID, settlementID, "dragon-armory", true)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestStorage_Adjust(t *testing.T) {
+	userID := "crafting-storage-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	stock(t, userID, settlementID, `{"kind":"resource","id":"bone","quantity":3}`)
+	stock(t, userID, settlementID, `{"kind":"resource","id":"bone","quantity":-1}`)
+	stock(t, userID, settlementID, `{"kind":"gear","id":"lantern","quantity":1}`)
+
+	assert.Equal(t, map[string]int{"resource:bone": 2, "gear:lantern": 1}, storage(t, userID, settlementID))
+
+	_, status := requester.AdjustStorage(userID, settlementID, `{"kind":"resource","id":"bone","quantity":-3}`)
+	assert.Equal(t, http.StatusConflict, status)
+	_, status = requester.AdjustStorage(userID, settlementID, `{"kind":"resource","id":"hide","quantity":-1}`)
+	assert.Equal(t, http.StatusConflict, status)
+
+	for _, body := range []string{
+		`{"kind":"trinket","id":"bone","quantity":1}`,
+		`{"kind":"resource","id":"dragon-scale","quantity":1}`,
+		`{"kind":"resource","id":"bone","quantity":0}`,
+		`{"kind":"gear","id":"","quantity":1}`,
+		`{"kind":"gear","id":"lantern-halberd","quantity":1}`,
+	} {
+		_, status := requester.AdjustStorage(userID, settlementID, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+}
+
+func TestCraft_KeywordSubstitution(t *testing.T) {
+	userID := "crafting-keyword-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	_, status := requester.SetLocation(userID, settlementID, "bone-smith", true)
+	require.Equal(t, http.StatusOK, status)
+
+	stock(t, userID, settlementID, `{"kind":"resource","id":"bone","quantity":1}`)
+	stock(t, userID, settlementID, `{"kind":"resource","id":"cat-bones","quantity":1}`)
+
+	raw, status := requester.Craft(userID, settlementID, "dagger")
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var result domain.CraftResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	assert.Equal(t, "dagger", result.Recipe)
+	assert.Equal(t, domain.Item{Kind: domain.KindGear, ID: "bone-dagger", Quantity: 1}, result.Gear)
+	assert.Equal(t, map[string]int{"bone": 1, "cat-bones": 1}, result.Consumed)
+
+	assert.Equal(t, map[string]int{"gear:bone-dagger": 1}, storage(t, userID, settlementID))
+}
+
+func TestCraft_InsufficientResourcesLeavesStorage(t *testing.T) {
+	userID := "crafting-short-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	_, status := requester.SetLocation(userID, settlementID, "bone-smith", true)
+	require.Equal(t, http.StatusOK, status)
+
+	stock(t, userID, settlementID, `{"kind":"resource","id":"bone","quantity":1}`)
+
+	_, status = requester.Craft(userID, settlementID, "blade")
+	assert.Equal(t, http.StatusConflict, status)
+	_, status = requester.Craft(userID, settlementID, "dagger")
+	assert.Equal(t, http.StatusConflict, status)
+
+	assert.Equal(t, map[string]int{"resource:bone": 1}, storage(t, userID, settlementID))
+}
+
+func TestCraft_LocationNotBuilt(t *testing.T) {
+	userID := "crafting-unbuilt-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	stock(t, userID, settlementID, `{"kind":"resource","id":"hide","quantity":1}`)
+
+	_, status := requester.Craft(userID, settlementID, "headband")
+	assert.Equal(t, http.StatusConflict, status)
+
+	_, status = requester.SetLocation(userID, settlementID, "skinnery", true)
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.Craft(userID, settlementID, "headband")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestCraft_UnknownRecipe(t *testing.T) {
+	userID := "crafting-unknown-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.Craft(userID, settlementID, "lantern-halberd")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestCrafting_SettlementIsolation(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("crafting-owner")
+	require.NoError(t, err)
+
+	_, status := requester.GetStorage("crafting-intruder", settlementID)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, status = requester.Craft("crafting-intruder", settlementID, "dagger")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestCrafting_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.GetStorage("unauthorized", testenv.UUIDString())
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
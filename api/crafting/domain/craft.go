@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	KindResource = "resource"
+	KindGear     = "gear"
+
+	MaxRecipeUnits = 30
+)
+
+var (
+	ErrLocationNotBuilt      = errors.New("location is not built")
+	ErrInsufficientResources = errors.New("storage does not cover the recipe cost")
+	ErrInsufficientStock     = errors.New("not enough of that item in storage")
+	ErrGearEquipped          = errors.New("survivors have that gear equipped")
+)
+
+type Item struct {
+	Kind     string `json:"kind"`
+	ID       string `json:"id"`
+	Quantity int    `json:"quantity"`
+}
+
+type Location struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Built bool   `json:"built"`
+}
+
+type Cost struct {
+	Resource string
+	Keyword  string
+	Quantity int
+}
+
+type Recipe struct {
+	ID       string
+	Location string
+	Gear     string
+	Costs    []Cost
+}
+
+type CraftResult struct {
+	Recipe   string         `json:"recipe"`
+	Gear     Item           `json:"gear"`
+	Consumed map[string]int `json:"consumed"`
+	Storage  []Item         `json:"storage"`
+}
+
+func ValidKind(kind string) bool {
+	return kind == KindResource || kind == KindGear
+}
+
+func (r Recipe) Validate() error {
+	units := 0
+	for _, c := range r.Costs {
+		if (c.Resource == "") == (c.Keyword == "") {
+			return fmt.Errorf("recipe %s: a cost names exactly one of resource or keyword", r.ID)
+		}
+		if c.Quantity < 1 {
+			return fmt.Errorf("recipe %s: cost quantity must be positive", r.ID)
+		}
+		units += c.Quantity
+	}
+	if units > MaxRecipeUnits {
+		return fmt.Errorf("recipe %s: costs exceed %d resources", r.ID, MaxRecipeUnits)
+	}
+	return nil
+}
+
+func Allocate(costs []Cost, available map[string]int, keywords map[string][]string) (map[string]int, error) {
+	remaining := maps.Clone(available)
+	if remaining == nil {
+		remaining = map[string]int{}
+	}
+	consumed := map[string]int{}
+
+	var units []string
+	for _, c := range costs {
+		if c.Resource != "" {
+			if remaining[c.Resource] < c.Quantity {
+				return nil, fmt.Errorf("%w: need %d %s", ErrInsufficientResources, c.Quantity, c.Resource)
+			}
+			remaining[c.Resource] -= c.Quantity
+			consumed[c.Resource] += c.Quantity
+			continue
+		}
+		for range c.Quantity {
+			units = append(units, c.Keyword)
+		}
+	}
+
+	candidates := map[string][]string{}
+	for _, keyword := range units {
+		if _, ok := candidates[keyword]; ok {
+			continue
+		}
+		for _, id := range slices.Sorted(maps.Keys(remaining)) {
+			if slices.Contains(keywords[id], keyword) {
+				candidates[keyword] = append(candidates[keyword], id)
+			}
+		}
+	}
+	slices.SortFunc(units, func(a, b string) int {
+		if n := len(candidates[a]) - len(candidates[b]); n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+
+	if !fill(units, 0, candidates, remaining, consumed) {
+		return nil, fmt.Errorf("%w: keyword costs cannot be covered", ErrInsufficientResources)
+	}
+
+	return consumed, nil
+}
+
+func fill(units []string, start int, candidates map[string][]string, remaining, consumed map[string]int) bool {
+	if len(units) == 0 {
+		return true
+	}
+
+	options := candidates[units[0]]
+	for i := start; i < len(options); i++ {
+		id := options[i]
+		if remaining[id] == 0 {
+			continue
+		}
+		next := 0
+		if len(units) > 1 && units[1] == units[0] {
+			next = i
+		}
+		remaining[id]--
+		consumed[id]++
+		if fill(units[1:], next, candidates, remaining, consumed) {
+			return true
+		}
+		remaining[id]++
+		consumed[id]--
+		if consumed[id] == 0 {
+			delete(consumed, id)
+		}
+	}
+	return false
+}
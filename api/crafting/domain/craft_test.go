@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var keywords = map[string][]string{
+	"bone":        {"bone"},
+	"hide":        {"hide"},
+	"organ":       {"organ"},
+	"lion-claw":   {"bone", "scale"},
+	"lion-tail":   {"hide"},
+	"shimmering":  {"organ", "hide"},
+	"broken-lamp": {},
+}
+
+func TestAllocate_NamedResources(t *testing.T) {
+	consumed, err := Allocate(
+		[]Cost{{Resource: "bone", Quantity: 2}, {Resource: "lion-tail", Quantity: 1}},
+		map[string]int{"bone": 3, "lion-tail": 1},
+		keywords,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"bone": 2, "lion-tail": 1}, consumed)
+}
+
+func TestAllocate_KeywordSubstitution(t *testing.T) {
+	consumed, err := Allocate(
+		[]Cost{{Keyword: "bone", Quantity: 2}},
+		map[string]int{"bone": 1, "lion-claw": 1},
+		keywords,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"bone": 1, "lion-claw": 1}, consumed)
+}
+
+func TestAllocate_BacktracksMultiKeywordResources(t *testing.T) {
+	// shimmering could pay either cost, but hide can only be paid by it.
+	consumed, err := Allocate(
+		[]Cost{{Keyword: "organ", Quantity: 1}, {Keyword: "hide", Quantity: 1}},
+		map[string]int{"organ": 1, "shimmering": 1},
+		keywords,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"organ": 1, "shimmering": 1}, consumed)
+}
+
+func TestAllocate_NamedBeforeKeyword(t *testing.T) {
+	_, err := Allocate(
+		[]Cost{{Keyword: "bone", Quantity: 1}, {Resource: "bone", Quantity: 1}},
+		map[string]int{"bone": 1},
+		keywords,
+	)
+	assert.ErrorIs(t, err, ErrInsufficientResources)
+}
+
+func TestAllocate_Insufficient(t *testing.T) {
+	tests := []struct {
+		name      string
+		costs     []Cost
+		available map[string]int
+	}{
+		{"missing resource", []Cost{{Resource: "organ", Quantity: 1}}, map[string]int{"bone": 4}},
+		{"short resource", []Cost{{Resource: "bone", Quantity: 3}}, map[string]int{"bone": 2}},
+		{"no keyword match", []Cost{{Keyword: "scale", Quantity: 2}}, map[string]int{"lion-claw": 1, "broken-lamp": 5}},
+		{"empty storage", []Cost{{Keyword: "hide", Quantity: 1}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Allocate(tt.costs, tt.available, keywords)
+			assert.ErrorIs(t, err, ErrInsufficientResources)
+		})
+	}
+}
+
+func TestAllocate_DoesNotMutateStorage(t *testing.T) {
+	available := map[string]int{"bone": 2}
+	_, err := Allocate([]Cost{{Resource: "bone", Quantity: 2}}, available, keywords)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"bone": 2}, available)
+}
+
+func TestRecipeValidate(t *testing.T) {
+	assert.NoError(t, Recipe{ID: "r", Costs: []Cost{{Resource: "bone", Quantity: 1}, {Keyword: "hide", Quantity: 2}}}.Validate())
+	assert.Error(t, Recipe{ID: "r", Costs: []Cost{{Resource: "bone", Keyword: "hide", Quantity: 1}}}.Validate())
+	assert.Error(t, Recipe{ID: "r", Costs: []Cost{{Quantity: 1}}}.Validate())
+	assert.Error(t, Recipe{ID: "r", Costs: []Cost{{Resource: "bone"}}}.Validate())
+	assert.Error(t, Recipe{ID: "r", Costs: []Cost{{Keyword: "bone", Quantity: MaxRecipeUnits + 1}}}.Validate())
+}
+
+func TestAllocate_LargeFailureTerminates(t *testing.T) {
+	available := map[string]int{}
+	kw := map[string][]string{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		available[id] = 3
+		kw[id] = []string{"bone"}
+	}
+
+	_, err := Allocate([]Cost{{Keyword: "bone", Quantity: 25}}, available, kw)
+	assert.ErrorIs(t, err, ErrInsufficientResources)
+}
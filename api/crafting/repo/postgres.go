@@ -0,0 +1,277 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/failuretoload/datamonster/crafting/domain"
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/store/postgres"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	getBuilt    = `SELECT location_id FROM settlement_location WHERE settlement_id = $1 AND built`
+	isBuilt     = `SELECT built FROM settlement_location WHERE settlement_id = $1 AND location_id = $2 FOR SHARE`
+	setLocation = `INSERT INTO settlement_location (settlement_id, location_id, built) VALUES ($1, $2, $3)
+ON CONFLICT (settlement_id, location_id) DO UPDATE SET built = EXCLUDED.built, updated_at = NOW()`
+	getStorage = `SELECT kind, item_id, quantity FROM settlement_storage
+WHERE settlement_id = $1 AND quantity > 0
+ORDER BY kind, item_id`
+	lockResources = `SELECT item_id, quantity FROM settlement_storage
+WHERE settlement_id = $1 AND kind = 'resource' AND quantity > 0
+ORDER BY item_id
+FOR UPDATE`
+	adjustItem = `INSERT INTO settlement_storage (settlement_id, kind, item_id, quantity) VALUES ($1, $2, $3, $4)
+ON CONFLICT (settlement_id, kind, item_id) DO UPDATE SET quantity = settlement_storage.quantity + EXCLUDED.quantity
+RETURNING quantity`
+	lockSettlement = `SELECT 1 FROM settlement WHERE external_id = $1 FOR UPDATE`
+	countEquipped  = `SELECT COUNT(*) FROM survivor_loadout, unnest(grid) AS slot
+WHERE settlement_id = $1 AND slot = $2`
+	debitResource = `UPDATE settlement_storage SET quantity = quantity - $3
+WHERE settlement_id = $1 AND kind = 'resource' AND item_id = $2`
+)
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("crafting repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) BuiltLocations(ctx context.Context, settlementID uuid.UUID) (map[string]bool, error) {
+	rows, err := r.db.Query(ctx, getBuilt, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query settlement locations")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan settlement locations")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	built := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		built[id] = true
+	}
+	return built, nil
+}
+
+func (r Postgres) SetLocation(ctx context.Context, settlementID uuid.UUID, locationID string, built bool) error {
+	_, err := r.db.Exec(ctx, setLocation, settlementID, locationID, built)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to update settlement location")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+	return nil
+}
+
+func (r Postgres) Storage(ctx context.Context, settlementID uuid.UUID) ([]domain.Item, error) {
+	return readStorage(ctx, r.db, settlementID)
+}
+
+func (r Postgres) Adjust(ctx context.Context, settlementID uuid.UUID, kind, itemID string, delta int) (domain.Item, error) {
+	item := domain.Item{Kind: kind, ID: itemID}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, lockSettlement, settlementID)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to lock settlement")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		err = tx.QueryRow(ctx, adjustItem, settlementID, kind, itemID, delta).Scan(&item.Quantity)
+		if postgres.IsCheckViolation(err) {
+			return domain.ErrInsufficientStock
+		}
+		if err != nil {
+			safeErr := fmt.Errorf("unable to adjust settlement storage")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+		if kind != domain.KindGear || delta > 0 {
+			return nil
+		}
+
+		var equipped int
+		err = tx.QueryRow(ctx, countEquipped, settlementID, itemID).Scan(&equipped)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to count equipped gear")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+		if item.Quantity < equipped {
+			return fmt.Errorf("%w: %d equipped, %d left in storage", domain.ErrGearEquipped, equipped, item.Quantity)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Item{}, err
+	}
+
+	return item, nil
+}
+
+func (r Postgres) Craft(ctx context.Context, settlementID uuid.UUID, recipe domain.Recipe, keywords map[string][]string) (domain.CraftResult, error) {
+	result := domain.CraftResult{Recipe: recipe.ID}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var built bool
+		err := tx.QueryRow(ctx, isBuilt, settlementID, recipe.Location).Scan(&built)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			safeErr := fmt.Errorf("unable to query settlement location")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+		if !built {
+			return domain.ErrLocationNotBuilt
+		}
+
+		available, err := lockStock(ctx, tx, settlementID)
+		if err != nil {
+			return err
+		}
+
+		consumed, err := domain.Allocate(recipe.Costs, available, keywords)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range slices.Sorted(maps.Keys(consumed)) {
+			_, err := tx.Exec(ctx, debitResource, settlementID, id, consumed[id])
+			if err != nil {
+				safeErr := fmt.Errorf("unable to debit resource")
+				logger.Error(ctx, safeErr.Error(),
+					logger.SettlementID(settlementID.String()),
+					logger.ErrorField(err),
+				)
+				return safeErr
+			}
+		}
+
+		gear := domain.Item{Kind: domain.KindGear, ID: recipe.Gear}
+		err = tx.QueryRow(ctx, adjustItem, settlementID, gear.Kind, gear.ID, 1).Scan(&gear.Quantity)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to credit gear")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		storage, err := readStorage(ctx, tx, settlementID)
+		if err != nil {
+			return err
+		}
+
+		result.Gear = gear
+		result.Consumed = consumed
+		result.Storage = storage
+		return nil
+	})
+	if err != nil {
+		return domain.CraftResult{}, err
+	}
+
+	return result, nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func readStorage(ctx context.Context, q querier, settlementID uuid.UUID) ([]domain.Item, error) {
+	rows, err := q.Query(ctx, getStorage, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query settlement storage")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Item, error) {
+		var i domain.Item
+		err := row.Scan(&i.Kind, &i.ID, &i.Quantity)
+		return i, err
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan settlement storage")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return items, nil
+}
+
+func lockStock(ctx context.Context, tx pgx.Tx, settlementID uuid.UUID) (map[string]int, error) {
+	rows, err := tx.Query(ctx, lockResources, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to lock settlement resources")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	available := map[string]int{}
+	var (
+		id       string
+		quantity int
+	)
+	_, err = pgx.ForEachRow(rows, []any{&id, &quantity}, func() error {
+		available[id] = quantity
+		return nil
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read settlement resources")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return available, nil
+}
@@ -0,0 +1,194 @@
+package dice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+
+	"github.com/failuretoload/datamonster/dice/domain"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		Record(ctx context.Context, settlementID uuid.UUID, roll domain.Roll) (domain.Roll, error)
+		RecordApplying(ctx context.Context, settlementID uuid.UUID, roll domain.Roll, update survivordomain.SurvivorUpdate, rules survivordomain.MilestoneRules) (domain.Roll, survivordomain.Survivor, []survivordomain.Milestone, error)
+		History(ctx context.Context, settlementID uuid.UUID, q domain.HistoryQuery) (domain.HistoryPage, error)
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Glossary interface {
+		RollTable(id string) (glossary.RollTable, bool)
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+		glossary    Glossary
+		milestones  survivordomain.MilestoneRules
+	}
+	RollRequest struct {
+		Expression string     `json:"expression,omitempty"`
+		Table      string     `json:"table,omitempty"`
+		Survivor   *uuid.UUID `json:"survivor,omitempty"`
+	}
+	RollResult struct {
+		domain.Roll
+		Effects    []survivordomain.Effect    `json:"effects,omitempty"`
+		Survivor   *survivordomain.Survivor   `json:"survivor,omitempty"`
+		Milestones []survivordomain.Milestone `json:"milestones,omitempty"`
+	}
+)
+
+func NewController(r Repo, s Settlements, g Glossary, rules survivordomain.MilestoneRules) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("glossary cannot be nil")
+	}
+
+	return &Controller{records: r, settlements: s, glossary: g, milestones: rules}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Post("/settlements/{id}/rolls", c.roll)
+		gr.Get("/settlements/{id}/rolls", c.getHistory)
+	})
+}
+
+func (c Controller) roll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body RollRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	var table *glossary.RollTable
+	if body.Table != "" {
+		t, ok := c.glossary.RollTable(body.Table)
+		if !ok {
+			response.BadRequest(ctx, w, fmt.Errorf("unknown roll table: %s", body.Table))
+			return
+		}
+		table = &t
+	}
+
+	raw := body.Expression
+	if raw == "" && table != nil {
+		raw = table.Dice
+	}
+	if raw == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("expression or table is required"))
+		return
+	}
+	if body.Survivor != nil && table == nil {
+		response.BadRequest(ctx, w, fmt.Errorf("a table is required to resolve a roll against a survivor"))
+		return
+	}
+
+	expr, err := domain.ParseExpression(raw)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	settlement := request.Settlement(ctx)
+	roll := domain.NewRoll(expr, rand.Int64())
+	roll.Roller = request.UserID(ctx)
+	roll.Year = settlement.CurrentYear
+	roll.SurvivorID = body.Survivor
+
+	var result RollResult
+	if table != nil {
+		roll.TableID = &table.ID
+		if entry, ok := table.Lookup(roll.Total); ok {
+			roll.Result = &entry.Result
+			result.Effects = toEffects(entry.Effects)
+		}
+	}
+
+	if body.Survivor == nil {
+		result.Roll, err = c.records.Record(ctx, settlement.ID, roll)
+		if err != nil {
+			response.InternalServerError(ctx, w, fmt.Errorf("error recording roll: %w", err))
+			return
+		}
+
+		response.OK(ctx, w, result)
+		return
+	}
+
+	update, err := survivordomain.UpdateFromEffects(result.Effects)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("invalid roll table effects: %w", err))
+		return
+	}
+	update.RecordFate(roll.Year, roll.Roller)
+
+	var survivor survivordomain.Survivor
+	result.Roll, survivor, result.Milestones, err = c.records.RecordApplying(ctx, settlement.ID, roll, update, c.milestones)
+	if errors.Is(err, survivordomain.ErrSurvivorNotFound) {
+		response.NotFound(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error recording roll: %w", err))
+		return
+	}
+	result.Survivor = &survivor
+
+	response.OK(ctx, w, result)
+}
+
+func toEffects(effects []glossary.RollEffect) []survivordomain.Effect {
+	if len(effects) == 0 {
+		return nil
+	}
+
+	converted := make([]survivordomain.Effect, len(effects))
+	for i, e := range effects {
+		converted[i] = survivordomain.Effect{
+			Type:       e.Type,
+			Stat:       e.Stat,
+			Amount:     e.Amount,
+			Status:     survivordomain.SurvivorStatus(e.Status),
+			Impairment: e.Impairment,
+		}
+	}
+	return converted
+}
+
+func (c Controller) getHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+	q, err := domain.NewHistoryQuery(params.Get("limit"), params.Get("cursor"))
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	history, err := c.records.History(ctx, request.SettlementID(ctx), q)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving roll history: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, history)
+}
@@ -0,0 +1,386 @@
+package dice_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/dice"
+	"github.com/failuretoload/datamonster/dice/domain"
+	diceRepo "github.com/failuretoload/datamonster/dice/repo"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/survivor"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	survivorRepo "github.com/failuretoload/datamonster/survivor/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for dice tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryStub := testenv.NewGlossaryStub(`{"rollTables":[
+		{"id":"head","name":"Severe Head Injury","source":"core","dice":"1d10","entries":[
+			{"min":1,"max":5,"result":"Low"},
+			{"min":6,"max":10,"result":"High"}
+		]},
+		{"id":"gap","name":"Gapped","source":"core","dice":"1d10","entries":[
+			{"min":1,"max":1,"result":"One"}
+		]},
+		{"id":"arm","name":"Severe Arm Injury","source":"core","category":"severe injury","location":"arms","dice":"1d10","entries":[
+			{"min":1,"max":5,"result":"Broken Arm","effects":[
+				{"type":"stat","stat":"accuracy","amount":-1},
+				{"type":"stat","stat":"strength","amount":-1},
+				{"type":"impairment","impairment":"Broken Arm"}
+			]},
+			{"min":6,"max":10,"result":"Dismembered Arm","effects":[
+				{"type":"impairment","impairment":"Dismembered Arm"},
+				{"type":"status","status":"Cannot depart"}
+			]}
+		]},
+		{"id":"doom","name":"Doom","source":"core","dice":"1d10","entries":[
+			{"min":1,"max":10,"result":"Dead","effects":[
+				{"type":"stat","stat":"survival","amount":-100},
+				{"type":"status","status":"Dead"}
+			]}
+		]}
+	]}`)
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	survivorController, err := survivor.NewController(survivorRepo, settlementRepo, survivordomain.DefaultMilestoneRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rollRepo, err := diceRepo.New(dbContainer.PGPool, survivorRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	diceController, err := dice.NewController(rollRepo, settlementRepo, glossaryController, survivordomain.DefaultMilestoneRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, survivorController, diceController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func roll(t *testing.T, userID, settlementID, body string) domain.Roll {
+	raw, status := requester.Roll(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var result domain.Roll
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	return result
+}
+
+func resolve(t *testing.T, userID, settlementID, body string) dice.RollResult {
+	raw, status := requester.Roll(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var result dice.RollResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	return result
+}
+
+func createSurvivor(t *testing.T, userID, settlementID, name string) survivordomain.Survivor {
+	raw, status := requester.CreateSurvivor(userID, settlementID, name)
+	require.Equal(t, http.StatusOK, status)
+
+	var s survivordomain.Survivor
+	require.NoError(t, json.NewDecoder(raw).Decode(&s))
+	return s
+}
+
+func TestRoll_LoggedSeedReproducesRoll(t *testing.T) {
+	userID := "dice-seed-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	first := roll(t, userID, settlementID, `{"expression":"3d10+2"}`)
+
+	assert.Equal(t, "3d10+2", first.Expression)
+	assert.Len(t, first.Dice, 3)
+	assert.Equal(t, 2, first.Modifier)
+	assert.Equal(t, userID, first.Roller)
+	assert.Nil(t, first.TableID)
+
+	expr, err := domain.ParseExpression(first.Expression)
+	require.NoError(t, err)
+	replayed := domain.NewRoll(expr, first.Seed)
+	assert.Equal(t, first.Dice, replayed.Dice)
+	assert.Equal(t, first.Total, replayed.Total)
+}
+
+func TestRoll_RejectsClientSeed(t *testing.T) {
+	userID := "dice-client-seed-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.Roll(userID, settlementID, `{"expression":"3d10+2","seed":99}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestRoll_RecordsLanternYear(t *testing.T) {
+	userID := "dice-year-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	raw, status := requester.GetSettlement(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var s settlementdomain.Settlement
+	require.NoError(t, json.NewDecoder(raw).Decode(&s))
+
+	result := roll(t, userID, settlementID, `{"expression":"d100"}`)
+	assert.Equal(t, s.CurrentYear, result.Year)
+	assert.Equal(t, "1d100", result.Expression)
+	assert.GreaterOrEqual(t, result.Total, 1)
+	assert.LessOrEqual(t, result.Total, 100)
+}
+
+func TestRoll_ResolvesTable(t *testing.T) {
+	userID := "dice-table-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	result := roll(t, userID, settlementID, `{"table":"head"}`)
+	assert.Equal(t, "1d10", result.Expression)
+	require.NotNil(t, result.TableID)
+	assert.Equal(t, "head", *result.TableID)
+	require.NotNil(t, result.Result)
+	if result.Total <= 5 {
+		assert.Equal(t, "Low", *result.Result)
+	} else {
+		assert.Equal(t, "High", *result.Result)
+	}
+
+	result = roll(t, userID, settlementID, `{"table":"head","expression":"1d10+20"}`)
+	require.NotNil(t, result.Result)
+	assert.Equal(t, "High", *result.Result, "an explicit expression overrides the table dice")
+
+	result = roll(t, userID, settlementID, `{"table":"gap","expression":"1d2+1"}`)
+	assert.Nil(t, result.Result, "no entry covers the total")
+}
+
+func TestRoll_AppliesEffectsToSurvivor(t *testing.T) {
+	userID := "dice-effects-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	target := createSurvivor(t, userID, settlementID, "Injured")
+
+	result := resolve(t, userID, settlementID, `{"table":"arm","expression":"1d2","survivor":"`+target.ID.String()+`"}`)
+	require.NotNil(t, result.Result)
+	assert.Equal(t, "Broken Arm", *result.Result)
+	assert.Len(t, result.Effects, 3)
+	require.NotNil(t, result.Survivor)
+	assert.Equal(t, target.Accuracy-1, result.Survivor.Accuracy)
+	assert.Equal(t, target.Strength-1, result.Survivor.Strength)
+	assert.Equal(t, []string{"Broken Arm"}, result.Survivor.Impairments)
+	assert.Equal(t, survivordomain.StatusAlive, result.Survivor.Status)
+	require.NotNil(t, result.SurvivorID)
+	assert.Equal(t, target.ID, *result.SurvivorID)
+
+	result = resolve(t, userID, settlementID, `{"table":"arm","expression":"1d2","survivor":"`+target.ID.String()+`"}`)
+	assert.Equal(t, []string{"Broken Arm"}, result.Survivor.Impairments, "impairments are not duplicated")
+
+	result = resolve(t, userID, settlementID, `{"table":"arm","expression":"1d2+8","survivor":"`+target.ID.String()+`"}`)
+	require.NotNil(t, result.Result)
+	assert.Equal(t, "Dismembered Arm", *result.Result)
+	assert.Equal(t, []string{"Broken Arm", "Dismembered Arm"}, result.Survivor.Impairments)
+	assert.Equal(t, survivordomain.StatusCannotDepart, result.Survivor.Status)
+}
+
+func TestRoll_StatusAndClampedStats(t *testing.T) {
+	userID := "dice-doom-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	target := createSurvivor(t, userID, settlementID, "Doomed")
+
+	result := resolve(t, userID, settlementID, `{"table":"doom","survivor":"`+target.ID.String()+`"}`)
+	require.NotNil(t, result.Survivor)
+	assert.Equal(t, survivordomain.StatusDead, result.Survivor.Status)
+	assert.Equal(t, 0, result.Survivor.Survival)
+
+	raw, status := requester.GetMemorial(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var entries []survivordomain.MemorialEntry
+	require.NoError(t, json.NewDecoder(raw).Decode(&entries))
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Fate, "a roll that kills records the fate")
+	assert.Equal(t, userID, entries[0].Fate.RecordedBy)
+}
+
+func TestRoll_NoEntryLeavesSurvivorUnchanged(t *testing.T) {
+	userID := "dice-miss-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	target := createSurvivor(t, userID, settlementID, "Lucky")
+
+	result := resolve(t, userID, settlementID, `{"table":"gap","expression":"1d2+1","survivor":"`+target.ID.String()+`"}`)
+	assert.Nil(t, result.Result)
+	assert.Empty(t, result.Effects)
+	require.NotNil(t, result.Survivor)
+	assert.Equal(t, target.Accuracy, result.Survivor.Accuracy)
+	assert.Empty(t, result.Survivor.Impairments)
+}
+
+func TestRoll_SurvivorTargetErrors(t *testing.T) {
+	userID := "dice-target-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	target := createSurvivor(t, userID, settlementID, "Target")
+
+	_, status := requester.Roll(userID, settlementID, `{"expression":"1d10","survivor":"`+target.ID.String()+`"}`)
+	assert.Equal(t, http.StatusBadRequest, status, "a survivor roll needs a table")
+
+	_, status = requester.Roll(userID, settlementID, `{"table":"arm","survivor":"`+testenv.UUIDString()+`"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	other, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	_, status = requester.Roll(userID, other, `{"table":"arm","survivor":"`+target.ID.String()+`"}`)
+	assert.Equal(t, http.StatusNotFound, status, "survivors are scoped to the settlement")
+
+	raw, status := requester.GetRolls(userID, settlementID, "")
+	require.Equal(t, http.StatusOK, status)
+	var page domain.HistoryPage
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	assert.Empty(t, page.Rolls, "failed resolutions are not logged")
+}
+
+func TestRoll_History(t *testing.T) {
+	userID := "dice-history-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	first := roll(t, userID, settlementID, `{"expression":"1d10"}`)
+	second := roll(t, userID, settlementID, `{"table":"head"}`)
+
+	raw, status := requester.GetRolls(userID, settlementID, "")
+	require.Equal(t, http.StatusOK, status)
+
+	var page domain.HistoryPage
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	assert.Empty(t, page.NextCursor)
+	history := page.Rolls
+	require.Len(t, history, 2)
+	assert.Equal(t, first.Seed, history[0].Seed)
+	assert.Equal(t, first.Dice, history[0].Dice)
+	assert.Equal(t, first.Total, history[0].Total)
+	assert.Equal(t, userID, history[0].Roller)
+	assert.Equal(t, second.Seed, history[1].Seed)
+	require.NotNil(t, history[1].TableID)
+	assert.Equal(t, "head", *history[1].TableID)
+	assert.Equal(t, second.Result, history[1].Result)
+	assert.False(t, history[1].At.IsZero())
+
+	raw, status = requester.GetRolls(userID, settlementID, "?limit=1")
+	require.Equal(t, http.StatusOK, status)
+	page = domain.HistoryPage{}
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	require.Len(t, page.Rolls, 1)
+	assert.Equal(t, first.Seed, page.Rolls[0].Seed)
+	require.NotEmpty(t, page.NextCursor)
+
+	raw, status = requester.GetRolls(userID, settlementID, "?limit=1&cursor="+page.NextCursor)
+	require.Equal(t, http.StatusOK, status)
+	page = domain.HistoryPage{}
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	require.Len(t, page.Rolls, 1)
+	assert.Equal(t, second.Seed, page.Rolls[0].Seed)
+	assert.Empty(t, page.NextCursor)
+
+	_, status = requester.GetRolls(userID, settlementID, "?cursor=nope")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestRoll_BadRequests(t *testing.T) {
+	userID := "dice-invalid-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for _, body := range []string{
+		`{}`,
+		`{"expression":"banana"}`,
+		`{"expression":"1d1"}`,
+		`{"expression":"1000d10"}`,
+		`{"table":"missing"}`,
+		`{"expression":"1d10","extra":true}`,
+	} {
+		_, status := requester.Roll(userID, settlementID, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+
+	raw, status := requester.GetRolls(userID, settlementID, "")
+	require.Equal(t, http.StatusOK, status)
+	var page domain.HistoryPage
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	assert.Empty(t, page.Rolls)
+}
+
+func TestRoll_SettlementIsolation(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("dice-owner")
+	require.NoError(t, err)
+
+	_, status := requester.Roll("dice-intruder", settlementID, `{"expression":"1d10"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, status = requester.Roll("dice-intruder", settlementID, `{}`)
+	assert.Equal(t, http.StatusNotFound, status, "ownership is checked before the body")
+
+	_, status = requester.GetRolls("dice-intruder", settlementID, "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestRoll_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.Roll("unauthorized", testenv.UUIDString(), `{"expression":"1d10"}`)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
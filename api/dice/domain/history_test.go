@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistoryQuery(t *testing.T) {
+	q, err := NewHistoryQuery("", "")
+	require.NoError(t, err)
+	assert.Equal(t, HistoryQuery{Limit: DefaultHistoryLimit}, q)
+
+	q, err = NewHistoryQuery("10", EncodeCursor(42))
+	require.NoError(t, err)
+	assert.Equal(t, HistoryQuery{Limit: 10, After: 42}, q)
+
+	for _, bad := range [][2]string{{"0", ""}, {"501", ""}, {"ten", ""}, {"", "nope"}, {"", EncodeCursor(0)}} {
+		_, err := NewHistoryQuery(bad[0], bad[1])
+		assert.Error(t, err, bad)
+	}
+}
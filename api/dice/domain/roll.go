@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	MaxDice     = 100
+	MaxSides    = 1000
+	MaxModifier = 10000
+)
+
+var ErrInvalidExpression = errors.New("invalid dice expression")
+
+var expressionPattern = regexp.MustCompile(`^(\d*)d(\d+)(?:([+-])(\d+))?$`)
+
+type Expression struct {
+	Count    int
+	Sides    int
+	Modifier int
+}
+
+func ParseExpression(raw string) (Expression, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(raw, " ", ""))
+	m := expressionPattern.FindStringSubmatch(normalized)
+	if m == nil {
+		return Expression{}, fmt.Errorf("%w: %q", ErrInvalidExpression, raw)
+	}
+
+	e := Expression{Count: 1}
+	if m[1] != "" {
+		e.Count, _ = strconv.Atoi(m[1])
+	}
+	e.Sides, _ = strconv.Atoi(m[2])
+	if m[4] != "" {
+		e.Modifier, _ = strconv.Atoi(m[4])
+		if m[3] == "-" {
+			e.Modifier = -e.Modifier
+		}
+	}
+
+	if e.Count < 1 || e.Count > MaxDice {
+		return Expression{}, fmt.Errorf("%w: dice count must be between 1 and %d", ErrInvalidExpression, MaxDice)
+	}
+	if e.Sides < 2 || e.Sides > MaxSides {
+		return Expression{}, fmt.Errorf("%w: sides must be between 2 and %d", ErrInvalidExpression, MaxSides)
+	}
+	if e.Modifier < -MaxModifier || e.Modifier > MaxModifier {
+		return Expression{}, fmt.Errorf("%w: modifier must be between -%d and %d", ErrInvalidExpression, MaxModifier, MaxModifier)
+	}
+
+	return e, nil
+}
+
+func (e Expression) String() string {
+	switch {
+	case e.Modifier > 0:
+		return fmt.Sprintf("%dd%d+%d", e.Count, e.Sides, e.Modifier)
+	case e.Modifier < 0:
+		return fmt.Sprintf("%dd%d%d", e.Count, e.Sides, e.Modifier)
+	default:
+		return fmt.Sprintf("%dd%d", e.Count, e.Sides)
+	}
+}
+
+func (e Expression) Roll(seed int64) ([]int, int) {
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	dice := make([]int, e.Count)
+	total := e.Modifier
+	for i := range dice {
+		dice[i] = rng.IntN(e.Sides) + 1
+		total += dice[i]
+	}
+	return dice, total
+}
+
+type Roll struct {
+	Expression string     `json:"expression"`
+	Seed       int64      `json:"seed"`
+	Dice       []int      `json:"dice"`
+	Modifier   int        `json:"modifier"`
+	Total      int        `json:"total"`
+	Roller     string     `json:"roller"`
+	Year       int        `json:"year"`
+	TableID    *string    `json:"tableId,omitempty"`
+	Result     *string    `json:"result,omitempty"`
+	SurvivorID *uuid.UUID `json:"survivorId,omitempty"`
+	At         time.Time  `json:"at"`
+}
+
+func NewRoll(e Expression, seed int64) Roll {
+	dice, total := e.Roll(seed)
+	return Roll{
+		Expression: e.String(),
+		Seed:       seed,
+		Dice:       dice,
+		Modifier:   e.Modifier,
+		Total:      total,
+	}
+}
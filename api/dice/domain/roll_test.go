@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Expression
+	}{
+		{"d100", Expression{Count: 1, Sides: 100}},
+		{"1d10", Expression{Count: 1, Sides: 10}},
+		{"1d10+2", Expression{Count: 1, Sides: 10, Modifier: 2}},
+		{"2D6-1", Expression{Count: 2, Sides: 6, Modifier: -1}},
+		{" 3d10 + 4 ", Expression{Count: 3, Sides: 10, Modifier: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseExpression(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseExpressionRejectsInvalid(t *testing.T) {
+	for _, raw := range []string{"", "10", "d", "1d1", "0d10", "101d10", "1d1001", "1d10+", "1d10*2", "1d10+99999", "d10+d6"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := ParseExpression(raw)
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+		})
+	}
+}
+
+func TestExpressionString(t *testing.T) {
+	assert.Equal(t, "1d100", Expression{Count: 1, Sides: 100}.String())
+	assert.Equal(t, "2d10+3", Expression{Count: 2, Sides: 10, Modifier: 3}.String())
+	assert.Equal(t, "1d6-2", Expression{Count: 1, Sides: 6, Modifier: -2}.String())
+}
+
+func TestRollIsReproducible(t *testing.T) {
+	e := Expression{Count: 5, Sides: 10, Modifier: 2}
+
+	first := NewRoll(e, 42)
+	second := NewRoll(e, 42)
+	assert.Equal(t, first, second)
+
+	sum := e.Modifier
+	for _, d := range first.Dice {
+		assert.GreaterOrEqual(t, d, 1)
+		assert.LessOrEqual(t, d, 10)
+		sum += d
+	}
+	assert.Equal(t, sum, first.Total)
+	assert.Len(t, first.Dice, 5)
+}
+
+func TestRollVariesWithSeed(t *testing.T) {
+	e := Expression{Count: 10, Sides: 100}
+	assert.NotEqual(t, NewRoll(e, 1).Dice, NewRoll(e, 2).Dice)
+}
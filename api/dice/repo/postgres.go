@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/failuretoload/datamonster/dice/domain"
+	"github.com/failuretoload/datamonster/logger"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	insertRoll = `INSERT INTO settlement_roll_log (settlement_id, roller, year, expression, seed, dice, modifier, total, table_id, result, survivor_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING created_at`
+	getHistory = `SELECT id, expression, seed, dice, modifier, total, roller, year, table_id, result, survivor_id, created_at
+FROM settlement_roll_log
+WHERE settlement_id = $1 AND id > $2
+ORDER BY id
+LIMIT $3`
+)
+
+type Survivors interface {
+	UpdateTx(ctx context.Context, tx pgx.Tx, settlementID, survivorID uuid.UUID, updates survivordomain.SurvivorUpdate, rules survivordomain.MilestoneRules) (survivordomain.Survivor, []survivordomain.Milestone, error)
+}
+
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type Postgres struct {
+	db        *pgxpool.Pool
+	survivors Survivors
+}
+
+func New(p *pgxpool.Pool, survivors Survivors) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("dice repo: pgx connection pool is required")
+	}
+	if survivors == nil {
+		return nil, errors.New("dice repo: survivors are required")
+	}
+	return &Postgres{db: p, survivors: survivors}, nil
+}
+
+func (r Postgres) Record(ctx context.Context, settlementID uuid.UUID, roll domain.Roll) (domain.Roll, error) {
+	return recordRoll(ctx, r.db, settlementID, roll)
+}
+
+func (r Postgres) RecordApplying(ctx context.Context, settlementID uuid.UUID, roll domain.Roll, update survivordomain.SurvivorUpdate, rules survivordomain.MilestoneRules) (domain.Roll, survivordomain.Survivor, []survivordomain.Milestone, error) {
+	if roll.SurvivorID == nil {
+		return domain.Roll{}, survivordomain.Survivor{}, nil, errors.New("roll has no survivor")
+	}
+
+	var (
+		survivor   survivordomain.Survivor
+		milestones []survivordomain.Milestone
+	)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		survivor, milestones, err = r.survivors.UpdateTx(ctx, tx, settlementID, *roll.SurvivorID, update, rules)
+		if err != nil {
+			return err
+		}
+
+		roll, err = recordRoll(ctx, tx, settlementID, roll)
+		return err
+	})
+	if err != nil {
+		return domain.Roll{}, survivordomain.Survivor{}, nil, err
+	}
+
+	return roll, survivor, milestones, nil
+}
+
+func recordRoll(ctx context.Context, q querier, settlementID uuid.UUID, roll domain.Roll) (domain.Roll, error) {
+	err := q.QueryRow(ctx, insertRoll,
+		settlementID,
+		roll.Roller,
+		roll.Year,
+		roll.Expression,
+		roll.Seed,
+		roll.Dice,
+		roll.Modifier,
+		roll.Total,
+		roll.TableID,
+		roll.Result,
+		roll.SurvivorID,
+	).Scan(&roll.At)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to record roll")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Roll{}, safeErr
+	}
+
+	return roll, nil
+}
+
+func (r Postgres) History(ctx context.Context, settlementID uuid.UUID, q domain.HistoryQuery) (domain.HistoryPage, error) {
+	rows, err := r.db.Query(ctx, getHistory, settlementID, q.After, q.Limit+1)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query roll history")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.HistoryPage{}, safeErr
+	}
+
+	var ids []int64
+	rolls, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Roll, error) {
+		var (
+			id   int64
+			roll domain.Roll
+		)
+		err := row.Scan(
+			&id,
+			&roll.Expression,
+			&roll.Seed,
+			&roll.Dice,
+			&roll.Modifier,
+			&roll.Total,
+			&roll.Roller,
+			&roll.Year,
+			&roll.TableID,
+			&roll.Result,
+			&roll.SurvivorID,
+			&roll.At,
+		)
+		ids = append(ids, id)
+		return roll, err
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan roll history")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.HistoryPage{}, safeErr
+	}
+
+	page := domain.HistoryPage{Rolls: rolls}
+	if len(rolls) > q.Limit {
+		page.Rolls = rolls[:q.Limit]
+		page.NextCursor = domain.EncodeCursor(ids[q.Limit-1])
+	}
+
+	return page, nil
+}
@@ -0,0 +1,157 @@
+package endeavor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/failuretoload/datamonster/endeavor/domain"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		Ledger(ctx context.Context, settlementID uuid.UUID, year int) (domain.Ledger, error)
+		Record(ctx context.Context, settlementID uuid.UUID, e domain.Entry) (domain.Entry, domain.Ledger, error)
+		CloseDeparture(ctx context.Context, settlementID uuid.UUID, year int, phase string, ends bool, createdBy string) (domain.Departure, error)
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+	}
+	EntryRequest struct {
+		Kind   string `json:"kind"`
+		Amount int    `json:"amount"`
+		Source string `json:"source"`
+		Year   *int   `json:"year,omitempty"`
+	}
+	EntryResult struct {
+		Entry  domain.Entry  `json:"entry"`
+		Ledger domain.Ledger `json:"ledger"`
+	}
+	CloseDepartureRequest struct {
+		Phase           string `json:"phase"`
+		ShowdownFollows bool   `json:"showdownFollows"`
+	}
+)
+
+func NewController(r Repo, s Settlements) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+
+	return &Controller{records: r, settlements: s}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Get("/settlements/{id}/endeavors", c.getLedger)
+		gr.Post("/settlements/{id}/endeavors", c.recordEntry)
+		gr.Post("/settlements/{id}/departures/close", c.closeDeparture)
+	})
+}
+
+func (c Controller) getLedger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	settlement := request.Settlement(ctx)
+	year := settlement.CurrentYear
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			response.BadRequest(ctx, w, fmt.Errorf("invalid year"))
+			return
+		}
+		year = parsed
+	}
+
+	ledger, err := c.records.Ledger(ctx, settlement.ID, year)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving endeavor ledger: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, ledger)
+}
+
+func (c Controller) recordEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body EntryRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	settlement := request.Settlement(ctx)
+	year := settlement.CurrentYear
+	if body.Year != nil {
+		if *body.Year < 0 {
+			response.BadRequest(ctx, w, fmt.Errorf("invalid year"))
+			return
+		}
+		year = *body.Year
+	}
+
+	entry, err := domain.NewEntry(year, body.Kind, body.Amount, body.Source)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+	entry.CreatedBy = request.UserID(ctx)
+
+	entry, ledger, err := c.records.Record(ctx, settlement.ID, entry)
+	if errors.Is(err, domain.ErrInsufficientEndeavors) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error recording endeavor: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, EntryResult{Entry: entry, Ledger: ledger})
+}
+
+func (c Controller) closeDeparture(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body CloseDepartureRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+	if !domain.ValidPhase(body.Phase) {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid phase: %s", body.Phase))
+		return
+	}
+	if body.ShowdownFollows && body.Phase != domain.PhaseHunt {
+		response.BadRequest(ctx, w, fmt.Errorf("only a hunt can lead to a showdown"))
+		return
+	}
+
+	settlement := request.Settlement(ctx)
+	departure, err := c.records.CloseDeparture(ctx, settlement.ID, settlement.CurrentYear, body.Phase, domain.Ends(body.Phase, body.ShowdownFollows), request.UserID(ctx))
+	if errors.Is(err, domain.ErrNoDeparture) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error closing departure: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, departure)
+}
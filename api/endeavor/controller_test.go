@@ -0,0 +1,291 @@
+package endeavor_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/endeavor"
+	"github.com/failuretoload/datamonster/endeavor/domain"
+	endeavorRepo "github.com/failuretoload/datamonster/endeavor/repo"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/survivor"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	survivorRepo "github.com/failuretoload/datamonster/survivor/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for endeavor tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryStub := testenv.NewGlossaryStub(`{}`)
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	survivorController, err := survivor.NewController(survivorRepo, settlementRepo, survivordomain.DefaultMilestoneRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ledgerRepo, err := endeavorRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	endeavorController, err := endeavor.NewController(ledgerRepo, settlementRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, survivorController, endeavorController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func record(t *testing.T, userID, settlementID, body string) endeavor.EntryResult {
+	raw, status := requester.RecordEndeavor(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var result endeavor.EntryResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	return result
+}
+
+func ledger(t *testing.T, userID, settlementID, query string) domain.Ledger {
+	raw, status := requester.GetEndeavors(userID, settlementID, query)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var l domain.Ledger
+	require.NoError(t, json.NewDecoder(raw).Decode(&l))
+	return l
+}
+
+func depart(t *testing.T, userID, settlementID, name string, status survivordomain.SurvivorStatus) survivordomain.Survivor {
+	raw, code := requester.CreateSurvivor(userID, settlementID, name)
+	require.Equal(t, http.StatusOK, code)
+
+	var s survivordomain.Survivor
+	require.NoError(t, json.NewDecoder(raw).Decode(&s))
+
+	_, code = requester.UpdateSurvivor(userID, settlementID, s.ID.String(), `{"departing":true,"status":"`+string(status)+`"}`)
+	require.Equal(t, http.StatusOK, code)
+	return s
+}
+
+func TestEndeavors_EarnAndSpend(t *testing.T) {
+	userID := "endeavor-ledger-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	empty := ledger(t, userID, settlementID, "")
+	assert.Equal(t, 0, empty.Balance)
+	assert.Empty(t, empty.Entries)
+
+	earned := record(t, userID, settlementID, `{"kind":"earned","amount":3,"source":"Lantern Research"}`)
+	assert.Equal(t, domain.KindEarned, earned.Entry.Kind)
+	assert.Equal(t, userID, earned.Entry.CreatedBy)
+	assert.Equal(t, 3, earned.Ledger.Balance)
+
+	spent := record(t, userID, settlementID, `{"kind":"spent","amount":2,"source":"Innovate"}`)
+	assert.Equal(t, 1, spent.Ledger.Balance)
+
+	_, status := requester.RecordEndeavor(userID, settlementID, `{"kind":"spent","amount":2,"source":"Build Bone Smith"}`)
+	assert.Equal(t, http.StatusConflict, status)
+
+	l := ledger(t, userID, settlementID, "")
+	assert.Equal(t, 3, l.Earned)
+	assert.Equal(t, 2, l.Spent)
+	assert.Equal(t, 1, l.Balance)
+	require.Len(t, l.Entries, 2)
+	assert.Equal(t, "Lantern Research", l.Entries[0].Source)
+	assert.Equal(t, "Innovate", l.Entries[1].Source)
+}
+
+func TestEndeavors_SeparateYears(t *testing.T) {
+	userID := "endeavor-year-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	record(t, userID, settlementID, `{"kind":"earned","amount":4,"source":"Year five","year":5}`)
+
+	l := ledger(t, userID, settlementID, "year=5")
+	assert.Equal(t, 5, l.Year)
+	assert.Equal(t, 4, l.Balance)
+
+	_, status := requester.RecordEndeavor(userID, settlementID, `{"kind":"spent","amount":1,"source":"Other year","year":6}`)
+	assert.Equal(t, http.StatusConflict, status, "endeavors do not carry between years")
+}
+
+func TestEndeavors_BadRequests(t *testing.T) {
+	userID := "endeavor-invalid-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for _, body := range []string{
+		`{"kind":"borrowed","amount":1,"source":"x"}`,
+		`{"kind":"earned","amount":0,"source":"x"}`,
+		`{"kind":"earned","amount":1,"source":""}`,
+		`{"kind":"earned","amount":1,"source":"x","year":-1}`,
+		`{"kind":"earned","amount":1,"source":"x","extra":true}`,
+	} {
+		_, status := requester.RecordEndeavor(userID, settlementID, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+
+	_, status := requester.GetEndeavors(userID, settlementID, "year=soon")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestCloseDeparture_GrantsReturningSurvivors(t *testing.T) {
+	userID := "endeavor-showdown-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	first := depart(t, userID, settlementID, "First", survivordomain.StatusAlive)
+	second := depart(t, userID, settlementID, "Second", survivordomain.StatusCannotDepart)
+	fallen := depart(t, userID, settlementID, "Fallen", survivordomain.StatusDead)
+
+	raw, status := requester.CloseDeparture(userID, settlementID, `{"phase":"showdown"}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var result domain.Departure
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	assert.Equal(t, domain.PhaseShowdown, result.Phase)
+	assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, result.Returning)
+	require.Len(t, result.Lost, 1)
+	assert.Equal(t, fallen.ID, result.Lost[0])
+	require.NotNil(t, result.Grant)
+	assert.Equal(t, 2, result.Grant.Amount)
+	assert.Equal(t, "showdown: 2 returning survivors", result.Grant.Source)
+	assert.Equal(t, 2, result.Ledger.Balance)
+
+	raw, status = requester.GetSurvivors(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var page survivordomain.SurvivorPage
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	for _, s := range page.Survivors {
+		assert.False(t, s.Departing, s.Name)
+	}
+
+	_, status = requester.CloseDeparture(userID, settlementID, `{"phase":"showdown"}`)
+	assert.Equal(t, http.StatusConflict, status, "nobody is departing any more")
+}
+
+func TestCloseDeparture_HuntThenShowdown(t *testing.T) {
+	userID := "endeavor-hunt-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	hunter := depart(t, userID, settlementID, "Hunter", survivordomain.StatusAlive)
+	fallen := depart(t, userID, settlementID, "Ambushed", survivordomain.StatusDead)
+
+	raw, status := requester.CloseDeparture(userID, settlementID, `{"phase":"hunt","showdownFollows":true}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var result domain.Departure
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	assert.Empty(t, result.Returning)
+	assert.Equal(t, []uuid.UUID{hunter.ID}, result.Continuing)
+	assert.Equal(t, []uuid.UUID{fallen.ID}, result.Lost)
+	assert.Nil(t, result.Grant, "nobody is home until the showdown ends")
+
+	raw, status = requester.CloseDeparture(userID, settlementID, `{"phase":"showdown"}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	result = domain.Departure{}
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	assert.Equal(t, []uuid.UUID{hunter.ID}, result.Returning)
+	assert.Empty(t, result.Lost, "survivors lost on the hunt are not counted twice")
+	require.NotNil(t, result.Grant)
+	assert.Equal(t, 1, result.Grant.Amount)
+
+	_, status = requester.CloseDeparture(userID, settlementID, `{"phase":"showdown","showdownFollows":true}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestCloseDeparture_NoSurvivorsReturn(t *testing.T) {
+	userID := "endeavor-wipe-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	depart(t, userID, settlementID, "Lost", survivordomain.StatusDead)
+
+	raw, status := requester.CloseDeparture(userID, settlementID, `{"phase":"hunt"}`)
+	require.Equal(t, http.StatusOK, status)
+
+	var result domain.Departure
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	assert.Nil(t, result.Grant)
+	assert.Empty(t, result.Returning)
+	assert.Equal(t, 0, result.Ledger.Balance)
+}
+
+func TestCloseDeparture_InvalidPhase(t *testing.T) {
+	userID := "endeavor-phase-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.CloseDeparture(userID, settlementID, `{"phase":"nap"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestEndeavors_SettlementIsolation(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("endeavor-owner")
+	require.NoError(t, err)
+
+	_, status := requester.GetEndeavors("endeavor-intruder", settlementID, "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, status = requester.RecordEndeavor("endeavor-intruder", settlementID, `{"kind":"earned","amount":1,"source":"x"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, status = requester.CloseDeparture("endeavor-intruder", settlementID, `{"phase":"nap"}`)
+	assert.Equal(t, http.StatusNotFound, status, "ownership is checked before the body")
+}
+
+func TestEndeavors_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.GetEndeavors("unauthorized", testenv.UUIDString(), "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
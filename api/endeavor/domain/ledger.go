@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	KindEarned = "earned"
+	KindSpent  = "spent"
+
+	PhaseHunt     = "hunt"
+	PhaseShowdown = "showdown"
+
+	MaxEntryAmount = 100
+	MaxSourceLen   = 255
+)
+
+var (
+	ErrInsufficientEndeavors = errors.New("not enough endeavors available")
+	ErrNoDeparture           = errors.New("no survivors are departing")
+)
+
+type Entry struct {
+	ID        uuid.UUID `json:"id"`
+	Year      int       `json:"year"`
+	Kind      string    `json:"kind"`
+	Amount    int       `json:"amount"`
+	Source    string    `json:"source"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Ledger struct {
+	Year    int     `json:"year"`
+	Earned  int     `json:"earned"`
+	Spent   int     `json:"spent"`
+	Balance int     `json:"balance"`
+	Entries []Entry `json:"entries"`
+}
+
+type Departure struct {
+	Phase      string      `json:"phase"`
+	Returning  []uuid.UUID `json:"returning"`
+	Continuing []uuid.UUID `json:"continuing"`
+	Lost       []uuid.UUID `json:"lost"`
+	Grant      *Entry      `json:"grant,omitempty"`
+	Ledger     Ledger      `json:"ledger"`
+}
+
+func NewEntry(year int, kind string, amount int, source string) (Entry, error) {
+	if kind != KindEarned && kind != KindSpent {
+		return Entry{}, fmt.Errorf("invalid endeavor kind: %s", kind)
+	}
+	if amount < 1 || amount > MaxEntryAmount {
+		return Entry{}, fmt.Errorf("amount must be between 1 and %d", MaxEntryAmount)
+	}
+
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return Entry{}, fmt.Errorf("source is required")
+	}
+	if len(source) > MaxSourceLen {
+		return Entry{}, fmt.Errorf("source cannot exceed %d characters", MaxSourceLen)
+	}
+
+	return Entry{Year: year, Kind: kind, Amount: amount, Source: source}, nil
+}
+
+func NewLedger(year int, entries []Entry) Ledger {
+	l := Ledger{Year: year, Entries: []Entry{}}
+	for _, e := range entries {
+		if e.Year != year {
+			continue
+		}
+		switch e.Kind {
+		case KindEarned:
+			l.Earned += e.Amount
+		case KindSpent:
+			l.Spent += e.Amount
+		}
+		l.Entries = append(l.Entries, e)
+	}
+	l.Balance = l.Earned - l.Spent
+	return l
+}
+
+func (l Ledger) Allows(e Entry) error {
+	if e.Kind == KindSpent && e.Amount > l.Balance {
+		return fmt.Errorf("%w: %d requested, %d available", ErrInsufficientEndeavors, e.Amount, l.Balance)
+	}
+	return nil
+}
+
+func ValidPhase(phase string) bool {
+	return phase == PhaseHunt || phase == PhaseShowdown
+}
+
+func Ends(phase string, showdownFollows bool) bool {
+	return phase == PhaseShowdown || !showdownFollows
+}
+
+func Returns(status survivordomain.SurvivorStatus) bool {
+	return status == survivordomain.StatusAlive || status == survivordomain.StatusCannotDepart
+}
+
+func ReturnGrant(year int, phase string, returning int) (*Entry, error) {
+	if returning == 0 {
+		return nil, nil
+	}
+
+	noun := "survivors"
+	if returning == 1 {
+		noun = "survivor"
+	}
+	grant, err := NewEntry(year, KindEarned, returning, fmt.Sprintf("%s: %d returning %s", phase, returning, noun))
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
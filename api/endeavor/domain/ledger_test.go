@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEntry(t *testing.T) {
+	e, err := NewEntry(3, KindSpent, 2, "  Build Lantern Hoard ")
+	require.NoError(t, err)
+	assert.Equal(t, Entry{Year: 3, Kind: KindSpent, Amount: 2, Source: "Build Lantern Hoard"}, e)
+}
+
+func TestNewEntryRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		amount int
+		source string
+	}{
+		{"unknown kind", "borrowed", 1, "x"},
+		{"zero amount", KindEarned, 0, "x"},
+		{"negative amount", KindSpent, -1, "x"},
+		{"huge amount", KindEarned, MaxEntryAmount + 1, "x"},
+		{"blank source", KindEarned, 1, "   "},
+		{"long source", KindEarned, 1, strings.Repeat("a", MaxSourceLen+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEntry(1, tt.kind, tt.amount, tt.source)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewLedger(t *testing.T) {
+	entries := []Entry{
+		{Year: 1, Kind: KindEarned, Amount: 4},
+		{Year: 1, Kind: KindSpent, Amount: 1},
+		{Year: 2, Kind: KindEarned, Amount: 10},
+		{Year: 1, Kind: KindSpent, Amount: 2},
+	}
+
+	l := NewLedger(1, entries)
+	assert.Equal(t, 1, l.Year)
+	assert.Equal(t, 4, l.Earned)
+	assert.Equal(t, 3, l.Spent)
+	assert.Equal(t, 1, l.Balance)
+	assert.Len(t, l.Entries, 3)
+
+	empty := NewLedger(5, entries)
+	assert.Equal(t, 0, empty.Balance)
+	assert.NotNil(t, empty.Entries)
+}
+
+func TestLedgerAllows(t *testing.T) {
+	l := NewLedger(1, []Entry{{Year: 1, Kind: KindEarned, Amount: 2}})
+
+	assert.NoError(t, l.Allows(Entry{Kind: KindSpent, Amount: 2}))
+	assert.NoError(t, l.Allows(Entry{Kind: KindEarned, Amount: 50}))
+	assert.ErrorIs(t, l.Allows(Entry{Kind: KindSpent, Amount: 3}), ErrInsufficientEndeavors)
+}
+
+func TestReturns(t *testing.T) {
+	tests := []struct {
+		status survivordomain.SurvivorStatus
+		want   bool
+	}{
+		{survivordomain.StatusAlive, true},
+		{survivordomain.StatusCannotDepart, true},
+		{survivordomain.StatusDead, false},
+		{survivordomain.StatusCeasedToExist, false},
+		{survivordomain.StatusRetired, false},
+		{survivordomain.SurvivorStatus(""), false},
+		{survivordomain.SurvivorStatus("Sleeping"), false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Returns(tt.status), tt.status)
+	}
+}
+
+func TestEnds(t *testing.T) {
+	assert.True(t, Ends(PhaseShowdown, false))
+	assert.True(t, Ends(PhaseHunt, false))
+	assert.False(t, Ends(PhaseHunt, true))
+}
+
+func TestReturnGrant(t *testing.T) {
+	e, err := ReturnGrant(1, PhaseShowdown, 0)
+	require.NoError(t, err)
+	assert.Nil(t, e)
+
+	e, err = ReturnGrant(2, PhaseShowdown, 3)
+	require.NoError(t, err)
+	assert.Equal(t, &Entry{Year: 2, Kind: KindEarned, Amount: 3, Source: "showdown: 3 returning survivors"}, e)
+
+	e, err = ReturnGrant(2, PhaseHunt, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "hunt: 1 returning survivor", e.Source)
+
+	_, err = ReturnGrant(2, PhaseHunt, MaxEntryAmount+1)
+	assert.Error(t, err)
+}
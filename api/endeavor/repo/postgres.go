@@ -0,0 +1,211 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/failuretoload/datamonster/endeavor/domain"
+	"github.com/failuretoload/datamonster/logger"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	lockSettlement = `SELECT 1 FROM settlement WHERE external_id = $1 FOR UPDATE`
+	getEntries     = `SELECT external_id, year, kind, amount, source, created_by, created_at
+FROM settlement_endeavor
+WHERE settlement_id = $1 AND year = $2
+ORDER BY id`
+	insertEntry = `INSERT INTO settlement_endeavor (settlement_id, year, kind, amount, source, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING external_id, created_at`
+	getDeparting = `SELECT external_id, status FROM survivor
+WHERE settlement_id = $1 AND departing
+ORDER BY id
+FOR UPDATE`
+	clearDeparting = `UPDATE survivor SET departing = false
+WHERE settlement_id = $1 AND external_id = ANY($2)`
+)
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("endeavor repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) Ledger(ctx context.Context, settlementID uuid.UUID, year int) (domain.Ledger, error) {
+	return readLedger(ctx, r.db, settlementID, year)
+}
+
+func (r Postgres) Record(ctx context.Context, settlementID uuid.UUID, e domain.Entry) (domain.Entry, domain.Ledger, error) {
+	var ledger domain.Ledger
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		current, err := lockLedger(ctx, tx, settlementID, e.Year)
+		if err != nil {
+			return err
+		}
+		if err := current.Allows(e); err != nil {
+			return err
+		}
+
+		e, err = insert(ctx, tx, settlementID, e)
+		if err != nil {
+			return err
+		}
+
+		ledger = domain.NewLedger(e.Year, append(current.Entries, e))
+		return nil
+	})
+	if err != nil {
+		return domain.Entry{}, domain.Ledger{}, err
+	}
+
+	return e, ledger, nil
+}
+
+func (r Postgres) CloseDeparture(ctx context.Context, settlementID uuid.UUID, year int, phase string, ends bool, createdBy string) (domain.Departure, error) {
+	result := domain.Departure{Phase: phase, Returning: []uuid.UUID{}, Continuing: []uuid.UUID{}, Lost: []uuid.UUID{}}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		current, err := lockLedger(ctx, tx, settlementID, year)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, getDeparting, settlementID)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to read departing survivors")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		var (
+			id     uuid.UUID
+			status string
+		)
+		_, err = pgx.ForEachRow(rows, []any{&id, &status}, func() error {
+			switch {
+			case !domain.Returns(survivordomain.SurvivorStatus(status)):
+				result.Lost = append(result.Lost, id)
+			case ends:
+				result.Returning = append(result.Returning, id)
+			default:
+				result.Continuing = append(result.Continuing, id)
+			}
+			return nil
+		})
+		if err != nil {
+			safeErr := fmt.Errorf("unable to read departing survivors")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+		if len(result.Returning)+len(result.Continuing)+len(result.Lost) == 0 {
+			return domain.ErrNoDeparture
+		}
+
+		_, err = tx.Exec(ctx, clearDeparting, settlementID, slices.Concat(result.Returning, result.Lost))
+		if err != nil {
+			safeErr := fmt.Errorf("unable to close departure")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		result.Ledger = current
+		returned, err := domain.ReturnGrant(year, phase, len(result.Returning))
+		if err != nil || returned == nil {
+			return err
+		}
+
+		returned.CreatedBy = createdBy
+		grant, err := insert(ctx, tx, settlementID, *returned)
+		if err != nil {
+			return err
+		}
+
+		result.Grant = &grant
+		result.Ledger = domain.NewLedger(year, append(current.Entries, grant))
+		return nil
+	})
+	if err != nil {
+		return domain.Departure{}, err
+	}
+
+	return result, nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func lockLedger(ctx context.Context, tx pgx.Tx, settlementID uuid.UUID, year int) (domain.Ledger, error) {
+	_, err := tx.Exec(ctx, lockSettlement, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to lock settlement")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Ledger{}, safeErr
+	}
+
+	return readLedger(ctx, tx, settlementID, year)
+}
+
+func readLedger(ctx context.Context, q querier, settlementID uuid.UUID, year int) (domain.Ledger, error) {
+	rows, err := q.Query(ctx, getEntries, settlementID, year)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query endeavor ledger")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Ledger{}, safeErr
+	}
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Entry, error) {
+		var e domain.Entry
+		err := row.Scan(&e.ID, &e.Year, &e.Kind, &e.Amount, &e.Source, &e.CreatedBy, &e.CreatedAt)
+		return e, err
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan endeavor ledger")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Ledger{}, safeErr
+	}
+
+	return domain.NewLedger(year, entries), nil
+}
+
+func insert(ctx context.Context, tx pgx.Tx, settlementID uuid.UUID, e domain.Entry) (domain.Entry, error) {
+	err := tx.QueryRow(ctx, insertEntry, settlementID, e.Year, e.Kind, e.Amount, e.Source, e.CreatedBy).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to record endeavor")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Entry{}, safeErr
+	}
+
+	return e, nil
+}
@@ -0,0 +1,196 @@
+package eventdeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+
+	"github.com/failuretoload/datamonster/eventdeck/domain"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		Get(ctx context.Context, settlementID uuid.UUID) (domain.Deck, error)
+		Reset(ctx context.Context, settlementID uuid.UUID, d domain.Deck) (domain.Deck, error)
+		Apply(ctx context.Context, settlementID uuid.UUID, action func(d *domain.Deck) (domain.Entry, error)) (domain.Deck, domain.Entry, error)
+		History(ctx context.Context, settlementID uuid.UUID, q domain.HistoryQuery) (domain.HistoryPage, error)
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Glossary interface {
+		SettlementEvents() []glossary.SettlementEvent
+		SettlementEvent(id string) (glossary.SettlementEvent, bool)
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+		glossary    Glossary
+	}
+	DrawResult struct {
+		Deck domain.DeckView          `json:"deck"`
+		Card glossary.SettlementEvent `json:"card"`
+	}
+)
+
+func NewController(r Repo, s Settlements, g Glossary) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("glossary cannot be nil")
+	}
+
+	return &Controller{records: r, settlements: s, glossary: g}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Get("/settlements/{id}/events/deck", c.getDeck)
+		gr.Post("/settlements/{id}/events/deck", c.shuffleDeck)
+		gr.Post("/settlements/{id}/events/deck/draw", c.draw)
+		gr.Post("/settlements/{id}/events/deck/discard", c.discard)
+		gr.Post("/settlements/{id}/events/deck/reshuffle", c.reshuffle)
+		gr.Get("/settlements/{id}/events/history", c.getHistory)
+	})
+}
+
+func (c Controller) getDeck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	deck, err := c.records.Get(ctx, request.SettlementID(ctx))
+	if err != nil {
+		writeDeckError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, deck.View())
+}
+
+func (c Controller) shuffleDeck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	// The seed is always chosen here so a logged shuffle cannot have been
+	// picked by the player.
+	if n, _ := io.ReadFull(r.Body, make([]byte, 1)); n > 0 {
+		response.BadRequest(ctx, w, fmt.Errorf("shuffle does not accept a request body"))
+		return
+	}
+
+	events := c.glossary.SettlementEvents()
+	cards := make([]string, len(events))
+	for i, e := range events {
+		cards[i] = e.ID
+	}
+
+	deck, err := domain.NewDeck(cards, rand.Int64())
+	if err != nil {
+		response.Conflict(ctx, w, err)
+		return
+	}
+
+	deck, err = c.records.Reset(ctx, request.SettlementID(ctx), deck)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error shuffling settlement event deck: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, deck.View())
+}
+
+func (c Controller) draw(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	deck, entry, err := c.records.Apply(ctx, request.SettlementID(ctx), func(d *domain.Deck) (domain.Entry, error) {
+		card, err := d.Draw()
+		if err != nil {
+			return domain.Entry{}, err
+		}
+		return d.Entry(domain.ActionDraw, &card), nil
+	})
+	if err != nil {
+		writeDeckError(ctx, w, err)
+		return
+	}
+
+	card, ok := c.glossary.SettlementEvent(*entry.CardID)
+	if !ok {
+		card = glossary.SettlementEvent{ID: *entry.CardID}
+	}
+
+	response.OK(ctx, w, DrawResult{Deck: deck.View(), Card: card})
+}
+
+func (c Controller) discard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	deck, _, err := c.records.Apply(ctx, request.SettlementID(ctx), func(d *domain.Deck) (domain.Entry, error) {
+		card, err := d.DiscardCurrent()
+		if err != nil {
+			return domain.Entry{}, err
+		}
+		return d.Entry(domain.ActionDiscard, &card), nil
+	})
+	if err != nil {
+		writeDeckError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, deck.View())
+}
+
+func (c Controller) reshuffle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	deck, _, err := c.records.Apply(ctx, request.SettlementID(ctx), func(d *domain.Deck) (domain.Entry, error) {
+		d.Reshuffle()
+		return d.Entry(domain.ActionReshuffle, nil), nil
+	})
+	if err != nil {
+		writeDeckError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, deck.View())
+}
+
+func (c Controller) getHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+	q, err := domain.NewHistoryQuery(params.Get("limit"), params.Get("cursor"))
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	history, err := c.records.History(ctx, request.SettlementID(ctx), q)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving settlement event history: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, history)
+}
+
+func writeDeckError(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrDeckNotFound):
+		response.NotFound(ctx, w, err)
+	case errors.Is(err, domain.ErrDeckEmpty),
+		errors.Is(err, domain.ErrCardInPlay),
+		errors.Is(err, domain.ErrNoCardInPlay):
+		response.Conflict(ctx, w, err)
+	default:
+		response.InternalServerError(ctx, w, err)
+	}
+}
@@ -0,0 +1,250 @@
+package eventdeck_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/eventdeck"
+	"github.com/failuretoload/datamonster/eventdeck/domain"
+	eventdeckRepo "github.com/failuretoload/datamonster/eventdeck/repo"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for event deck tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryStub := testenv.NewGlossaryStub(`{"settlementEvents":[
+		{"id":"event-a","name":"Heat Wave","source":"core","text":["Hot."]},
+		{"id":"event-b","name":"Murmurs","source":"core","text":["Quiet."]},
+		{"id":"event-c","name":"Strange Spot","source":"core","text":["Odd."]}
+	]}`)
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	deckRepo, err := eventdeckRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	deckController, err := eventdeck.NewController(deckRepo, settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, deckController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func shuffle(t *testing.T, userID, settlementID, body string) domain.DeckView {
+	raw, status := requester.ShuffleEventDeck(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	var view domain.DeckView
+	require.NoError(t, json.NewDecoder(raw).Decode(&view))
+	return view
+}
+
+func draw(t *testing.T, userID, settlementID string) eventdeck.DrawResult {
+	raw, status := requester.EventDeckAction(userID, settlementID, "draw")
+	require.Equal(t, http.StatusOK, status)
+
+	var result eventdeck.DrawResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	return result
+}
+
+func drawAll(t *testing.T, userID, settlementID string) []string {
+	var order []string
+	for range 3 {
+		result := draw(t, userID, settlementID)
+		order = append(order, result.Card.ID)
+
+		_, status := requester.EventDeckAction(userID, settlementID, "discard")
+		require.Equal(t, http.StatusOK, status)
+	}
+	return order
+}
+
+func TestShuffle_LoggedSeedReproducesDeck(t *testing.T) {
+	userID := "deck-seed-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	view := shuffle(t, userID, settlementID, "")
+	assert.Equal(t, 3, view.Remaining)
+
+	deck, err := domain.NewDeck([]string{"event-a", "event-b", "event-c"}, view.Seed)
+	require.NoError(t, err)
+	var want []string
+	for range 3 {
+		card, err := deck.Draw()
+		require.NoError(t, err)
+		want = append(want, card)
+		_, err = deck.DiscardCurrent()
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, want, drawAll(t, userID, settlementID))
+}
+
+func TestShuffle_RejectsBody(t *testing.T) {
+	userID := "deck-client-seed-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.ShuffleEventDeck(userID, settlementID, `{"seed":1234}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.ShuffleEventDeck(userID, settlementID, `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.ShuffleEventDeckChunked(userID, settlementID, `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.ShuffleEventDeckChunked(userID, settlementID, "")
+	assert.Equal(t, http.StatusOK, status, "an empty body of unknown length is accepted")
+}
+
+func TestDeck_DrawDiscardReshuffle(t *testing.T) {
+	userID := "deck-cycle-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	shuffle(t, userID, settlementID, "")
+
+	result := draw(t, userID, settlementID)
+	assert.NotEmpty(t, result.Card.Name)
+	require.NotNil(t, result.Deck.Current)
+	assert.Equal(t, result.Card.ID, *result.Deck.Current)
+	assert.Equal(t, 2, result.Deck.Remaining)
+
+	_, status := requester.EventDeckAction(userID, settlementID, "draw")
+	assert.Equal(t, http.StatusConflict, status, "a card is already in play")
+
+	_, status = requester.EventDeckAction(userID, settlementID, "discard")
+	require.Equal(t, http.StatusOK, status)
+	for range 2 {
+		draw(t, userID, settlementID)
+		_, status = requester.EventDeckAction(userID, settlementID, "discard")
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	_, status = requester.EventDeckAction(userID, settlementID, "draw")
+	assert.Equal(t, http.StatusConflict, status, "the deck is empty")
+
+	raw, status := requester.EventDeckAction(userID, settlementID, "reshuffle")
+	require.Equal(t, http.StatusOK, status)
+
+	var view domain.DeckView
+	require.NoError(t, json.NewDecoder(raw).Decode(&view))
+	assert.Equal(t, 1, view.Shuffles)
+	assert.Equal(t, 3, view.Remaining)
+	assert.Empty(t, view.DiscardPile)
+
+	raw, status = requester.GetEventHistory(userID, settlementID, "")
+	require.Equal(t, http.StatusOK, status)
+
+	var page domain.HistoryPage
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	assert.Empty(t, page.NextCursor)
+	history := page.Entries
+	require.Len(t, history, 8)
+	assert.Equal(t, domain.ActionShuffle, history[0].Action)
+	assert.Equal(t, domain.ActionDraw, history[1].Action)
+	assert.Equal(t, result.Card.ID, *history[1].CardID)
+	assert.Equal(t, domain.ActionReshuffle, history[7].Action)
+
+	var paged []domain.Entry
+	query := "?limit=3"
+	for {
+		raw, status = requester.GetEventHistory(userID, settlementID, query)
+		require.Equal(t, http.StatusOK, status)
+		page = domain.HistoryPage{}
+		require.NoError(t, json.NewDecoder(raw).Decode(&page))
+		require.LessOrEqual(t, len(page.Entries), 3)
+		paged = append(paged, page.Entries...)
+		if page.NextCursor == "" {
+			break
+		}
+		query = "?limit=3&cursor=" + page.NextCursor
+	}
+	assert.Equal(t, history, paged)
+
+	for _, query := range []string{"?limit=0", "?limit=501", "?cursor=nope"} {
+		_, status = requester.GetEventHistory(userID, settlementID, query)
+		assert.Equal(t, http.StatusBadRequest, status, query)
+	}
+}
+
+func TestDeck_NotShuffled(t *testing.T) {
+	userID := "deck-missing-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.GetEventDeck(userID, settlementID)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, status = requester.EventDeckAction(userID, settlementID, "draw")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestDeck_DiscardWithoutDraw(t *testing.T) {
+	userID := "deck-discard-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	shuffle(t, userID, settlementID, "")
+
+	_, status := requester.EventDeckAction(userID, settlementID, "discard")
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestDeck_SettlementIsolation(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("deck-owner")
+	require.NoError(t, err)
+
+	_, status := requester.ShuffleEventDeck("deck-intruder", settlementID, "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestDeck_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.GetEventDeck("unauthorized", testenv.UUIDString())
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
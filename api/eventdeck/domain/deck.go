@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+var (
+	ErrDeckNotFound = errors.New("settlement event deck not found")
+	ErrNoCards      = errors.New("no settlement events to build a deck from")
+	ErrDeckEmpty    = errors.New("settlement event deck is empty")
+	ErrCardInPlay   = errors.New("a settlement event is already in play")
+	ErrNoCardInPlay = errors.New("no settlement event is in play")
+)
+
+const (
+	ActionShuffle   = "shuffle"
+	ActionDraw      = "draw"
+	ActionDiscard   = "discard"
+	ActionReshuffle = "reshuffle"
+)
+
+type Deck struct {
+	Seed     int64
+	Shuffles int
+	DrawPile []string
+	Discard  []string
+	Current  *string
+}
+
+type DeckView struct {
+	Seed        int64    `json:"seed"`
+	Shuffles    int      `json:"shuffles"`
+	Remaining   int      `json:"remaining"`
+	DiscardPile []string `json:"discardPile"`
+	Current     *string  `json:"current"`
+}
+
+type Entry struct {
+	Action   string    `json:"action"`
+	CardID   *string   `json:"cardId,omitempty"`
+	Seed     int64     `json:"seed"`
+	Shuffles int       `json:"shuffles"`
+	At       time.Time `json:"at"`
+}
+
+func NewDeck(cards []string, seed int64) (Deck, error) {
+	if len(cards) == 0 {
+		return Deck{}, ErrNoCards
+	}
+
+	return Deck{
+		Seed:     seed,
+		DrawPile: shuffle(cards, seed, 0),
+		Discard:  []string{},
+	}, nil
+}
+
+func (d *Deck) Draw() (string, error) {
+	if d.Current != nil {
+		return "", ErrCardInPlay
+	}
+	if len(d.DrawPile) == 0 {
+		return "", ErrDeckEmpty
+	}
+
+	card := d.DrawPile[0]
+	d.DrawPile = d.DrawPile[1:]
+	d.Current = &card
+	return card, nil
+}
+
+func (d *Deck) DiscardCurrent() (string, error) {
+	if d.Current == nil {
+		return "", ErrNoCardInPlay
+	}
+
+	card := *d.Current
+	d.Discard = append(d.Discard, card)
+	d.Current = nil
+	return card, nil
+}
+
+func (d *Deck) Reshuffle() {
+	d.Shuffles++
+	d.DrawPile = shuffle(append(slices.Clone(d.DrawPile), d.Discard...), d.Seed, d.Shuffles)
+	d.Discard = []string{}
+}
+
+func (d Deck) Entry(action string, card *string) Entry {
+	return Entry{Action: action, CardID: card, Seed: d.Seed, Shuffles: d.Shuffles}
+}
+
+func (d Deck) View() DeckView {
+	discard := d.Discard
+	if discard == nil {
+		discard = []string{}
+	}
+
+	return DeckView{
+		Seed:        d.Seed,
+		Shuffles:    d.Shuffles,
+		Remaining:   len(d.DrawPile),
+		DiscardPile: discard,
+		Current:     d.Current,
+	}
+}
+
+func shuffle(cards []string, seed int64, round int) []string {
+	out := slices.Clone(cards)
+	slices.Sort(out)
+
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(round)))
+	rng.Shuffle(len(out), func(i, j int) {
+		out[i], out[j] = out[j], out[i]
+	})
+	return out
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cards = []string{"a", "b", "c", "d", "e", "f"}
+
+func TestNewDeck_IsReproducible(t *testing.T) {
+	first, err := NewDeck(cards, 42)
+	require.NoError(t, err)
+
+	second, err := NewDeck([]string{"f", "e", "d", "c", "b", "a"}, 42)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.DrawPile, second.DrawPile)
+	assert.ElementsMatch(t, cards, first.DrawPile)
+}
+
+func TestNewDeck_NoCards(t *testing.T) {
+	_, err := NewDeck(nil, 1)
+	assert.ErrorIs(t, err, ErrNoCards)
+}
+
+func TestDeck_DrawAndDiscard(t *testing.T) {
+	d, err := NewDeck(cards, 7)
+	require.NoError(t, err)
+	top := d.DrawPile[0]
+
+	card, err := d.Draw()
+	require.NoError(t, err)
+	assert.Equal(t, top, card)
+	assert.Len(t, d.DrawPile, len(cards)-1)
+
+	_, err = d.Draw()
+	assert.ErrorIs(t, err, ErrCardInPlay)
+
+	discarded, err := d.DiscardCurrent()
+	require.NoError(t, err)
+	assert.Equal(t, card, discarded)
+	assert.Equal(t, []string{card}, d.Discard)
+	assert.Nil(t, d.Current)
+
+	_, err = d.DiscardCurrent()
+	assert.ErrorIs(t, err, ErrNoCardInPlay)
+}
+
+func TestDeck_EmptyAndReshuffle(t *testing.T) {
+	d, err := NewDeck(cards[:2], 7)
+	require.NoError(t, err)
+
+	for range 2 {
+		_, err := d.Draw()
+		require.NoError(t, err)
+		_, err = d.DiscardCurrent()
+		require.NoError(t, err)
+	}
+
+	_, err = d.Draw()
+	assert.ErrorIs(t, err, ErrDeckEmpty)
+
+	d.Reshuffle()
+	assert.Equal(t, 1, d.Shuffles)
+	assert.Empty(t, d.Discard)
+	assert.ElementsMatch(t, cards[:2], d.DrawPile)
+
+	replay, err := NewDeck(cards[:2], 7)
+	require.NoError(t, err)
+	replay.DrawPile = nil
+	replay.Discard = []string{d.DrawPile[1], d.DrawPile[0]}
+	replay.Reshuffle()
+	assert.Equal(t, d.DrawPile, replay.DrawPile)
+}
+
+func TestDeck_View(t *testing.T) {
+	d, err := NewDeck(cards, 3)
+	require.NoError(t, err)
+	_, err = d.Draw()
+	require.NoError(t, err)
+
+	view := d.View()
+	assert.Equal(t, int64(3), view.Seed)
+	assert.Equal(t, len(cards)-1, view.Remaining)
+	assert.NotNil(t, view.Current)
+	assert.NotNil(t, view.DiscardPile)
+}
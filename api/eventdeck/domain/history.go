@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+const (
+	DefaultHistoryLimit = 100
+	MaxHistoryLimit     = 500
+)
+
+type HistoryQuery struct {
+	Limit int
+	After int64
+}
+
+type HistoryPage struct {
+	Entries    []Entry `json:"entries"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+func NewHistoryQuery(limit, cursor string) (HistoryQuery, error) {
+	q := HistoryQuery{Limit: DefaultHistoryLimit}
+
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxHistoryLimit {
+			return HistoryQuery{}, fmt.Errorf("limit must be between 1 and %d", MaxHistoryLimit)
+		}
+		q.Limit = n
+	}
+
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return HistoryQuery{}, err
+		}
+		q.After = after
+	}
+
+	return q, nil
+}
+
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func DecodeCursor(token string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	return id, nil
+}
@@ -0,0 +1,203 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/failuretoload/datamonster/eventdeck/domain"
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	getDeck       = `SELECT * FROM settlement_event_deck WHERE settlement_id = $1`
+	getDeckLocked = `SELECT * FROM settlement_event_deck WHERE settlement_id = $1 FOR UPDATE`
+	upsertDeck    = `INSERT INTO settlement_event_deck (settlement_id, seed, shuffles, draw_pile, discard_pile, current_card)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (settlement_id) DO UPDATE SET
+	seed = EXCLUDED.seed,
+	shuffles = EXCLUDED.shuffles,
+	draw_pile = EXCLUDED.draw_pile,
+	discard_pile = EXCLUDED.discard_pile,
+	current_card = EXCLUDED.current_card,
+	updated_at = NOW()`
+	insertEntry = `INSERT INTO settlement_event_log (settlement_id, action, card_id, seed, shuffles)
+VALUES ($1, $2, $3, $4, $5)`
+	getHistory = `SELECT id, action, card_id, seed, shuffles, created_at FROM settlement_event_log
+WHERE settlement_id = $1 AND id > $2
+ORDER BY id
+LIMIT $3`
+)
+
+type deck struct {
+	SettlementID uuid.UUID `db:"settlement_id"`
+	Seed         int64     `db:"seed"`
+	Shuffles     int       `db:"shuffles"`
+	DrawPile     []string  `db:"draw_pile"`
+	DiscardPile  []string  `db:"discard_pile"`
+	CurrentCard  *string   `db:"current_card"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("event deck repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) Get(ctx context.Context, settlementID uuid.UUID) (domain.Deck, error) {
+	return readDeck(ctx, r.db, getDeck, settlementID)
+}
+
+func (r Postgres) Reset(ctx context.Context, settlementID uuid.UUID, d domain.Deck) (domain.Deck, error) {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return save(ctx, tx, settlementID, d, d.Entry(domain.ActionShuffle, nil))
+	})
+	if err != nil {
+		return domain.Deck{}, err
+	}
+
+	return d, nil
+}
+
+func (r Postgres) Apply(ctx context.Context, settlementID uuid.UUID, action func(d *domain.Deck) (domain.Entry, error)) (domain.Deck, domain.Entry, error) {
+	var (
+		result domain.Deck
+		entry  domain.Entry
+	)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		current, err := readDeck(ctx, tx, getDeckLocked, settlementID)
+		if err != nil {
+			return err
+		}
+
+		entry, err = action(&current)
+		if err != nil {
+			return err
+		}
+
+		result = current
+		return save(ctx, tx, settlementID, current, entry)
+	})
+	if err != nil {
+		return domain.Deck{}, domain.Entry{}, err
+	}
+
+	return result, entry, nil
+}
+
+func (r Postgres) History(ctx context.Context, settlementID uuid.UUID, q domain.HistoryQuery) (domain.HistoryPage, error) {
+	rows, err := r.db.Query(ctx, getHistory, settlementID, q.After, q.Limit+1)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query settlement event history")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.HistoryPage{}, safeErr
+	}
+
+	var ids []int64
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Entry, error) {
+		var (
+			id int64
+			e  domain.Entry
+		)
+		err := row.Scan(&id, &e.Action, &e.CardID, &e.Seed, &e.Shuffles, &e.At)
+		ids = append(ids, id)
+		return e, err
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan settlement event history")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.HistoryPage{}, safeErr
+	}
+
+	page := domain.HistoryPage{Entries: entries}
+	if len(entries) > q.Limit {
+		page.Entries = entries[:q.Limit]
+		page.NextCursor = domain.EncodeCursor(ids[q.Limit-1])
+	}
+
+	return page, nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func readDeck(ctx context.Context, q querier, query string, settlementID uuid.UUID) (domain.Deck, error) {
+	rows, err := q.Query(ctx, query, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query settlement event deck")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Deck{}, safeErr
+	}
+
+	d, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[deck])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Deck{}, domain.ErrDeckNotFound
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read settlement event deck")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Deck{}, safeErr
+	}
+
+	return domain.Deck{
+		Seed:     d.Seed,
+		Shuffles: d.Shuffles,
+		DrawPile: d.DrawPile,
+		Discard:  d.DiscardPile,
+		Current:  d.CurrentCard,
+	}, nil
+}
+
+func save(ctx context.Context, tx pgx.Tx, settlementID uuid.UUID, d domain.Deck, entry domain.Entry) error {
+	_, err := tx.Exec(ctx, upsertDeck, settlementID, d.Seed, d.Shuffles, orEmpty(d.DrawPile), orEmpty(d.Discard), d.Current)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to save settlement event deck")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+
+	_, err = tx.Exec(ctx, insertEntry, settlementID, entry.Action, entry.CardID, entry.Seed, entry.Shuffles)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to log settlement event")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+
+	return nil
+}
+
+func orEmpty(cards []string) []string {
+	if cards == nil {
+		return []string{}
+	}
+	return cards
+}
@@ -39,7 +39,7 @@ func (fa fightingArt) Key() string {
 	return fa.ID
 }
 
-type innovation struct {
+type Innovation struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Source   string `json:"source"`
@@ -47,11 +47,11 @@ type innovation struct {
 	Parent   string `json:"parent,omitempty"`
 }
 
-func (i innovation) Key() string {
+func (i Innovation) Key() string {
 	return i.ID
 }
 
-type knowledge struct {
+type Knowledge struct {
 	ID           string   `json:"id"`
 	Name         string   `json:"name"`
 	Cost         int      `json:"cost"`
@@ -64,28 +64,204 @@ type knowledge struct {
 	Activation   string   `json:"activation,omitempty"`
 }
 
-func (k knowledge) Key() string {
+func (k Knowledge) Key() string {
 	return k.ID
 }
 
+type SettlementEvent struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Source string   `json:"source"`
+	Text   []string `json:"text"`
+}
+
+func (e SettlementEvent) Key() string {
+	return e.ID
+}
+
+type RollEffect struct {
+	Type       string `json:"type"`
+	Stat       string `json:"stat,omitempty"`
+	Amount     int    `json:"amount,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Impairment string `json:"impairment,omitempty"`
+}
+
+type RollTableEntry struct {
+	Min     int          `json:"min"`
+	Max     int          `json:"max"`
+	Result  string       `json:"result"`
+	Effects []RollEffect `json:"effects,omitempty"`
+}
+
+type RollTable struct {
+	ID       string           `json:"id"`
+	Name     string           `json:"name"`
+	Source   string           `json:"source"`
+	Category string           `json:"category,omitempty"`
+	Location string           `json:"location,omitempty"`
+	Dice     string           `json:"dice"`
+	Entries  []RollTableEntry `json:"entries"`
+}
+
+func (t RollTable) Key() string {
+	return t.ID
+}
+
+func (t RollTable) Lookup(total int) (RollTableEntry, bool) {
+	for _, e := range t.Entries {
+		if total >= e.Min && total <= e.Max {
+			return e, true
+		}
+	}
+	return RollTableEntry{}, false
+}
+
+type CampaignTimelineEntry struct {
+	Year  int    `json:"year"`
+	Event string `json:"event"`
+}
+
+type CampaignSurvivor struct {
+	Name     string `json:"name"`
+	Gender   string `json:"gender"`
+	Survival int    `json:"survival"`
+	Movement int    `json:"movement"`
+	Accuracy int    `json:"accuracy,omitempty"`
+	Strength int    `json:"strength,omitempty"`
+	Evasion  int    `json:"evasion,omitempty"`
+	Luck     int    `json:"luck,omitempty"`
+	Speed    int    `json:"speed,omitempty"`
+}
+
+type Campaign struct {
+	ID                string                  `json:"id"`
+	Name              string                  `json:"name"`
+	Source            string                  `json:"source"`
+	SurvivalLimit     int                     `json:"survivalLimit"`
+	DepartingSurvival int                     `json:"departingSurvival"`
+	Innovations       []string                `json:"innovations"`
+	Timeline          []CampaignTimelineEntry `json:"timeline"`
+	Survivors         []CampaignSurvivor      `json:"survivors"`
+}
+
+func (c Campaign) Key() string {
+	return c.ID
+}
+
+type Location struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+func (l Location) Key() string {
+	return l.ID
+}
+
+type Resource struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Source   string   `json:"source"`
+	Keywords []string `json:"keywords"`
+}
+
+func (r Resource) Key() string {
+	return r.ID
+}
+
+type RecipeCost struct {
+	Resource string `json:"resource,omitempty"`
+	Keyword  string `json:"keyword,omitempty"`
+	Quantity int    `json:"quantity"`
+}
+
+type GearRecipe struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Source   string       `json:"source"`
+	Location string       `json:"location"`
+	Gear     string       `json:"gear"`
+	Costs    []RecipeCost `json:"costs"`
+}
+
+func (r GearRecipe) Key() string {
+	return r.ID
+}
+
+type GearAffinities struct {
+	Top    string `json:"top,omitempty"`
+	Right  string `json:"right,omitempty"`
+	Bottom string `json:"bottom,omitempty"`
+	Left   string `json:"left,omitempty"`
+}
+
+type GearBonus struct {
+	Requires map[string]int `json:"requires"`
+	Effect   string         `json:"effect"`
+}
+
+type Gear struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Source         string         `json:"source"`
+	Keywords       []string       `json:"keywords"`
+	Armor          int            `json:"armor,omitempty"`
+	ArmorLocations []string       `json:"armorLocations,omitempty"`
+	Affinities     GearAffinities `json:"affinities"`
+	Bonus          *GearBonus     `json:"bonus,omitempty"`
+}
+
+func (g Gear) Key() string {
+	return g.ID
+}
+
+type Monster struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	Levels int    `json:"levels"`
+}
+
+func (m Monster) Key() string {
+	return m.ID
+}
+
 type mappable interface {
 	any
 	Key() string
 }
 
 type glossary struct {
-	Disorders    []disorder    `json:"disorders"`
-	Fightingarts []fightingArt `json:"fightingArts"`
-	Innovations  []innovation  `json:"innovations"`
-	Knowledge    []knowledge   `json:"knowledge"`
+	Disorders        []disorder        `json:"disorders"`
+	Fightingarts     []fightingArt     `json:"fightingArts"`
+	Innovations      []Innovation      `json:"innovations"`
+	Knowledge        []Knowledge       `json:"knowledge"`
+	Campaigns        []Campaign        `json:"campaigns"`
+	SettlementEvents []SettlementEvent `json:"settlementEvents"`
+	RollTables       []RollTable       `json:"rollTables"`
+	Locations        []Location        `json:"locations"`
+	Resources        []Resource        `json:"resources"`
+	Recipes          []GearRecipe      `json:"recipes"`
+	Gear             []Gear            `json:"gear"`
+	Monsters         []Monster         `json:"monsters"`
 }
 
 type Controller struct {
 	bulk         glossary
 	disorders    map[string]disorder
 	fightingarts map[string]fightingArt
-	innovations  map[string]innovation
-	knowledge    map[string]knowledge
+	innovations  map[string]Innovation
+	knowledge    map[string]Knowledge
+	campaigns    map[string]Campaign
+	events       map[string]SettlementEvent
+	rollTables   map[string]RollTable
+	locations    map[string]Location
+	resources    map[string]Resource
+	recipes      map[string]GearRecipe
+	gear         map[string]Gear
+	monsters     map[string]Monster
 }
 
 func NewController(glossaryServerURL string) (*Controller, error) {
@@ -100,6 +276,14 @@ func NewController(glossaryServerURL string) (*Controller, error) {
 		disorders:    toMap(glossary.Disorders),
 		fightingarts: toMap(glossary.Fightingarts),
 		knowledge:    toMap(glossary.Knowledge),
+		campaigns:    toMap(glossary.Campaigns),
+		events:       toMap(glossary.SettlementEvents),
+		rollTables:   toMap(glossary.RollTables),
+		locations:    toMap(glossary.Locations),
+		resources:    toMap(glossary.Resources),
+		recipes:      toMap(glossary.Recipes),
+		gear:         toMap(glossary.Gear),
+		monsters:     toMap(glossary.Monsters),
 	}, nil
 }
 
@@ -113,6 +297,22 @@ func (c Controller) RegisterRoutes(r chi.Router) {
 	r.Get("/glossary/innovations/{id}", c.getInnovation)
 	r.Get("/glossary/knowledge", c.allKnowledge)
 	r.Get("/glossary/knowledge/{id}", c.getKnowledge)
+	r.Get("/glossary/campaigns", c.allCampaigns)
+	r.Get("/glossary/campaigns/{id}", c.getCampaign)
+	r.Get("/glossary/settlementevents", c.allSettlementEvents)
+	r.Get("/glossary/settlementevents/{id}", c.getSettlementEvent)
+	r.Get("/glossary/rolltables", c.allRollTables)
+	r.Get("/glossary/rolltables/{id}", c.getRollTable)
+	r.Get("/glossary/locations", c.allLocations)
+	r.Get("/glossary/locations/{id}", c.getLocation)
+	r.Get("/glossary/resources", c.allResources)
+	r.Get("/glossary/resources/{id}", c.getResource)
+	r.Get("/glossary/recipes", c.allRecipes)
+	r.Get("/glossary/recipes/{id}", c.getRecipe)
+	r.Get("/glossary/gear", c.allGear)
+	r.Get("/glossary/gear/{id}", c.getGear)
+	r.Get("/glossary/monsters", c.allMonsters)
+	r.Get("/glossary/monsters/{id}", c.getMonster)
 }
 
 func (c Controller) getGlossary(w http.ResponseWriter, r *http.Request) {
@@ -179,6 +379,196 @@ func (c Controller) getKnowledge(w http.ResponseWriter, r *http.Request) {
 	response.OK(r.Context(), w, c.knowledge[id])
 }
 
+func (c Controller) allCampaigns(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.Campaigns)
+}
+
+func (c Controller) getCampaign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.campaigns[id])
+}
+
+func (c Controller) allSettlementEvents(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.SettlementEvents)
+}
+
+func (c Controller) getSettlementEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.events[id])
+}
+
+func (c Controller) allRollTables(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.RollTables)
+}
+
+func (c Controller) getRollTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.rollTables[id])
+}
+
+func (c Controller) allLocations(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.Locations)
+}
+
+func (c Controller) getLocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.locations[id])
+}
+
+func (c Controller) allResources(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.Resources)
+}
+
+func (c Controller) getResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.resources[id])
+}
+
+func (c Controller) allRecipes(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.Recipes)
+}
+
+func (c Controller) getRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.recipes[id])
+}
+
+func (c Controller) allGear(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.Gear)
+}
+
+func (c Controller) getGear(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.gear[id])
+}
+
+func (c Controller) allMonsters(w http.ResponseWriter, r *http.Request) {
+	response.OK(r.Context(), w, c.bulk.Monsters)
+}
+
+func (c Controller) getMonster(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := idParam(r)
+	if id == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid id"))
+		return
+	}
+
+	response.OK(r.Context(), w, c.monsters[id])
+}
+
+func (c Controller) Innovation(id string) (Innovation, bool) {
+	i, ok := c.innovations[id]
+	return i, ok
+}
+
+func (c Controller) Knowledge(id string) (Knowledge, bool) {
+	k, ok := c.knowledge[id]
+	return k, ok
+}
+
+func (c Controller) Campaign(id string) (Campaign, bool) {
+	campaign, ok := c.campaigns[id]
+	return campaign, ok
+}
+
+func (c Controller) SettlementEvents() []SettlementEvent {
+	return c.bulk.SettlementEvents
+}
+
+func (c Controller) SettlementEvent(id string) (SettlementEvent, bool) {
+	e, ok := c.events[id]
+	return e, ok
+}
+
+func (c Controller) RollTable(id string) (RollTable, bool) {
+	t, ok := c.rollTables[id]
+	return t, ok
+}
+
+func (c Controller) Locations() []Location {
+	return c.bulk.Locations
+}
+
+func (c Controller) Location(id string) (Location, bool) {
+	l, ok := c.locations[id]
+	return l, ok
+}
+
+func (c Controller) Resources() []Resource {
+	return c.bulk.Resources
+}
+
+func (c Controller) Resource(id string) (Resource, bool) {
+	r, ok := c.resources[id]
+	return r, ok
+}
+
+func (c Controller) GearRecipe(id string) (GearRecipe, bool) {
+	r, ok := c.recipes[id]
+	return r, ok
+}
+
+func (c Controller) AllGear() []Gear {
+	return c.bulk.Gear
+}
+
+func (c Controller) Gear(id string) (Gear, bool) {
+	g, ok := c.gear[id]
+	return g, ok
+}
+
+func (c Controller) Monsters() []Monster {
+	return c.bulk.Monsters
+}
+
+func (c Controller) Monster(id string) (Monster, bool) {
+	m, ok := c.monsters[id]
+	return m, ok
+}
+
 func fetchGlossary(uri string) (glossary, error) {
 	_, err := url.Parse(uri)
 	if err != nil {
@@ -197,6 +587,16 @@ func fetchGlossary(uri string) (glossary, error) {
 		return glossary{}, fmt.Errorf("unable to decode glossary from response: %w", err)
 	}
 
+	for _, t := range result.RollTables {
+		for _, e := range t.Entries {
+			for _, effect := range e.Effects {
+				if effect.Type == "" {
+					return glossary{}, fmt.Errorf("roll table %s: effect has no type", t.ID)
+				}
+			}
+		}
+	}
+
 	return result, nil
 }
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/failuretoload/datamonster/glossary"
 	"github.com/failuretoload/datamonster/server"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
 	"github.com/failuretoload/datamonster/testenv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -78,11 +79,103 @@ type knowledge struct {
 	Activation   string   `json:"activation,omitempty"`
 }
 
+type campaign struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Source        string   `json:"source"`
+	SurvivalLimit int      `json:"survivalLimit"`
+	Innovations   []string `json:"innovations"`
+	Timeline      []struct {
+		Year  int    `json:"year"`
+		Event string `json:"event"`
+	} `json:"timeline"`
+	Survivors []struct {
+		Name   string `json:"name"`
+		Gender string `json:"gender"`
+	} `json:"survivors"`
+}
+
+type settlementEvent struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Source string   `json:"source"`
+	Text   []string `json:"text"`
+}
+
+type rollTableEntry struct {
+	Min     int                     `json:"min"`
+	Max     int                     `json:"max"`
+	Result  string                  `json:"result"`
+	Effects []survivordomain.Effect `json:"effects"`
+}
+
+type rollTable struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Source  string           `json:"source"`
+	Dice    string           `json:"dice"`
+	Entries []rollTableEntry `json:"entries"`
+}
+
+type location struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+type resource struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Source   string   `json:"source"`
+	Keywords []string `json:"keywords"`
+}
+
+type recipeCost struct {
+	Resource string `json:"resource"`
+	Keyword  string `json:"keyword"`
+	Quantity int    `json:"quantity"`
+}
+
+type recipe struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Source   string       `json:"source"`
+	Location string       `json:"location"`
+	Gear     string       `json:"gear"`
+	Costs    []recipeCost `json:"costs"`
+}
+
+type gear struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Source         string            `json:"source"`
+	Keywords       []string          `json:"keywords"`
+	Armor          int               `json:"armor"`
+	ArmorLocations []string          `json:"armorLocations"`
+	Affinities     map[string]string `json:"affinities"`
+}
+
+type monster struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	Levels int    `json:"levels"`
+}
+
 type glossaryResponse struct {
-	Disorders    []disorder    `json:"disorders"`
-	FightingArts []fightingArt `json:"fightingArts"`
-	Innovations  []innovation  `json:"innovations"`
-	Knowledge    []knowledge   `json:"knowledge"`
+	Disorders        []disorder        `json:"disorders"`
+	FightingArts     []fightingArt     `json:"fightingArts"`
+	Innovations      []innovation      `json:"innovations"`
+	Knowledge        []knowledge       `json:"knowledge"`
+	Campaigns        []campaign        `json:"campaigns"`
+	SettlementEvents []settlementEvent `json:"settlementEvents"`
+	RollTables       []rollTable       `json:"rollTables"`
+	Locations        []location        `json:"locations"`
+	Resources        []resource        `json:"resources"`
+	Recipes          []recipe          `json:"recipes"`
+	Gear             []gear            `json:"gear"`
+	Monsters         []monster         `json:"monsters"`
 }
 
 func TestGetAllDisorders(t *testing.T) {
@@ -138,7 +231,7 @@ func TestGetAllInnovations(t *testing.T) {
 
 	var innovations []innovation
 	require.NoError(t, json.NewDecoder(body).Decode(&innovations))
-	require.Len(t, innovations, 2)
+	require.Len(t, innovations, 7)
 
 	validateInnovations(t, innovations)
 }
@@ -181,6 +274,121 @@ func TestGetKnowledge(t *testing.T) {
 	assert.Equal(t, "requires 019412a0-0007-7000-8000-000000000007", k.Condition)
 }
 
+func TestGetAllCampaigns(t *testing.T) {
+	body, status := requester.GetAllCampaigns("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []campaign
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 4)
+	validateCampaigns(t, items)
+}
+
+func TestGetCampaign(t *testing.T) {
+	body, status := requester.GetCampaign("test-user", "019412a0-0009-7000-8000-000000000009")
+	require.Equal(t, http.StatusOK, status)
+
+	var c campaign
+	require.NoError(t, json.NewDecoder(body).Decode(&c))
+	assert.Equal(t, "People of the Lantern", c.Name)
+	assert.Equal(t, 1, c.SurvivalLimit)
+	assert.Equal(t, []string{"019412a0-0005-7000-8000-000000000005"}, c.Innovations)
+	assert.Len(t, c.Timeline, 4)
+	assert.Len(t, c.Survivors, 4)
+}
+
+func TestGetAllSettlementEvents(t *testing.T) {
+	body, status := requester.GetAllSettlementEvents("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []settlementEvent
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 3)
+	for _, e := range items {
+		assert.NotEmpty(t, e.ID)
+		assert.NotEmpty(t, e.Name)
+		assert.NotEmpty(t, e.Text)
+	}
+}
+
+func TestGetAllRollTables(t *testing.T) {
+	body, status := requester.GetAllRollTables("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []rollTable
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 2)
+	validateRollTables(t, items)
+}
+
+func TestGetAllLocations(t *testing.T) {
+	body, status := requester.GetAllLocations("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []location
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 3)
+	for _, l := range items {
+		assert.NotEmpty(t, l.ID)
+		assert.NotEmpty(t, l.Name)
+	}
+}
+
+func TestGetAllResources(t *testing.T) {
+	body, status := requester.GetAllResources("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []resource
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 5)
+	for _, r := range items {
+		assert.NotEmpty(t, r.ID)
+		assert.NotEmpty(t, r.Name)
+		assert.NotEmpty(t, r.Keywords)
+	}
+}
+
+func TestGetAllRecipes(t *testing.T) {
+	body, status := requester.GetAllRecipes("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []recipe
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 3)
+}
+
+func TestGetAllGear(t *testing.T) {
+	body, status := requester.GetAllGear("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []gear
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 5)
+	for _, g := range items {
+		assert.NotEmpty(t, g.ID)
+		assert.NotEmpty(t, g.Name)
+		assert.NotEmpty(t, g.Keywords)
+		if g.Armor > 0 {
+			assert.NotEmpty(t, g.ArmorLocations, g.Name)
+		}
+	}
+}
+
+func TestGetAllMonsters(t *testing.T) {
+	body, status := requester.GetAllMonsters("test-user")
+	require.Equal(t, http.StatusOK, status)
+
+	var items []monster
+	require.NoError(t, json.NewDecoder(body).Decode(&items))
+	require.Len(t, items, 4)
+	for _, m := range items {
+		assert.NotEmpty(t, m.ID)
+		assert.NotEmpty(t, m.Name)
+		assert.Contains(t, []string{"quarry", "nemesis"}, m.Type)
+		assert.Positive(t, m.Levels)
+	}
+}
+
 func TestGetDisorder_Unauthorized(t *testing.T) {
 	t.Cleanup(requester.Unauthorized())
 	_, status := requester.GetDisorder("unauthorized", "019412a0-0001-7000-8000-000000000001")
@@ -199,11 +407,82 @@ func TestGetGlossary(t *testing.T) {
 	require.Len(t, g.FightingArts, 2)
 	validateFightingArts(t, g.FightingArts)
 
-	require.Len(t, g.Innovations, 2)
+	require.Len(t, g.Innovations, 7)
 	validateInnovations(t, g.Innovations)
 
 	require.Len(t, g.Knowledge, 2)
 	validateKnowledge(t, g.Knowledge)
+
+	require.Len(t, g.Campaigns, 4)
+	validateCampaigns(t, g.Campaigns)
+
+	require.Len(t, g.SettlementEvents, 3)
+
+	require.Len(t, g.RollTables, 2)
+	validateRollTables(t, g.RollTables)
+
+	require.Len(t, g.Locations, 3)
+	require.Len(t, g.Resources, 5)
+	require.Len(t, g.Recipes, 3)
+	validateRecipes(t, g.Recipes, g.Locations, g.Resources)
+
+	require.Len(t, g.Gear, 5)
+	gearIDs := map[string]bool{}
+	for _, item := range g.Gear {
+		gearIDs[item.ID] = true
+	}
+	for _, r := range g.Recipes {
+		assert.True(t, gearIDs[r.Gear], "recipe %s crafts unknown gear", r.Name)
+	}
+
+	require.Len(t, g.Monsters, 4)
+}
+
+// validateRecipes checks that every recipe points at a known location and
+// that named costs point at known resources.
+func validateRecipes(t *testing.T, recipes []recipe, locations []location, resources []resource) {
+	locationIDs := map[string]bool{}
+	for _, l := range locations {
+		locationIDs[l.ID] = true
+	}
+	resourceIDs := map[string]bool{}
+	for _, r := range resources {
+		resourceIDs[r.ID] = true
+	}
+
+	for _, r := range recipes {
+		assert.NotEmpty(t, r.Gear, r.Name)
+		assert.True(t, locationIDs[r.Location], r.Name)
+		require.NotEmpty(t, r.Costs, r.Name)
+		for _, c := range r.Costs {
+			assert.Positive(t, c.Quantity, r.Name)
+			if c.Resource != "" {
+				assert.True(t, resourceIDs[c.Resource], r.Name)
+			} else {
+				assert.NotEmpty(t, c.Keyword, r.Name)
+			}
+		}
+	}
+}
+
+// validateRollTables checks that each table's entries are contiguous.
+func validateRollTables(t *testing.T, items []rollTable) {
+	for _, table := range items {
+		assert.NotEmpty(t, table.ID)
+		assert.NotEmpty(t, table.Name)
+		assert.NotEmpty(t, table.Dice)
+		require.NotEmpty(t, table.Entries)
+		for i, e := range table.Entries {
+			assert.LessOrEqual(t, e.Min, e.Max)
+			assert.NotEmpty(t, e.Result)
+			for _, effect := range e.Effects {
+				assert.NoError(t, effect.Validate())
+			}
+			if i > 0 {
+				assert.Equal(t, table.Entries[i-1].Max+1, e.Min)
+			}
+		}
+	}
 }
 
 func validateDisorders(t *testing.T, items []disorder) {
@@ -253,3 +532,13 @@ func validateKnowledge(t *testing.T, items []knowledge) {
 		assert.NotEmpty(t, k.Description)
 	}
 }
+
+func validateCampaigns(t *testing.T, items []campaign) {
+	for _, c := range items {
+		assert.NotEmpty(t, c.ID)
+		assert.NotEmpty(t, c.Name)
+		assert.NotEmpty(t, c.Source)
+		assert.NotEmpty(t, c.Timeline)
+		assert.Len(t, c.Survivors, 4)
+	}
+}
@@ -0,0 +1,178 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/failuretoload/datamonster/journal/domain"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		Search(ctx context.Context, settlementID uuid.UUID, q domain.Query) ([]domain.Entry, error)
+		Get(ctx context.Context, settlementID, entryID uuid.UUID) (domain.Entry, error)
+		Create(ctx context.Context, settlementID uuid.UUID, in domain.EntryInput, createdBy string) (domain.Entry, error)
+		Update(ctx context.Context, settlementID, entryID uuid.UUID, in domain.EntryInput) (domain.Entry, error)
+		Delete(ctx context.Context, settlementID, entryID uuid.UUID) error
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+	}
+)
+
+func NewController(r Repo, s Settlements) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+
+	return &Controller{records: r, settlements: s}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Get("/settlements/{id}/journal", c.searchEntries)
+		gr.Post("/settlements/{id}/journal", c.createEntry)
+		gr.Get("/settlements/{id}/journal/{entryID}", c.getEntry)
+		gr.Put("/settlements/{id}/journal/{entryID}", c.updateEntry)
+		gr.Delete("/settlements/{id}/journal/{entryID}", c.deleteEntry)
+	})
+}
+
+func (c Controller) searchEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+	q, err := domain.NewQuery(
+		params.Get("q"),
+		params.Get("tag"),
+		params.Get("survivor"),
+		params.Get("year"),
+		params.Get("limit"),
+	)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	entries, err := c.records.Search(ctx, request.SettlementID(ctx), q)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error searching journal: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, entries)
+}
+
+func (c Controller) createEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in, ok := decodeInput(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := c.records.Create(ctx, request.SettlementID(ctx), in, request.UserID(ctx))
+	if err != nil {
+		writeJournalError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, entry)
+}
+
+func (c Controller) getEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	entryID, err := uuid.FromString(chi.URLParam(r, "entryID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid entry id"))
+		return
+	}
+
+	entry, err := c.records.Get(ctx, request.SettlementID(ctx), entryID)
+	if err != nil {
+		writeJournalError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, entry)
+}
+
+func (c Controller) updateEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	entryID, err := uuid.FromString(chi.URLParam(r, "entryID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid entry id"))
+		return
+	}
+
+	in, ok := decodeInput(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := c.records.Update(ctx, request.SettlementID(ctx), entryID, in)
+	if err != nil {
+		writeJournalError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, entry)
+}
+
+func (c Controller) deleteEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	entryID, err := uuid.FromString(chi.URLParam(r, "entryID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid entry id"))
+		return
+	}
+
+	if err := c.records.Delete(ctx, request.SettlementID(ctx), entryID); err != nil {
+		writeJournalError(ctx, w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func decodeInput(ctx context.Context, w http.ResponseWriter, r *http.Request) (domain.EntryInput, bool) {
+	var body domain.EntryInput
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return domain.EntryInput{}, false
+	}
+
+	in, err := body.Normalize()
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return domain.EntryInput{}, false
+	}
+
+	return in, true
+}
+
+func writeJournalError(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrEntryNotFound):
+		response.NotFound(ctx, w, err)
+	case errors.Is(err, domain.ErrSurvivorNotFound):
+		response.BadRequest(ctx, w, err)
+	default:
+		response.InternalServerError(ctx, w, fmt.Errorf("error accessing journal: %w", err))
+	}
+}
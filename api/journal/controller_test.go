@@ -0,0 +1,249 @@
+package journal_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/journal"
+	"github.com/failuretoload/datamonster/journal/domain"
+	journalRepo "github.com/failuretoload/datamonster/journal/repo"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/survivor"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	survivorRepo "github.com/failuretoload/datamonster/survivor/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for journal tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryStub := testenv.NewGlossaryStub(`{}`)
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	survivorController, err := survivor.NewController(survivorRepo, settlementRepo, survivordomain.DefaultMilestoneRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	entryRepo, err := journalRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	journalController, err := journal.NewController(entryRepo, settlementRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, survivorController, journalController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func create(t *testing.T, userID, settlementID, body string) domain.Entry {
+	raw, status := requester.CreateJournalEntry(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var e domain.Entry
+	require.NoError(t, json.NewDecoder(raw).Decode(&e))
+	return e
+}
+
+func search(t *testing.T, userID, settlementID string, params url.Values) []domain.Entry {
+	raw, status := requester.SearchJournal(userID, settlementID, params.Encode())
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var entries []domain.Entry
+	require.NoError(t, json.NewDecoder(raw).Decode(&entries))
+	return entries
+}
+
+func titles(entries []domain.Entry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Title
+	}
+	return out
+}
+
+func newSurvivor(t *testing.T, userID, settlementID string) string {
+	raw, status := requester.CreateSurvivor(userID, settlementID, "Zachary")
+	require.Equal(t, http.StatusOK, status)
+
+	var s survivordomain.Survivor
+	require.NoError(t, json.NewDecoder(raw).Decode(&s))
+	return s.ID.String()
+}
+
+func TestJournal_CRUD(t *testing.T) {
+	userID := "journal-crud-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	survivorID := newSurvivor(t, userID, settlementID)
+
+	entry := create(t, userID, settlementID, fmt.Sprintf(
+		`{"title":"The lion took Zachary","body":"# Year 2\n\n*Never forget.*","tags":["Death","lion"],"survivor":"%s","year":2}`,
+		survivorID,
+	))
+	assert.Equal(t, "The lion took Zachary", entry.Title)
+	assert.Equal(t, "# Year 2\n\n*Never forget.*", entry.Body)
+	assert.Equal(t, []string{"death", "lion"}, entry.Tags)
+	assert.Equal(t, survivorID, entry.Survivor.String())
+	assert.Equal(t, 2, *entry.Year)
+	assert.Equal(t, userID, entry.CreatedBy)
+
+	raw, status := requester.GetJournalEntry(userID, settlementID, entry.ID.String())
+	require.Equal(t, http.StatusOK, status)
+	var fetched domain.Entry
+	require.NoError(t, json.NewDecoder(raw).Decode(&fetched))
+	assert.Equal(t, entry.ID, fetched.ID)
+
+	raw, status = requester.UpdateJournalEntry(userID, settlementID, entry.ID.String(), `{"title":"Zachary's last hunt","tags":["death"]}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+	var updated domain.Entry
+	require.NoError(t, json.NewDecoder(raw).Decode(&updated))
+	assert.Equal(t, "Zachary's last hunt", updated.Title)
+	assert.Empty(t, updated.Body)
+	assert.Nil(t, updated.Survivor, "update replaces the entry")
+	assert.Nil(t, updated.Year)
+	assert.False(t, updated.UpdatedAt.Before(entry.UpdatedAt))
+
+	_, status = requester.DeleteJournalEntry(userID, settlementID, entry.ID.String())
+	require.Equal(t, http.StatusNoContent, status)
+	_, status = requester.GetJournalEntry(userID, settlementID, entry.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.DeleteJournalEntry(userID, settlementID, entry.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.UpdateJournalEntry(userID, settlementID, entry.ID.String(), `{"title":"gone"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestJournal_FullTextSearch(t *testing.T) {
+	userID := "journal-search-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	create(t, userID, settlementID, `{"title":"Ruling on knockdown","body":"Knockdown happens before the monster moves.","tags":["rules"]}`)
+	create(t, userID, settlementID, `{"title":"Antelope stampede","body":"Everyone got **trampled**.","tags":["funny"]}`)
+	create(t, userID, settlementID, `{"title":"Trampled","body":"The lion trampled Ana, and then trampled her again.","tags":["death"],"year":3}`)
+
+	assert.Len(t, search(t, userID, settlementID, url.Values{}), 3)
+
+	found := search(t, userID, settlementID, url.Values{"q": {"trample"}})
+	assert.Equal(t, []string{"Trampled", "Antelope stampede"}, titles(found), "title matches rank first")
+
+	found = search(t, userID, settlementID, url.Values{"q": {"knockdown -antelope"}})
+	assert.Equal(t, []string{"Ruling on knockdown"}, titles(found))
+
+	found = search(t, userID, settlementID, url.Values{"q": {"trampled"}, "tag": {"Funny"}})
+	assert.Equal(t, []string{"Antelope stampede"}, titles(found))
+
+	found = search(t, userID, settlementID, url.Values{"year": {"3"}})
+	assert.Equal(t, []string{"Trampled"}, titles(found))
+
+	assert.Len(t, search(t, userID, settlementID, url.Values{"limit": {"1"}}), 1)
+	assert.Empty(t, search(t, userID, settlementID, url.Values{"q": {"phoenix"}}))
+
+	_, status := requester.SearchJournal(userID, settlementID, "limit=0")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestJournal_FilterBySurvivor(t *testing.T) {
+	userID := "journal-survivor-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	survivorID := newSurvivor(t, userID, settlementID)
+
+	create(t, userID, settlementID, fmt.Sprintf(`{"title":"Named","survivor":"%s"}`, survivorID))
+	create(t, userID, settlementID, `{"title":"Unnamed"}`)
+
+	found := search(t, userID, settlementID, url.Values{"survivor": {survivorID}})
+	assert.Equal(t, []string{"Named"}, titles(found))
+}
+
+func TestJournal_InvalidEntries(t *testing.T) {
+	userID := "journal-invalid-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	otherSettlement, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	foreignSurvivor := newSurvivor(t, userID, otherSettlement)
+
+	for _, body := range []string{
+		`{"title":""}`,
+		`{"title":"x","tags":[""]}`,
+		`{"title":"x","year":-1}`,
+		`{"title":"x","mood":"grim"}`,
+		fmt.Sprintf(`{"title":"x","survivor":"%s"}`, foreignSurvivor),
+		fmt.Sprintf(`{"title":"x","survivor":"%s"}`, testenv.UUIDString()),
+	} {
+		_, status := requester.CreateJournalEntry(userID, settlementID, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+
+	_, status := requester.GetJournalEntry(userID, settlementID, "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestJournal_SettlementIsolation(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("journal-owner")
+	require.NoError(t, err)
+	entry := create(t, "journal-owner", settlementID, `{"title":"Private"}`)
+
+	_, status := requester.SearchJournal("journal-intruder", settlementID, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.GetJournalEntry("journal-intruder", settlementID, entry.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+
+	otherID, err := requester.CreateSettlement("journal-owner")
+	require.NoError(t, err)
+	_, status = requester.GetJournalEntry("journal-owner", otherID, entry.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.DeleteJournalEntry("journal-owner", otherID, entry.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestJournal_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.SearchJournal("unauthorized", testenv.UUIDString(), "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
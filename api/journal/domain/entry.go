@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	MaxTitleLen  = 200
+	MaxBodyLen   = 20000
+	MaxTags      = 20
+	MaxTagLen    = 40
+	MaxQueryLen  = 200
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrEntryNotFound    = errors.New("journal entry not found")
+	ErrSurvivorNotFound = errors.New("survivor not found in settlement")
+)
+
+type Entry struct {
+	ID        uuid.UUID  `json:"id"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Tags      []string   `json:"tags"`
+	Survivor  *uuid.UUID `json:"survivor,omitempty"`
+	Year      *int       `json:"year,omitempty"`
+	CreatedBy string     `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+type EntryInput struct {
+	Title    string     `json:"title"`
+	Body     string     `json:"body"`
+	Tags     []string   `json:"tags,omitempty"`
+	Survivor *uuid.UUID `json:"survivor,omitempty"`
+	Year     *int       `json:"year,omitempty"`
+}
+
+type Query struct {
+	Text     string
+	Tag      string
+	Survivor *uuid.UUID
+	Year     *int
+	Limit    int
+}
+
+func (in EntryInput) Normalize() (EntryInput, error) {
+	in.Title = strings.TrimSpace(in.Title)
+	if in.Title == "" {
+		return EntryInput{}, fmt.Errorf("title is required")
+	}
+	if utf8.RuneCountInString(in.Title) > MaxTitleLen {
+		return EntryInput{}, fmt.Errorf("title must be at most %d characters", MaxTitleLen)
+	}
+	if utf8.RuneCountInString(in.Body) > MaxBodyLen {
+		return EntryInput{}, fmt.Errorf("body must be at most %d characters", MaxBodyLen)
+	}
+	if in.Year != nil && *in.Year < 0 {
+		return EntryInput{}, fmt.Errorf("invalid year")
+	}
+	if in.Survivor != nil && *in.Survivor == uuid.Nil {
+		return EntryInput{}, fmt.Errorf("invalid survivor id")
+	}
+
+	tags := make([]string, 0, len(in.Tags))
+	for _, raw := range in.Tags {
+		tag, err := NormalizeTag(raw)
+		if err != nil {
+			return EntryInput{}, err
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > MaxTags {
+		return EntryInput{}, fmt.Errorf("an entry can have at most %d tags", MaxTags)
+	}
+	slices.Sort(tags)
+	in.Tags = tags
+
+	return in, nil
+}
+
+func NormalizeTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if tag == "" {
+		return "", fmt.Errorf("tags cannot be blank")
+	}
+	if utf8.RuneCountInString(tag) > MaxTagLen {
+		return "", fmt.Errorf("tags must be at most %d characters", MaxTagLen)
+	}
+	return tag, nil
+}
+
+func NewQuery(text, tag, survivor, year, limit string) (Query, error) {
+	q := Query{Text: strings.TrimSpace(text), Limit: DefaultLimit}
+	if utf8.RuneCountInString(q.Text) > MaxQueryLen {
+		return Query{}, fmt.Errorf("search must be at most %d characters", MaxQueryLen)
+	}
+
+	if tag != "" {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return Query{}, err
+		}
+		q.Tag = normalized
+	}
+
+	if survivor != "" {
+		id, err := uuid.FromString(survivor)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid survivor id")
+		}
+		q.Survivor = &id
+	}
+
+	if year != "" {
+		n, err := strconv.Atoi(year)
+		if err != nil || n < 0 {
+			return Query{}, fmt.Errorf("invalid year")
+		}
+		q.Year = &n
+	}
+
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxLimit {
+			return Query{}, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		q.Limit = n
+	}
+
+	return q, nil
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	year := 3
+	in, err := EntryInput{
+		Title: "  The lion took Zachary  ",
+		Body:  "**Ouch.**",
+		Tags:  []string{" Death ", "lion", "death"},
+		Year:  &year,
+	}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, "The lion took Zachary", in.Title)
+	assert.Equal(t, "**Ouch.**", in.Body)
+	assert.Equal(t, []string{"death", "lion"}, in.Tags)
+	assert.Equal(t, 3, *in.Year)
+}
+
+func TestNormalizeRejectsInvalid(t *testing.T) {
+	negative := -1
+	manyTags := make([]string, MaxTags+1)
+	for i := range manyTags {
+		manyTags[i] = strings.Repeat("t", i+1)
+	}
+	tests := []struct {
+		name string
+		in   EntryInput
+	}{
+		{"blank title", EntryInput{Title: "   "}},
+		{"long title", EntryInput{Title: strings.Repeat("a", MaxTitleLen+1)}},
+		{"long body", EntryInput{Title: "a", Body: strings.Repeat("a", MaxBodyLen+1)}},
+		{"blank tag", EntryInput{Title: "a", Tags: []string{" "}}},
+		{"long tag", EntryInput{Title: "a", Tags: []string{strings.Repeat("a", MaxTagLen+1)}}},
+		{"too many tags", EntryInput{Title: "a", Tags: manyTags}},
+		{"negative year", EntryInput{Title: "a", Year: &negative}},
+		{"nil survivor", EntryInput{Title: "a", Survivor: &uuid.Nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.in.Normalize()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewQuery(t *testing.T) {
+	q, err := NewQuery("", "", "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, Query{Limit: DefaultLimit}, q)
+
+	id := uuid.Must(uuid.NewV7())
+	q, err = NewQuery(" lion ", "Rules", id.String(), "4", "10")
+	require.NoError(t, err)
+	assert.Equal(t, "lion", q.Text)
+	assert.Equal(t, "rules", q.Tag)
+	assert.Equal(t, id, *q.Survivor)
+	assert.Equal(t, 4, *q.Year)
+	assert.Equal(t, 10, q.Limit)
+}
+
+func TestNewQueryRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name                             string
+		text, tag, survivor, year, limit string
+	}{
+		{"long search", strings.Repeat("a", MaxQueryLen+1), "", "", "", ""},
+		{"bad survivor", "", "", "nope", "", ""},
+		{"bad year", "", "", "", "-1", ""},
+		{"zero limit", "", "", "", "", "0"},
+		{"huge limit", "", "", "", "", "201"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewQuery(tt.text, tt.tag, tt.survivor, tt.year, tt.limit)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/failuretoload/datamonster/journal/domain"
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	entryColumns = `external_id, survivor_id, year, title, body, tags, created_by, created_at, updated_at`
+	hasSurvivor  = `SELECT EXISTS (SELECT 1 FROM survivor WHERE settlement_id = $1 AND external_id = $2)`
+	insertEntry  = `INSERT INTO settlement_journal (settlement_id, survivor_id, year, title, body, tags, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING ` + entryColumns
+	getEntry = `SELECT ` + entryColumns + ` FROM settlement_journal
+WHERE settlement_id = $1 AND external_id = $2`
+	updateEntry = `UPDATE settlement_journal
+SET survivor_id = $3, year = $4, title = $5, body = $6, tags = $7, updated_at = NOW()
+WHERE settlement_id = $1 AND external_id = $2
+RETURNING ` + entryColumns
+	deleteEntry  = `DELETE FROM settlement_journal WHERE settlement_id = $1 AND external_id = $2`
+	searchSelect = `SELECT ` + entryColumns + ` FROM settlement_journal WHERE settlement_id = $1`
+)
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("journal repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) Search(ctx context.Context, settlementID uuid.UUID, q domain.Query) ([]domain.Entry, error) {
+	args := []any{settlementID}
+	filters := ""
+	order := " ORDER BY created_at DESC, id DESC"
+	if q.Text != "" {
+		args = append(args, q.Text)
+		filters += fmt.Sprintf(" AND search @@ websearch_to_tsquery('english', $%d)", len(args))
+		order = fmt.Sprintf(" ORDER BY ts_rank(search, websearch_to_tsquery('english', $%d)) DESC, created_at DESC, id DESC", len(args))
+	}
+	if q.Tag != "" {
+		args = append(args, []string{q.Tag})
+		filters += fmt.Sprintf(" AND tags @> $%d::text[]", len(args))
+	}
+	if q.Survivor != nil {
+		args = append(args, *q.Survivor)
+		filters += fmt.Sprintf(" AND survivor_id = $%d", len(args))
+	}
+	if q.Year != nil {
+		args = append(args, *q.Year)
+		filters += fmt.Sprintf(" AND year = $%d", len(args))
+	}
+	args = append(args, q.Limit)
+	query := searchSelect + filters + order + fmt.Sprintf(" LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to search journal")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	entries, err := pgx.CollectRows(rows, scanEntry)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read journal entries")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return entries, nil
+}
+
+func (r Postgres) Get(ctx context.Context, settlementID, entryID uuid.UUID) (domain.Entry, error) {
+	rows, err := r.db.Query(ctx, getEntry, settlementID, entryID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query journal entry")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Entry{}, safeErr
+	}
+
+	return collectEntry(ctx, settlementID, rows)
+}
+
+func (r Postgres) Create(ctx context.Context, settlementID uuid.UUID, in domain.EntryInput, createdBy string) (domain.Entry, error) {
+	var entry domain.Entry
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := checkSurvivor(ctx, tx, settlementID, in.Survivor); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, insertEntry, settlementID, in.Survivor, in.Year, in.Title, in.Body, in.Tags, createdBy)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to create journal entry")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		entry, err = collectEntry(ctx, settlementID, rows)
+		return err
+	})
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r Postgres) Update(ctx context.Context, settlementID, entryID uuid.UUID, in domain.EntryInput) (domain.Entry, error) {
+	var entry domain.Entry
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := checkSurvivor(ctx, tx, settlementID, in.Survivor); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, updateEntry, settlementID, entryID, in.Survivor, in.Year, in.Title, in.Body, in.Tags)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to update journal entry")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		entry, err = collectEntry(ctx, settlementID, rows)
+		return err
+	})
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r Postgres) Delete(ctx context.Context, settlementID, entryID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, deleteEntry, settlementID, entryID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to delete journal entry")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntryNotFound
+	}
+	return nil
+}
+
+func checkSurvivor(ctx context.Context, tx pgx.Tx, settlementID uuid.UUID, survivorID *uuid.UUID) error {
+	if survivorID == nil {
+		return nil
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, hasSurvivor, settlementID, *survivorID).Scan(&exists); err != nil {
+		safeErr := fmt.Errorf("unable to query survivor")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+	if !exists {
+		return domain.ErrSurvivorNotFound
+	}
+	return nil
+}
+
+func collectEntry(ctx context.Context, settlementID uuid.UUID, rows pgx.Rows) (domain.Entry, error) {
+	entry, err := pgx.CollectExactlyOneRow(rows, scanEntry)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Entry{}, domain.ErrEntryNotFound
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read journal entry")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Entry{}, safeErr
+	}
+	return entry, nil
+}
+
+func scanEntry(row pgx.CollectableRow) (domain.Entry, error) {
+	var e domain.Entry
+	err := row.Scan(&e.ID, &e.Survivor, &e.Year, &e.Title, &e.Body, &e.Tags, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
@@ -0,0 +1,250 @@
+package knowledge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/knowledge/domain"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		All(ctx context.Context, settlementID, survivorID uuid.UUID) ([]domain.SurvivorKnowledge, error)
+		Learn(ctx context.Context, settlementID uuid.UUID, k domain.SurvivorKnowledge) (domain.SurvivorKnowledge, error)
+		Save(ctx context.Context, settlementID uuid.UUID, before, after domain.SurvivorKnowledge) (domain.SurvivorKnowledge, error)
+		Forget(ctx context.Context, settlementID, survivorID, knowledgeID uuid.UUID) error
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Glossary interface {
+		Knowledge(id string) (glossary.Knowledge, bool)
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+		glossary    Glossary
+	}
+	LearnRequest struct {
+		KnowledgeID uuid.UUID `json:"knowledgeId"`
+	}
+	ObservationResult struct {
+		domain.SurvivorKnowledge
+		Advanced bool `json:"advanced"`
+	}
+)
+
+func NewController(r Repo, s Settlements, g Glossary) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+
+	if g == nil {
+		return nil, fmt.Errorf("glossary cannot be nil")
+	}
+
+	return &Controller{records: r, settlements: s, glossary: g}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Get("/settlements/{id}/survivors/{survivorID}/knowledge", c.getKnowledge)
+		gr.Post("/settlements/{id}/survivors/{survivorID}/knowledge", c.learnKnowledge)
+		gr.Post("/settlements/{id}/survivors/{survivorID}/knowledge/{knowledgeID}/observations", c.observeKnowledge)
+		gr.Delete("/settlements/{id}/survivors/{survivorID}/knowledge/{knowledgeID}", c.forgetKnowledge)
+	})
+}
+
+func (c Controller) getKnowledge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	held, err := c.records.All(ctx, request.SettlementID(ctx), survivorID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving knowledge: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, held)
+}
+
+func (c Controller) learnKnowledge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	var body LearnRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	def, ok := c.definition(body.KnowledgeID)
+	if !ok {
+		response.BadRequest(ctx, w, fmt.Errorf("unknown knowledge: %s", body.KnowledgeID))
+		return
+	}
+
+	settlementID := request.SettlementID(ctx)
+	held, err := c.records.All(ctx, settlementID, survivorID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving knowledge: %w", err))
+		return
+	}
+
+	k, err := domain.Learn(survivorID, held, def)
+	if errors.Is(err, domain.ErrAlreadyKnown) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	learned, err := c.records.Learn(ctx, settlementID, k)
+	if err != nil {
+		writeRepoError(ctx, w, fmt.Errorf("error learning knowledge: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, learned)
+}
+
+func (c Controller) observeKnowledge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	knowledgeID, err := uuid.FromString(chi.URLParam(r, "knowledgeID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid knowledge id"))
+		return
+	}
+
+	settlementID := request.SettlementID(ctx)
+	held, err := c.records.All(ctx, settlementID, survivorID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving knowledge: %w", err))
+		return
+	}
+
+	var current *domain.SurvivorKnowledge
+	for i := range held {
+		if held[i].KnowledgeID == knowledgeID {
+			current = &held[i]
+		}
+	}
+	if current == nil {
+		response.NotFound(ctx, w, fmt.Errorf("survivor does not hold knowledge %s", knowledgeID))
+		return
+	}
+
+	def, ok := c.definition(knowledgeID)
+	if !ok {
+		response.InternalServerError(ctx, w, fmt.Errorf("knowledge %s is missing from the glossary", knowledgeID))
+		return
+	}
+
+	next, advance, err := domain.Observe(*current, def)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	if advance {
+		advanced, ok := c.definition(def.Advance)
+		if !ok {
+			response.InternalServerError(ctx, w, fmt.Errorf("advanced knowledge %s is missing from the glossary", def.Advance))
+			return
+		}
+		next = domain.Advance(next, advanced)
+	}
+
+	saved, err := c.records.Save(ctx, settlementID, *current, next)
+	if err != nil {
+		writeRepoError(ctx, w, fmt.Errorf("error observing knowledge: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, ObservationResult{SurvivorKnowledge: saved, Advanced: advance})
+}
+
+func (c Controller) forgetKnowledge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	knowledgeID, err := uuid.FromString(chi.URLParam(r, "knowledgeID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid knowledge id"))
+		return
+	}
+
+	if err := c.records.Forget(ctx, request.SettlementID(ctx), survivorID, knowledgeID); err != nil {
+		writeRepoError(ctx, w, fmt.Errorf("error forgetting knowledge: %w", err))
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (c Controller) definition(id uuid.UUID) (domain.Definition, bool) {
+	k, ok := c.glossary.Knowledge(id.String())
+	if !ok {
+		return domain.Definition{}, false
+	}
+
+	advance, err := uuid.FromString(k.Advance)
+	if err != nil {
+		advance = uuid.Nil
+	}
+
+	return domain.Definition{
+		ID:           id,
+		Cost:         k.Cost,
+		Tenet:        k.Tenet,
+		Condition:    k.Condition,
+		Observations: k.Observations,
+		Advance:      advance,
+	}, true
+}
+
+func writeRepoError(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(ctx, w, err)
+	case errors.Is(err, domain.ErrStale), errors.Is(err, domain.ErrAlreadyKnown):
+		response.Conflict(ctx, w, err)
+	default:
+		response.InternalServerError(ctx, w, err)
+	}
+}
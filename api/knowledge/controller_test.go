@@ -0,0 +1,288 @@
+package knowledge_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/knowledge"
+	"github.com/failuretoload/datamonster/knowledge/domain"
+	knowledgeRepo "github.com/failuretoload/datamonster/knowledge/repo"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementDomain "github.com/failuretoload/datamonster/settlement/domain"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/survivor"
+	survivorDomain "github.com/failuretoload/datamonster/survivor/domain"
+	survivorRepo "github.com/failuretoload/datamonster/survivor/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	basicKnowledgeID    = "019412a0-0007-7000-8000-000000000007"
+	advancedKnowledgeID = "019412a0-0008-7000-8000-000000000008"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	dbContainer, err := testenv.NewDBContainer(ctx)
+	if err != nil {
+		log.Fatalf("unable to set up test env for knowledge tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryContainer, err := testenv.NewGlossaryContainer(ctx)
+	if err != nil {
+		log.Fatalf("unable to set up glossary container: %v", err)
+	}
+	defer glossaryContainer.Cleanup(ctx)
+
+	glossaryController, err := glossary.NewController(glossaryContainer.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	survivorController, err := survivor.NewController(survivorRepo, settlementRepo, survivorDomain.DefaultMilestoneRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	knowledgeRepo, err := knowledgeRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	knowledgeController, err := knowledge.NewController(knowledgeRepo, settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, survivorController, knowledgeController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func setup(t *testing.T, userID string) (string, string) {
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	rawSurvivor, status := requester.CreateSurvivor(userID, settlementID, "Thinker")
+	require.Equal(t, http.StatusOK, status)
+
+	var s survivorDomain.Survivor
+	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&s))
+
+	return settlementID, s.ID.String()
+}
+
+func collectiveCognition(t *testing.T, userID, settlementID string) int {
+	body, status := requester.GetSettlement(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+
+	var s settlementDomain.Settlement
+	require.NoError(t, json.NewDecoder(body).Decode(&s))
+
+	return s.CollectiveCognition
+}
+
+func TestLearnKnowledge_Success(t *testing.T) {
+	userID := "learn-knowledge-user"
+	settlementID, survivorID := setup(t, userID)
+
+	body, status := requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	var learned domain.SurvivorKnowledge
+	require.NoError(t, json.NewDecoder(body).Decode(&learned))
+	assert.Equal(t, basicKnowledgeID, learned.KnowledgeID.String())
+	assert.Equal(t, survivorID, learned.SurvivorID.String())
+	assert.Equal(t, 1, learned.Slot)
+	assert.Equal(t, 0, learned.Observations)
+	assert.Equal(t, 1, collectiveCognition(t, userID, settlementID))
+
+	body, status = requester.GetSurvivorKnowledge(userID, settlementID, survivorID)
+	require.Equal(t, http.StatusOK, status)
+
+	var held []domain.SurvivorKnowledge
+	require.NoError(t, json.NewDecoder(body).Decode(&held))
+	require.Len(t, held, 1)
+	assert.Equal(t, learned, held[0])
+}
+
+func TestLearnKnowledge_AlreadyKnown(t *testing.T) {
+	userID := "learn-known-knowledge-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestLearnKnowledge_TenetConditionNotMet(t *testing.T) {
+	userID := "tenet-unmet-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, advancedKnowledgeID)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, 0, collectiveCognition(t, userID, settlementID))
+}
+
+func TestLearnKnowledge_TenetConditionMet(t *testing.T) {
+	userID := "tenet-met-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	body, status := requester.LearnKnowledge(userID, settlementID, survivorID, advancedKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	var learned domain.SurvivorKnowledge
+	require.NoError(t, json.NewDecoder(body).Decode(&learned))
+	assert.Equal(t, 2, learned.Slot)
+	assert.Equal(t, 4, collectiveCognition(t, userID, settlementID))
+}
+
+func TestLearnKnowledge_UnknownKnowledge(t *testing.T) {
+	userID := "unknown-knowledge-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, testenv.UUIDString())
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestLearnKnowledge_UnknownSurvivor(t *testing.T) {
+	userID := "unknown-survivor-knowledge-user"
+	settlementID, _ := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, testenv.UUIDString(), basicKnowledgeID)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestObserveKnowledge_AdvancesAtFinalRank(t *testing.T) {
+	userID := "observe-knowledge-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	body, status := requester.ObserveKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	var result knowledge.ObservationResult
+	require.NoError(t, json.NewDecoder(body).Decode(&result))
+	assert.False(t, result.Advanced)
+	assert.Equal(t, 1, result.Observations)
+	assert.Equal(t, basicKnowledgeID, result.KnowledgeID.String())
+
+	body, status = requester.ObserveKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	require.NoError(t, json.NewDecoder(body).Decode(&result))
+	assert.True(t, result.Advanced)
+	assert.Equal(t, 0, result.Observations)
+	assert.Equal(t, advancedKnowledgeID, result.KnowledgeID.String())
+	assert.Equal(t, 1, result.Slot)
+	assert.Equal(t, 3, collectiveCognition(t, userID, settlementID))
+}
+
+func TestObserveKnowledge_AdvanceIntoHeldKnowledge(t *testing.T) {
+	userID := "observe-held-advance-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.LearnKnowledge(userID, settlementID, survivorID, advancedKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.ObserveKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.ObserveKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, 4, collectiveCognition(t, userID, settlementID))
+}
+
+func TestObserveKnowledge_NotHeld(t *testing.T) {
+	userID := "observe-unheld-knowledge-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.ObserveKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestForgetKnowledge_Success(t *testing.T) {
+	userID := "forget-knowledge-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.ForgetKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, 0, collectiveCognition(t, userID, settlementID))
+
+	body, status := requester.GetSurvivorKnowledge(userID, settlementID, survivorID)
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, "[]", body.String())
+}
+
+func TestForgetKnowledge_NotHeld(t *testing.T) {
+	userID := "forget-unheld-knowledge-user"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.ForgetKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestKnowledge_SettlementIsolation(t *testing.T) {
+	userID := "knowledge-owner"
+	settlementID, survivorID := setup(t, userID)
+
+	_, status := requester.LearnKnowledge(userID, settlementID, survivorID, basicKnowledgeID)
+	require.Equal(t, http.StatusOK, status)
+
+	intruder := "knowledge-intruder"
+	_, status = requester.GetSurvivorKnowledge(intruder, settlementID, survivorID)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.LearnKnowledge(intruder, settlementID, survivorID, advancedKnowledgeID)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.ObserveKnowledge(intruder, settlementID, survivorID, basicKnowledgeID)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.ForgetKnowledge(intruder, settlementID, survivorID, basicKnowledgeID)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	assert.Equal(t, 1, collectiveCognition(t, userID, settlementID))
+}
+
+func TestGetSurvivorKnowledge_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.GetSurvivorKnowledge("unauthorized", testenv.UUIDString(), testenv.UUIDString())
+
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const Slots = 2
+
+var (
+	ErrNotFound      = errors.New("knowledge not found")
+	ErrStale         = errors.New("knowledge was modified by another request")
+	ErrSlotsFull     = errors.New("survivor has no free knowledge slots")
+	ErrAlreadyKnown  = errors.New("survivor already holds this knowledge")
+	ErrNoObservation = errors.New("knowledge has no observation ranks")
+)
+
+type Definition struct {
+	ID           uuid.UUID
+	Cost         int
+	Tenet        bool
+	Condition    string
+	Observations int
+	Advance      uuid.UUID
+}
+
+type SurvivorKnowledge struct {
+	KnowledgeID  uuid.UUID `json:"knowledgeId"`
+	SurvivorID   uuid.UUID `json:"survivorId"`
+	Slot         int       `json:"slot"`
+	Observations int       `json:"observations"`
+	Cognition    int       `json:"cognition"`
+}
+
+func Requirements(condition string) ([]uuid.UUID, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return nil, nil
+	}
+
+	rest, ok := strings.CutPrefix(condition, "requires ")
+	if !ok {
+		return nil, fmt.Errorf("unsupported knowledge condition: %q", condition)
+	}
+
+	var required []uuid.UUID
+	for _, raw := range strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if raw == "and" {
+			continue
+		}
+		id, err := uuid.FromString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid knowledge id %q in condition: %w", raw, err)
+		}
+		required = append(required, id)
+	}
+
+	if len(required) == 0 {
+		return nil, fmt.Errorf("knowledge condition %q names no requirements", condition)
+	}
+
+	return required, nil
+}
+
+func Learn(survivorID uuid.UUID, held []SurvivorKnowledge, def Definition) (SurvivorKnowledge, error) {
+	known := make(map[uuid.UUID]bool, len(held))
+	used := make(map[int]bool, len(held))
+	for _, k := range held {
+		known[k.KnowledgeID] = true
+		used[k.Slot] = true
+	}
+
+	if known[def.ID] {
+		return SurvivorKnowledge{}, ErrAlreadyKnown
+	}
+
+	if def.Tenet {
+		required, err := Requirements(def.Condition)
+		if err != nil {
+			return SurvivorKnowledge{}, err
+		}
+		for _, id := range required {
+			if !known[id] {
+				return SurvivorKnowledge{}, fmt.Errorf("tenet condition not met: requires knowledge %s", id)
+			}
+		}
+	}
+
+	for slot := 1; slot <= Slots; slot++ {
+		if used[slot] {
+			continue
+		}
+		return SurvivorKnowledge{
+			KnowledgeID: def.ID,
+			SurvivorID:  survivorID,
+			Slot:        slot,
+			Cognition:   def.Cost,
+		}, nil
+	}
+
+	return SurvivorKnowledge{}, ErrSlotsFull
+}
+
+func Observe(current SurvivorKnowledge, def Definition) (SurvivorKnowledge, bool, error) {
+	if def.Observations <= 0 {
+		return current, false, ErrNoObservation
+	}
+
+	next := current
+	if next.Observations < def.Observations {
+		next.Observations++
+	}
+
+	return next, next.Observations >= def.Observations && def.Advance != uuid.Nil, nil
+}
+
+func Advance(current SurvivorKnowledge, next Definition) SurvivorKnowledge {
+	return SurvivorKnowledge{
+		KnowledgeID: next.ID,
+		SurvivorID:  current.SurvivorID,
+		Slot:        current.Slot,
+		Cognition:   next.Cost,
+	}
+}
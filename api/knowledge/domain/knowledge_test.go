@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirements(t *testing.T) {
+	first := uuid.Must(uuid.NewV7())
+	second := uuid.Must(uuid.NewV7())
+
+	required, err := Requirements("requires " + first.String() + ", " + second.String())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first, second}, required)
+
+	required, err = Requirements("")
+	require.NoError(t, err)
+	assert.Empty(t, required)
+
+	_, err = Requirements("when the moon is full")
+	require.Error(t, err)
+
+	_, err = Requirements("requires nothing")
+	require.Error(t, err)
+}
+
+func TestLearn_FillsFreeSlots(t *testing.T) {
+	survivorID := uuid.Must(uuid.NewV7())
+	var held []SurvivorKnowledge
+
+	for range Slots {
+		k, err := Learn(survivorID, held, Definition{ID: uuid.Must(uuid.NewV7()), Cost: 1})
+		require.NoError(t, err)
+		held = append(held, k)
+	}
+
+	_, err := Learn(survivorID, held, Definition{ID: uuid.Must(uuid.NewV7())})
+	require.ErrorIs(t, err, ErrSlotsFull)
+}
+
+func TestObserve_CapsAtFinalRank(t *testing.T) {
+	def := Definition{ID: uuid.Must(uuid.NewV7()), Observations: 1}
+	current := SurvivorKnowledge{KnowledgeID: def.ID}
+
+	next, advance, err := Observe(current, def)
+	require.NoError(t, err)
+	assert.False(t, advance)
+	assert.Equal(t, 1, next.Observations)
+
+	next, _, err = Observe(next, def)
+	require.NoError(t, err)
+	assert.Equal(t, 1, next.Observations)
+
+	_, _, err = Observe(current, Definition{})
+	require.ErrorIs(t, err, ErrNoObservation)
+}
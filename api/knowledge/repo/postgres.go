@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/failuretoload/datamonster/knowledge/domain"
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/store/postgres"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	getAll = `SELECT * FROM survivor_knowledge WHERE settlement_id = $1 AND survivor_id = $2 ORDER BY slot`
+	insert = `INSERT INTO survivor_knowledge (settlement_id, survivor_id, knowledge_id, slot, observations, cognition)
+SELECT settlement_id, external_id, $3, $4, $5, $6 FROM survivor WHERE settlement_id = $1 AND external_id = $2
+RETURNING *`
+	update = `UPDATE survivor_knowledge SET knowledge_id = $5, observations = $6, cognition = $7
+WHERE settlement_id = $1 AND survivor_id = $2 AND knowledge_id = $3 AND observations = $4
+RETURNING *`
+	remove = `DELETE FROM survivor_knowledge WHERE settlement_id = $1 AND survivor_id = $2 AND knowledge_id = $3 RETURNING cognition`
+
+	adjustCognition = `UPDATE settlement SET collective_cognition = collective_cognition + $2 WHERE external_id = $1`
+)
+
+type survivorKnowledge struct {
+	ID           int       `db:"id"`
+	SettlementID uuid.UUID `db:"settlement_id"`
+	SurvivorID   uuid.UUID `db:"survivor_id"`
+	KnowledgeID  uuid.UUID `db:"knowledge_id"`
+	Slot         int       `db:"slot"`
+	Observations int       `db:"observations"`
+	Cognition    int       `db:"cognition"`
+}
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("knowledge repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) All(ctx context.Context, settlementID, survivorID uuid.UUID) ([]domain.SurvivorKnowledge, error) {
+	rows, err := r.db.Query(ctx, getAll, settlementID, survivorID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query survivor knowledge")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+	defer rows.Close()
+
+	held, err := pgx.CollectRows(rows, pgx.RowToStructByName[survivorKnowledge])
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan survivor knowledge")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return toDTOList(held), nil
+}
+
+func (r Postgres) Learn(ctx context.Context, settlementID uuid.UUID, k domain.SurvivorKnowledge) (domain.SurvivorKnowledge, error) {
+	var learned domain.SurvivorKnowledge
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, insert, settlementID, k.SurvivorID, k.KnowledgeID, k.Slot, k.Observations, k.Cognition)
+		if err != nil {
+			return err
+		}
+
+		inserted, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[survivorKnowledge])
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		if postgres.IsUniqueViolation(err) {
+			return domain.ErrStale
+		}
+		if err != nil {
+			return err
+		}
+
+		learned = toDTO(inserted)
+		_, err = tx.Exec(ctx, adjustCognition, settlementID, learned.Cognition)
+		return err
+	})
+
+	return learned, safeError(ctx, "unable to learn knowledge", settlementID, err)
+}
+
+func (r Postgres) Save(ctx context.Context, settlementID uuid.UUID, before, after domain.SurvivorKnowledge) (domain.SurvivorKnowledge, error) {
+	var saved domain.SurvivorKnowledge
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, update,
+			settlementID,
+			before.SurvivorID,
+			before.KnowledgeID,
+			before.Observations,
+			after.KnowledgeID,
+			after.Observations,
+			after.Cognition,
+		)
+		if err != nil {
+			return err
+		}
+
+		updated, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[survivorKnowledge])
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrStale
+		}
+		if postgres.IsUniqueViolation(err) {
+			return domain.ErrAlreadyKnown
+		}
+		if err != nil {
+			return err
+		}
+
+		saved = toDTO(updated)
+		_, err = tx.Exec(ctx, adjustCognition, settlementID, saved.Cognition-before.Cognition)
+		return err
+	})
+
+	return saved, safeError(ctx, "unable to save knowledge", settlementID, err)
+}
+
+func (r Postgres) Forget(ctx context.Context, settlementID, survivorID, knowledgeID uuid.UUID) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var cognition int
+		err := tx.QueryRow(ctx, remove, settlementID, survivorID, knowledgeID).Scan(&cognition)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, adjustCognition, settlementID, -cognition)
+		return err
+	})
+
+	return safeError(ctx, "unable to forget knowledge", settlementID, err)
+}
+
+func safeError(ctx context.Context, msg string, settlementID uuid.UUID, err error) error {
+	if err == nil || errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrStale) || errors.Is(err, domain.ErrAlreadyKnown) {
+		return err
+	}
+
+	safeErr := errors.New(msg)
+	logger.Error(ctx, safeErr.Error(),
+		logger.SettlementID(settlementID.String()),
+		logger.ErrorField(err),
+	)
+	return safeErr
+}
+
+func toDTO(k survivorKnowledge) domain.SurvivorKnowledge {
+	return domain.SurvivorKnowledge{
+		KnowledgeID:  k.KnowledgeID,
+		SurvivorID:   k.SurvivorID,
+		Slot:         k.Slot,
+		Observations: k.Observations,
+		Cognition:    k.Cognition,
+	}
+}
+
+func toDTOList(held []survivorKnowledge) []domain.SurvivorKnowledge {
+	dtos := make([]domain.SurvivorKnowledge, len(held))
+
+	for i, k := range held {
+		dtos[i] = toDTO(k)
+	}
+
+	return dtos
+}
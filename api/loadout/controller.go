@@ -0,0 +1,284 @@
+package loadout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/loadout/domain"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		Get(ctx context.Context, settlementID, survivorID uuid.UUID) (domain.Grid, error)
+		Save(ctx context.Context, settlementID, survivorID uuid.UUID, grid domain.Grid) error
+		Presets(ctx context.Context, settlementID uuid.UUID) ([]domain.Preset, error)
+		Preset(ctx context.Context, settlementID, presetID uuid.UUID) (domain.Preset, error)
+		SavePreset(ctx context.Context, settlementID uuid.UUID, p domain.Preset) (domain.Preset, error)
+		DeletePreset(ctx context.Context, settlementID, presetID uuid.UUID) error
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Glossary interface {
+		AllGear() []glossary.Gear
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+		gear        map[string]domain.Gear
+	}
+	LoadoutRequest struct {
+		Grid []string `json:"grid"`
+	}
+	ApplyPresetRequest struct {
+		Preset uuid.UUID `json:"preset"`
+	}
+	PresetRequest struct {
+		Name     string     `json:"name"`
+		Survivor *uuid.UUID `json:"survivor,omitempty"`
+		Grid     []string   `json:"grid,omitempty"`
+	}
+)
+
+func NewController(r Repo, s Settlements, g Glossary) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("glossary cannot be nil")
+	}
+
+	gear := map[string]domain.Gear{}
+	for _, card := range g.AllGear() {
+		converted, err := fromGear(card)
+		if err != nil {
+			return nil, err
+		}
+		gear[card.ID] = converted
+	}
+
+	return &Controller{records: r, settlements: s, gear: gear}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Get("/settlements/{id}/survivors/{survivorID}/loadout", c.getLoadout)
+		gr.Put("/settlements/{id}/survivors/{survivorID}/loadout", c.saveLoadout)
+		gr.Post("/settlements/{id}/survivors/{survivorID}/loadout/preset", c.applyPreset)
+		gr.Get("/settlements/{id}/loadouts/presets", c.getPresets)
+		gr.Post("/settlements/{id}/loadouts/presets", c.createPreset)
+		gr.Delete("/settlements/{id}/loadouts/presets/{presetID}", c.deletePreset)
+	})
+}
+
+func (c Controller) getLoadout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	grid, err := c.records.Get(ctx, request.SettlementID(ctx), survivorID)
+	if err != nil {
+		writeLoadoutError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, domain.Summarize(survivorID, grid, c.gear))
+}
+
+func (c Controller) saveLoadout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	var body LoadoutRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	grid, err := c.toGrid(body.Grid)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	c.save(ctx, w, survivorID, grid)
+}
+
+func (c Controller) applyPreset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	var body ApplyPresetRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	preset, err := c.records.Preset(ctx, request.SettlementID(ctx), body.Preset)
+	if err != nil {
+		writeLoadoutError(ctx, w, err)
+		return
+	}
+
+	c.save(ctx, w, survivorID, preset.Grid)
+}
+
+func (c Controller) save(ctx context.Context, w http.ResponseWriter, survivorID uuid.UUID, grid domain.Grid) {
+	if err := c.records.Save(ctx, request.SettlementID(ctx), survivorID, grid); err != nil {
+		writeLoadoutError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, domain.Summarize(survivorID, grid, c.gear))
+}
+
+func (c Controller) getPresets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	presets, err := c.records.Presets(ctx, request.SettlementID(ctx))
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving loadout presets: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, presets)
+}
+
+func (c Controller) createPreset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body PresetRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	name := strings.TrimSpace(body.Name)
+	if name == "" || len(name) > domain.MaxPresetName {
+		response.BadRequest(ctx, w, fmt.Errorf("name must be between 1 and %d characters", domain.MaxPresetName))
+		return
+	}
+	if (body.Survivor == nil) == (body.Grid == nil) {
+		response.BadRequest(ctx, w, fmt.Errorf("provide exactly one of survivor or grid"))
+		return
+	}
+
+	var grid domain.Grid
+	if body.Survivor != nil {
+		saved, err := c.records.Get(ctx, request.SettlementID(ctx), *body.Survivor)
+		if err != nil {
+			writeLoadoutError(ctx, w, err)
+			return
+		}
+		grid = saved
+	} else {
+		parsed, err := c.toGrid(body.Grid)
+		if err != nil {
+			response.BadRequest(ctx, w, err)
+			return
+		}
+		grid = parsed
+	}
+
+	preset, err := c.records.SavePreset(ctx, request.SettlementID(ctx), domain.Preset{Name: name, Grid: grid})
+	if err != nil {
+		writeLoadoutError(ctx, w, err)
+		return
+	}
+
+	response.OK(ctx, w, preset)
+}
+
+func (c Controller) deletePreset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	presetID, err := uuid.FromString(chi.URLParam(r, "presetID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid preset id"))
+		return
+	}
+
+	if err := c.records.DeletePreset(ctx, request.SettlementID(ctx), presetID); err != nil {
+		writeLoadoutError(ctx, w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (c Controller) toGrid(slots []string) (domain.Grid, error) {
+	if len(slots) != domain.GridSize {
+		return domain.Grid{}, fmt.Errorf("grid must have %d slots", domain.GridSize)
+	}
+
+	var grid domain.Grid
+	copy(grid[:], slots)
+	if err := grid.Validate(c.gear); err != nil {
+		return domain.Grid{}, err
+	}
+	return grid, nil
+}
+
+func fromGear(g glossary.Gear) (domain.Gear, error) {
+	edges := domain.Edges{
+		Top:    g.Affinities.Top,
+		Right:  g.Affinities.Right,
+		Bottom: g.Affinities.Bottom,
+		Left:   g.Affinities.Left,
+	}
+	for _, color := range []string{edges.Top, edges.Right, edges.Bottom, edges.Left} {
+		if color != "" && !domain.ValidColor(color) {
+			return domain.Gear{}, fmt.Errorf("gear %s: invalid affinity color %s", g.ID, color)
+		}
+	}
+	for _, loc := range g.ArmorLocations {
+		if !domain.ValidHitLocation(loc) {
+			return domain.Gear{}, fmt.Errorf("gear %s: invalid hit location %s", g.ID, loc)
+		}
+	}
+
+	converted := domain.Gear{
+		ID:             g.ID,
+		Armor:          g.Armor,
+		ArmorLocations: g.ArmorLocations,
+		Edges:          edges,
+	}
+	if g.Bonus != nil {
+		converted.Bonus = &domain.Bonus{Requires: g.Bonus.Requires, Effect: g.Bonus.Effect}
+	}
+	return converted, nil
+}
+
+func writeLoadoutError(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSurvivorNotFound), errors.Is(err, domain.ErrPresetNotFound):
+		response.NotFound(ctx, w, err)
+	case errors.Is(err, domain.ErrGearUnavailable), errors.Is(err, domain.ErrDuplicatePreset):
+		response.Conflict(ctx, w, err)
+	default:
+		response.InternalServerError(ctx, w, err)
+	}
+}
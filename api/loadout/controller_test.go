@@ -0,0 +1,273 @@
+package loadout_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/crafting"
+	craftingRepo "github.com/failuretoload/datamonster/crafting/repo"
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/loadout"
+	"github.com/failuretoload/datamonster/loadout/domain"
+	loadoutRepo "github.com/failuretoload/datamonster/loadout/repo"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/survivor"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
+	survivorRepo "github.com/failuretoload/datamonster/survivor/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for loadout tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryStub := testenv.NewGlossaryStub(`{"gear":[
+		{"id":"helm","name":"Helm","source":"core","keywords":["armor"],"armor":2,"armorLocations":["head"],
+			"affinities":{"bottom":"red"}},
+		{"id":"vest","name":"Vest","source":"core","keywords":["armor"],"armor":1,"armorLocations":["body","waist"],
+			"affinities":{"top":"red","right":"blue"}},
+		{"id":"dagger","name":"Dagger","source":"core","keywords":["weapon"],
+			"affinities":{"left":"blue"},"bonus":{"requires":{"blue":1},"effect":"+1 accuracy"}},
+		{"id":"cloak","name":"Cloak","source":"core","keywords":["armor"],"armor":1,"armorLocations":["all"],"affinities":{}}
+	]}`)
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	survivorController, err := survivor.NewController(survivorRepo, settlementRepo, survivordomain.DefaultMilestoneRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	storageRepo, err := craftingRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	craftingController, err := crafting.NewController(storageRepo, settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gridRepo, err := loadoutRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	loadoutController, err := loadout.NewController(gridRepo, settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, survivorController, craftingController, loadoutController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func setup(t *testing.T, userID string, gear map[string]int) (string, []string) {
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for id, n := range gear {
+		body, _ := json.Marshal(map[string]any{"kind": "gear", "id": id, "quantity": n})
+		raw, status := requester.AdjustStorage(userID, settlementID, string(body))
+		require.Equal(t, http.StatusOK, status, raw.String())
+	}
+
+	var survivors []string
+	for _, name := range []string{"First", "Second"} {
+		raw, status := requester.CreateSurvivor(userID, settlementID, name)
+		require.Equal(t, http.StatusOK, status)
+		var s survivordomain.Survivor
+		require.NoError(t, json.NewDecoder(raw).Decode(&s))
+		survivors = append(survivors, s.ID.String())
+	}
+
+	return settlementID, survivors
+}
+
+func save(t *testing.T, userID, settlementID, survivorID, body string) domain.Loadout {
+	raw, status := requester.SaveLoadout(userID, settlementID, survivorID, body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var l domain.Loadout
+	require.NoError(t, json.NewDecoder(raw).Decode(&l))
+	return l
+}
+
+func TestLoadout_EmptyByDefault(t *testing.T) {
+	userID := "loadout-empty-user"
+	settlementID, survivors := setup(t, userID, nil)
+
+	raw, status := requester.GetLoadout(userID, settlementID, survivors[0])
+	require.Equal(t, http.StatusOK, status)
+
+	var l domain.Loadout
+	require.NoError(t, json.NewDecoder(raw).Decode(&l))
+	assert.Equal(t, domain.Grid{}, l.Grid)
+	assert.Equal(t, 0, l.Armor["head"])
+	assert.Empty(t, l.Bonuses)
+}
+
+func TestLoadout_ArmorAndAffinities(t *testing.T) {
+	userID := "loadout-summary-user"
+	settlementID, survivors := setup(t, userID, map[string]int{"helm": 1, "vest": 1, "dagger": 1, "cloak": 1})
+
+	l := save(t, userID, settlementID, survivors[0], `{"grid":["helm","","","vest","dagger","","cloak","",""]}`)
+	assert.Equal(t, map[string]int{"head": 3, "arms": 1, "body": 2, "waist": 2, "legs": 1}, l.Armor)
+	assert.Equal(t, map[string]int{"red": 1, "green": 0, "blue": 1}, l.Affinities)
+	assert.Equal(t, []domain.ActiveBonus{{Gear: "dagger", Effect: "+1 accuracy"}}, l.Bonuses)
+
+	raw, status := requester.GetLoadout(userID, settlementID, survivors[0])
+	require.Equal(t, http.StatusOK, status)
+	var fetched domain.Loadout
+	require.NoError(t, json.NewDecoder(raw).Decode(&fetched))
+	assert.Equal(t, l, fetched)
+}
+
+func TestLoadout_StorageLimitsGear(t *testing.T) {
+	userID := "loadout-stock-user"
+	settlementID, survivors := setup(t, userID, map[string]int{"helm": 1})
+
+	save(t, userID, settlementID, survivors[0], `{"grid":["helm","","","","","","","",""]}`)
+
+	_, status := requester.SaveLoadout(userID, settlementID, survivors[1], `{"grid":["helm","","","","","","","",""]}`)
+	assert.Equal(t, http.StatusConflict, status, "the only helm is already carried")
+
+	_, status = requester.SaveLoadout(userID, settlementID, survivors[0], `{"grid":["helm","helm","","","","","","",""]}`)
+	assert.Equal(t, http.StatusConflict, status)
+
+	_, status = requester.SaveLoadout(userID, settlementID, survivors[0], `{"grid":["","","","","","","","",""]}`)
+	require.Equal(t, http.StatusOK, status)
+	save(t, userID, settlementID, survivors[1], `{"grid":["helm","","","","","","","",""]}`)
+}
+
+func TestLoadout_EquippedGearStaysInStorage(t *testing.T) {
+	userID := "loadout-equipped-user"
+	settlementID, survivors := setup(t, userID, map[string]int{"helm": 2})
+
+	save(t, userID, settlementID, survivors[0], `{"grid":["helm","","","","","","","",""]}`)
+
+	_, status := requester.AdjustStorage(userID, settlementID, `{"kind":"gear","id":"helm","quantity":-2}`)
+	assert.Equal(t, http.StatusConflict, status, "one helm is still carried")
+
+	raw, status := requester.AdjustStorage(userID, settlementID, `{"kind":"gear","id":"helm","quantity":-1}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+}
+
+func TestLoadout_BadGrids(t *testing.T) {
+	userID := "loadout-invalid-user"
+	settlementID, survivors := setup(t, userID, map[string]int{"helm": 1})
+
+	for _, body := range []string{
+		`{"grid":["helm"]}`,
+		`{"grid":["","","","","","","","","",""]}`,
+		`{"grid":["lantern-halberd","","","","","","","",""]}`,
+		`{}`,
+	} {
+		_, status := requester.SaveLoadout(userID, settlementID, survivors[0], body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+
+	_, status := requester.SaveLoadout(userID, settlementID, testenv.UUIDString(), `{"grid":["","","","","","","","",""]}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestLoadout_Presets(t *testing.T) {
+	userID := "loadout-preset-user"
+	settlementID, survivors := setup(t, userID, map[string]int{"vest": 2, "dagger": 2})
+
+	source := save(t, userID, settlementID, survivors[0], `{"grid":["","","","vest","dagger","","","",""]}`)
+
+	raw, status := requester.CreateLoadoutPreset(userID, settlementID, `{"name":"Striker","survivor":"`+survivors[0]+`"}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+	var preset domain.Preset
+	require.NoError(t, json.NewDecoder(raw).Decode(&preset))
+	assert.Equal(t, "Striker", preset.Name)
+	assert.Equal(t, source.Grid, preset.Grid)
+
+	_, status = requester.CreateLoadoutPreset(userID, settlementID, `{"name":"Striker","grid":["","","","","","","","",""]}`)
+	assert.Equal(t, http.StatusConflict, status)
+
+	raw, status = requester.ApplyLoadoutPreset(userID, settlementID, survivors[1], preset.ID.String())
+	require.Equal(t, http.StatusOK, status, raw.String())
+	var copied domain.Loadout
+	require.NoError(t, json.NewDecoder(raw).Decode(&copied))
+	assert.Equal(t, source.Grid, copied.Grid)
+	assert.Equal(t, source.Bonuses, copied.Bonuses)
+
+	raw, status = requester.GetLoadoutPresets(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var presets []domain.Preset
+	require.NoError(t, json.NewDecoder(raw).Decode(&presets))
+	require.Len(t, presets, 1)
+
+	_, status = requester.DeleteLoadoutPreset(userID, settlementID, preset.ID.String())
+	require.Equal(t, http.StatusNoContent, status)
+	_, status = requester.ApplyLoadoutPreset(userID, settlementID, survivors[1], preset.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestLoadout_PresetRespectsStorage(t *testing.T) {
+	userID := "loadout-preset-stock-user"
+	settlementID, survivors := setup(t, userID, map[string]int{"helm": 1})
+
+	raw, status := requester.CreateLoadoutPreset(userID, settlementID, `{"name":"Helmed","grid":["helm","","","","","","","",""]}`)
+	require.Equal(t, http.StatusOK, status)
+	var preset domain.Preset
+	require.NoError(t, json.NewDecoder(raw).Decode(&preset))
+
+	_, status = requester.ApplyLoadoutPreset(userID, settlementID, survivors[0], preset.ID.String())
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.ApplyLoadoutPreset(userID, settlementID, survivors[1], preset.ID.String())
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestLoadout_SettlementIsolation(t *testing.T) {
+	settlementID, survivors := setup(t, "loadout-owner", nil)
+
+	_, status := requester.GetLoadout("loadout-intruder", settlementID, survivors[0])
+	assert.Equal(t, http.StatusNotFound, status)
+
+	other, err := requester.CreateSettlement("loadout-owner")
+	require.NoError(t, err)
+	_, status = requester.GetLoadout("loadout-owner", other, survivors[0])
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestLoadout_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.GetLoadout("unauthorized", testenv.UUIDString(), testenv.UUIDString())
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	GridWidth = 3
+	GridSize  = GridWidth * GridWidth
+
+	AllLocations = "all"
+
+	MaxPresetName = 100
+)
+
+var HitLocations = []string{"head", "arms", "body", "waist", "legs"}
+
+var Colors = []string{"red", "green", "blue"}
+
+var (
+	ErrSurvivorNotFound = errors.New("survivor not found")
+	ErrPresetNotFound   = errors.New("loadout preset not found")
+	ErrGearUnavailable  = errors.New("not enough gear in settlement storage")
+	ErrDuplicatePreset  = errors.New("a loadout preset with that name already exists")
+)
+
+type Grid [GridSize]string
+
+type Edges struct {
+	Top    string `json:"top,omitempty"`
+	Right  string `json:"right,omitempty"`
+	Bottom string `json:"bottom,omitempty"`
+	Left   string `json:"left,omitempty"`
+}
+
+type Bonus struct {
+	Requires map[string]int `json:"requires"`
+	Effect   string         `json:"effect"`
+}
+
+type Gear struct {
+	ID             string
+	Armor          int
+	ArmorLocations []string
+	Edges          Edges
+	Bonus          *Bonus
+}
+
+type ActiveBonus struct {
+	Gear   string `json:"gear"`
+	Effect string `json:"effect"`
+}
+
+type Loadout struct {
+	SurvivorID uuid.UUID      `json:"survivorId"`
+	Grid       Grid           `json:"grid"`
+	Armor      map[string]int `json:"armor"`
+	Affinities map[string]int `json:"affinities"`
+	Bonuses    []ActiveBonus  `json:"bonuses"`
+}
+
+type Preset struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Grid Grid      `json:"grid"`
+}
+
+func ValidColor(c string) bool {
+	return slices.Contains(Colors, c)
+}
+
+func ValidHitLocation(l string) bool {
+	return l == AllLocations || slices.Contains(HitLocations, l)
+}
+
+func (g Grid) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, id := range g {
+		if id != "" {
+			counts[id]++
+		}
+	}
+	return counts
+}
+
+func (g Grid) Validate(gear map[string]Gear) error {
+	for slot, id := range g {
+		if id == "" {
+			continue
+		}
+		if _, ok := gear[id]; !ok {
+			return fmt.Errorf("slot %d: unknown gear %s", slot, id)
+		}
+	}
+	return nil
+}
+
+func (g Grid) CheckStock(stock, inUse map[string]int) error {
+	counts := g.Counts()
+	for _, id := range slices.Sorted(maps.Keys(counts)) {
+		free := stock[id] - inUse[id]
+		if need := counts[id]; need > free {
+			return fmt.Errorf("%w: %s needs %d, %d free", ErrGearUnavailable, id, need, max(free, 0))
+		}
+	}
+	return nil
+}
+
+func Summarize(survivorID uuid.UUID, g Grid, gear map[string]Gear) Loadout {
+	l := Loadout{
+		SurvivorID: survivorID,
+		Grid:       g,
+		Armor:      map[string]int{},
+		Affinities: map[string]int{},
+		Bonuses:    []ActiveBonus{},
+	}
+	for _, loc := range HitLocations {
+		l.Armor[loc] = 0
+	}
+	for _, c := range Colors {
+		l.Affinities[c] = 0
+	}
+
+	for _, id := range g {
+		card, ok := gear[id]
+		if !ok {
+			continue
+		}
+		for _, loc := range card.ArmorLocations {
+			if loc == AllLocations {
+				for _, each := range HitLocations {
+					l.Armor[each] += card.Armor
+				}
+				continue
+			}
+			l.Armor[loc] += card.Armor
+		}
+	}
+
+	for slot := range g {
+		card, ok := gear[g[slot]]
+		if !ok {
+			continue
+		}
+		if slot%GridWidth < GridWidth-1 {
+			if right, ok := gear[g[slot+1]]; ok && card.Edges.Right != "" && card.Edges.Right == right.Edges.Left {
+				l.Affinities[card.Edges.Right]++
+			}
+		}
+		if slot+GridWidth < GridSize {
+			if below, ok := gear[g[slot+GridWidth]]; ok && card.Edges.Bottom != "" && card.Edges.Bottom == below.Edges.Top {
+				l.Affinities[card.Edges.Bottom]++
+			}
+		}
+	}
+
+	for _, id := range g {
+		card, ok := gear[id]
+		if !ok || card.Bonus == nil {
+			continue
+		}
+		if satisfied(card.Bonus.Requires, l.Affinities) {
+			l.Bonuses = append(l.Bonuses, ActiveBonus{Gear: id, Effect: card.Bonus.Effect})
+		}
+	}
+
+	return l
+}
+
+func satisfied(requires, affinities map[string]int) bool {
+	for color, n := range requires {
+		if affinities[color] < n {
+			return false
+		}
+	}
+	return true
+}
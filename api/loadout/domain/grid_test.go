@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var catalog = map[string]Gear{
+	"helm":   {ID: "helm", Armor: 2, ArmorLocations: []string{"head"}, Edges: Edges{Bottom: "red"}},
+	"vest":   {ID: "vest", Armor: 1, ArmorLocations: []string{"body", "waist"}, Edges: Edges{Top: "red", Right: "blue"}},
+	"cloak":  {ID: "cloak", Armor: 1, ArmorLocations: []string{AllLocations}},
+	"dagger": {ID: "dagger", Edges: Edges{Left: "blue"}, Bonus: &Bonus{Requires: map[string]int{"blue": 1}, Effect: "+1 accuracy"}},
+	"sword":  {ID: "sword", Edges: Edges{Left: "green"}, Bonus: &Bonus{Requires: map[string]int{"red": 1, "green": 1}, Effect: "+2 strength"}},
+}
+
+func TestSummarize_Armor(t *testing.T) {
+	g := Grid{"helm", "", "", "vest", "", "", "cloak"}
+
+	l := Summarize(uuid.Nil, g, catalog)
+	assert.Equal(t, map[string]int{"head": 3, "arms": 1, "body": 2, "waist": 2, "legs": 1}, l.Armor)
+}
+
+func TestSummarize_Affinities(t *testing.T) {
+	// helm sits above vest (red link); vest sits left of dagger (blue link).
+	g := Grid{
+		"helm", "", "",
+		"vest", "dagger", "",
+		"", "", "",
+	}
+
+	l := Summarize(uuid.Nil, g, catalog)
+	assert.Equal(t, map[string]int{"red": 1, "green": 0, "blue": 1}, l.Affinities)
+	assert.Equal(t, []ActiveBonus{{Gear: "dagger", Effect: "+1 accuracy"}}, l.Bonuses)
+}
+
+func TestSummarize_EdgesMustTouch(t *testing.T) {
+	// vest's right edge is at the end of a row, so it does not wrap to dagger.
+	g := Grid{
+		"", "", "vest",
+		"dagger", "", "",
+		"", "", "",
+	}
+
+	l := Summarize(uuid.Nil, g, catalog)
+	assert.Equal(t, 0, l.Affinities["blue"])
+	assert.Empty(t, l.Bonuses)
+}
+
+func TestSummarize_UnmetBonus(t *testing.T) {
+	g := Grid{"helm", "", "", "vest", "sword"}
+
+	l := Summarize(uuid.Nil, g, catalog)
+	assert.Equal(t, 1, l.Affinities["red"])
+	assert.Empty(t, l.Bonuses, "sword needs a green link too")
+}
+
+func TestGridValidate(t *testing.T) {
+	assert.NoError(t, Grid{"helm"}.Validate(catalog))
+	assert.Error(t, Grid{"", "", "lantern-halberd"}.Validate(catalog))
+}
+
+func TestGridCheckStock(t *testing.T) {
+	g := Grid{"dagger", "dagger", "helm"}
+
+	require.NoError(t, g.CheckStock(map[string]int{"dagger": 2, "helm": 1}, nil))
+	assert.ErrorIs(t, g.CheckStock(map[string]int{"dagger": 2, "helm": 1}, map[string]int{"dagger": 1}), ErrGearUnavailable)
+	assert.ErrorIs(t, g.CheckStock(map[string]int{"dagger": 2}, nil), ErrGearUnavailable)
+	assert.NoError(t, Grid{}.CheckStock(nil, nil))
+}
@@ -0,0 +1,293 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/failuretoload/datamonster/loadout/domain"
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/store/postgres"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	lockSettlement = `SELECT 1 FROM settlement WHERE external_id = $1 FOR UPDATE`
+	survivorExists = `SELECT EXISTS (SELECT 1 FROM survivor WHERE settlement_id = $1 AND external_id = $2)`
+	getLoadout     = `SELECT grid FROM survivor_loadout WHERE settlement_id = $1 AND survivor_id = $2`
+	getOtherGrids  = `SELECT grid FROM survivor_loadout WHERE settlement_id = $1 AND survivor_id <> $2`
+	getGearStock   = `SELECT item_id, quantity FROM settlement_storage WHERE settlement_id = $1 AND kind = 'gear'`
+	upsertLoadout  = `INSERT INTO survivor_loadout (survivor_id, settlement_id, grid) VALUES ($2, $1, $3)
+ON CONFLICT (survivor_id) DO UPDATE SET grid = EXCLUDED.grid, updated_at = NOW()`
+	getPresets = `SELECT external_id, name, grid FROM settlement_loadout_preset
+WHERE settlement_id = $1
+ORDER BY name`
+	getPreset = `SELECT external_id, name, grid FROM settlement_loadout_preset
+WHERE settlement_id = $1 AND external_id = $2`
+	insertPreset = `INSERT INTO settlement_loadout_preset (settlement_id, name, grid) VALUES ($1, $2, $3)
+RETURNING external_id`
+	deletePreset = `DELETE FROM settlement_loadout_preset WHERE settlement_id = $1 AND external_id = $2`
+)
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("loadout repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) Get(ctx context.Context, settlementID, survivorID uuid.UUID) (domain.Grid, error) {
+	if err := requireSurvivor(ctx, r.db, settlementID, survivorID); err != nil {
+		return domain.Grid{}, err
+	}
+
+	var grid []string
+	err := r.db.QueryRow(ctx, getLoadout, settlementID, survivorID).Scan(&grid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Grid{}, nil
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query survivor loadout")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Grid{}, safeErr
+	}
+
+	return toGrid(grid), nil
+}
+
+func (r Postgres) Save(ctx context.Context, settlementID, survivorID uuid.UUID, grid domain.Grid) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, lockSettlement, settlementID)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to lock settlement")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		if err := requireSurvivor(ctx, tx, settlementID, survivorID); err != nil {
+			return err
+		}
+
+		stock, err := gearStock(ctx, tx, settlementID)
+		if err != nil {
+			return err
+		}
+		inUse, err := gearInUse(ctx, tx, settlementID, survivorID)
+		if err != nil {
+			return err
+		}
+		if err := grid.CheckStock(stock, inUse); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, upsertLoadout, settlementID, survivorID, grid[:])
+		if err != nil {
+			safeErr := fmt.Errorf("unable to save survivor loadout")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		return nil
+	})
+}
+
+func (r Postgres) Presets(ctx context.Context, settlementID uuid.UUID) ([]domain.Preset, error) {
+	rows, err := r.db.Query(ctx, getPresets, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query loadout presets")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	presets, err := pgx.CollectRows(rows, scanPreset)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan loadout presets")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return presets, nil
+}
+
+func (r Postgres) Preset(ctx context.Context, settlementID, presetID uuid.UUID) (domain.Preset, error) {
+	rows, err := r.db.Query(ctx, getPreset, settlementID, presetID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query loadout preset")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Preset{}, safeErr
+	}
+
+	preset, err := pgx.CollectExactlyOneRow(rows, scanPreset)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Preset{}, domain.ErrPresetNotFound
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan loadout preset")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Preset{}, safeErr
+	}
+
+	return preset, nil
+}
+
+func (r Postgres) SavePreset(ctx context.Context, settlementID uuid.UUID, p domain.Preset) (domain.Preset, error) {
+	err := r.db.QueryRow(ctx, insertPreset, settlementID, p.Name, p.Grid[:]).Scan(&p.ID)
+	if postgres.IsUniqueViolation(err) {
+		return domain.Preset{}, domain.ErrDuplicatePreset
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to save loadout preset")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Preset{}, safeErr
+	}
+
+	return p, nil
+}
+
+func (r Postgres) DeletePreset(ctx context.Context, settlementID, presetID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, deletePreset, settlementID, presetID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to delete loadout preset")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrPresetNotFound
+	}
+
+	return nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func requireSurvivor(ctx context.Context, q querier, settlementID, survivorID uuid.UUID) error {
+	var exists bool
+	err := q.QueryRow(ctx, survivorExists, settlementID, survivorID).Scan(&exists)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query survivor")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+	if !exists {
+		return domain.ErrSurvivorNotFound
+	}
+	return nil
+}
+
+func gearStock(ctx context.Context, q querier, settlementID uuid.UUID) (map[string]int, error) {
+	rows, err := q.Query(ctx, getGearStock, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query gear storage")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	stock := map[string]int{}
+	var (
+		id       string
+		quantity int
+	)
+	_, err = pgx.ForEachRow(rows, []any{&id, &quantity}, func() error {
+		stock[id] = quantity
+		return nil
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read gear storage")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return stock, nil
+}
+
+func gearInUse(ctx context.Context, q querier, settlementID, survivorID uuid.UUID) (map[string]int, error) {
+	rows, err := q.Query(ctx, getOtherGrids, settlementID, survivorID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query survivor loadouts")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	inUse := map[string]int{}
+	var grid []string
+	_, err = pgx.ForEachRow(rows, []any{&grid}, func() error {
+		for id, n := range toGrid(grid).Counts() {
+			inUse[id] += n
+		}
+		return nil
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read survivor loadouts")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return inUse, nil
+}
+
+func scanPreset(row pgx.CollectableRow) (domain.Preset, error) {
+	var (
+		p    domain.Preset
+		grid []string
+	)
+	err := row.Scan(&p.ID, &p.Name, &grid)
+	p.Grid = toGrid(grid)
+	return p, err
+}
+
+func toGrid(slots []string) domain.Grid {
+	var g domain.Grid
+	copy(g[:], slots)
+	return g
+}
@@ -12,16 +12,36 @@ import (
 	"time"
 
 	"github.com/failuretoload/datamonster/auth"
+	"github.com/failuretoload/datamonster/crafting"
+	craftingrepo "github.com/failuretoload/datamonster/crafting/repo"
+	"github.com/failuretoload/datamonster/dice"
+	dicerepo "github.com/failuretoload/datamonster/dice/repo"
+	"github.com/failuretoload/datamonster/endeavor"
+	endeavorrepo "github.com/failuretoload/datamonster/endeavor/repo"
+	"github.com/failuretoload/datamonster/eventdeck"
+	eventdeckrepo "github.com/failuretoload/datamonster/eventdeck/repo"
 	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/journal"
+	journalrepo "github.com/failuretoload/datamonster/journal/repo"
+	"github.com/failuretoload/datamonster/knowledge"
+	knowledgerepo "github.com/failuretoload/datamonster/knowledge/repo"
+	"github.com/failuretoload/datamonster/loadout"
+	loadoutrepo "github.com/failuretoload/datamonster/loadout/repo"
 	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/monster"
+	monsterrepo "github.com/failuretoload/datamonster/monster/repo"
 	"github.com/failuretoload/datamonster/server"
 	"github.com/failuretoload/datamonster/settlement"
 	settlementrepo "github.com/failuretoload/datamonster/settlement/repo"
 	"github.com/failuretoload/datamonster/store/cache"
+	"github.com/failuretoload/datamonster/store/memory"
 	"github.com/failuretoload/datamonster/store/postgres"
 	"github.com/failuretoload/datamonster/store/postgres/migrator"
 	"github.com/failuretoload/datamonster/survivor"
+	survivordomain "github.com/failuretoload/datamonster/survivor/domain"
 	survivorrepo "github.com/failuretoload/datamonster/survivor/repo"
+	"github.com/failuretoload/datamonster/token"
+	tokenrepo "github.com/failuretoload/datamonster/token/repo"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -31,7 +51,21 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	sessions, err := cache.NewSessionStore(ctx)
+	dbsn := os.Getenv("DBSN")
+	if dbsn == "" {
+		exit(fmt.Errorf("dbsn is required"))
+	}
+
+	pool, err := postgres.NewConnectionPool(ctx, dbsn)
+	if err != nil {
+		exit(fmt.Errorf("failed to initialize connection pool: %w", err))
+	}
+
+	if err := migrator.Migrate(ctx, pool); err != nil {
+		exit(fmt.Errorf("failed to run migrations: %w", err))
+	}
+
+	sessions, introspections, err := sessionStore(ctx, os.Getenv("SESSION_STORE"), pool)
 	if err != nil {
 		exit(fmt.Errorf("failed to initialize session store: %w", err))
 	}
@@ -44,6 +78,17 @@ func main() {
 		TokenURL:      os.Getenv("TOKEN_URL"),
 		ClientURL:     os.Getenv("CLIENT_URL"),
 		Sessions:      sessions,
+
+		IntrospectionCache: introspections,
+		JWKSURL:            os.Getenv("JWKS_URL"),
+		Audience:           os.Getenv("JWT_AUDIENCE"),
+	}
+	if raw := os.Getenv("INTROSPECTION_CACHE_TTL"); raw != "" {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil {
+			exit(fmt.Errorf("invalid INTROSPECTION_CACHE_TTL: %w", err))
+		}
+		authConfig.IntrospectionTTL = ttl
 	}
 
 	authController, err := authConfig.Controller()
@@ -51,26 +96,18 @@ func main() {
 		exit(fmt.Errorf("failed to initialize auth controller: %w", err))
 	}
 
-	authorizer, err := authConfig.Authorizer()
+	tokenRepo, err := tokenrepo.New(pool)
 	if err != nil {
-		exit(fmt.Errorf("failed to initialize authorizer: %w", err))
+		exit(fmt.Errorf("failed to initialize token repo: %w", err))
 	}
 
-	dbsn := os.Getenv("DBSN")
-	if dbsn == "" {
-		exit(fmt.Errorf("dbsn is required"))
-	}
-
-	pool, err := postgres.NewConnectionPool(ctx, dbsn)
+	authConfig.Tokens = tokenRepo
+	authorizer, err := authConfig.Authorizer()
 	if err != nil {
-		exit(fmt.Errorf("failed to initialize connection pool: %w", err))
-	}
-
-	if err := migrator.Migrate(ctx, pool); err != nil {
-		exit(fmt.Errorf("failed to run migrations: %w", err))
+		exit(fmt.Errorf("failed to initialize authorizer: %w", err))
 	}
 
-	controllers, err := makeControllers(pool)
+	controllers, err := makeControllers(pool, tokenRepo)
 	if err != nil {
 		exit(fmt.Errorf("failed to create controller: %w", err))
 	}
@@ -80,6 +117,8 @@ func main() {
 		exit(errors.New("CLIENT_URL environment variable is required"))
 	}
 
+	controllers = append(controllers, authController)
+
 	srv, err := server.New(authController, authorizer, []string{clientURL}, controllers)
 	if err != nil {
 		exit(fmt.Errorf("failed to create server: %w", err))
@@ -112,12 +151,40 @@ func exit(err error) {
 	os.Exit(1)
 }
 
-func makeControllers(pool *pgxpool.Pool) ([]server.Controller, error) {
+func sessionStore(ctx context.Context, kind string, pool *pgxpool.Pool) (auth.SessionStore, auth.IntrospectionCache, error) {
+	switch kind {
+	case "", "valkey":
+		store, err := cache.NewSessionStore(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store.Introspections(), nil
+	case "postgres":
+		store, err := postgres.NewSessionStore(pool)
+		if err != nil {
+			return nil, nil, err
+		}
+		go store.RunSweeper(ctx, postgres.DefaultSweepInterval)
+		return store, store.Introspections(), nil
+	case "memory":
+		store := memory.NewSessionStore(memory.DefaultSweepInterval)
+		return store, store.Introspections(), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown session store %q", kind)
+	}
+}
+
+func makeControllers(pool *pgxpool.Pool, tokenRepo *tokenrepo.Postgres) ([]server.Controller, error) {
+	glossaryController, err := glossary.NewController(os.Getenv("GLOSSARY_SERVER"))
+	if err != nil {
+		return nil, err
+	}
+
 	settlementRepo, err := settlementrepo.New(pool)
 	if err != nil {
 		return nil, err
 	}
-	settlementController, err := settlement.NewController(settlementRepo)
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
 	if err != nil {
 		return nil, err
 	}
@@ -127,12 +194,97 @@ func makeControllers(pool *pgxpool.Pool) ([]server.Controller, error) {
 		return nil, err
 	}
 
-	survivorController, err := survivor.NewController(survivorRepo)
+	milestones, err := milestoneRules(os.Getenv("SURVIVOR_MILESTONES"))
 	if err != nil {
 		return nil, err
 	}
 
-	glossaryController, err := glossary.NewController(os.Getenv("GLOSSARY_SERVER"))
+	survivorController, err := survivor.NewController(survivorRepo, settlementRepo, milestones)
+	if err != nil {
+		return nil, err
+	}
+
+	knowledgeRepo, err := knowledgerepo.New(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	knowledgeController, err := knowledge.NewController(knowledgeRepo, settlementRepo, glossaryController)
+	if err != nil {
+		return nil, err
+	}
+
+	eventDeckRepo, err := eventdeckrepo.New(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	eventDeckController, err := eventdeck.NewController(eventDeckRepo, settlementRepo, glossaryController)
+	if err != nil {
+		return nil, err
+	}
+
+	diceRepo, err := dicerepo.New(pool, survivorRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	diceController, err := dice.NewController(diceRepo, settlementRepo, glossaryController, milestones)
+	if err != nil {
+		return nil, err
+	}
+
+	endeavorRepo, err := endeavorrepo.New(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	endeavorController, err := endeavor.NewController(endeavorRepo, settlementRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	craftingRepo, err := craftingrepo.New(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	craftingController, err := crafting.NewController(craftingRepo, settlementRepo, glossaryController)
+	if err != nil {
+		return nil, err
+	}
+
+	loadoutRepo, err := loadoutrepo.New(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	loadoutController, err := loadout.NewController(loadoutRepo, settlementRepo, glossaryController)
+	if err != nil {
+		return nil, err
+	}
+
+	monsterRepo, err := monsterrepo.New(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	monsterController, err := monster.NewController(monsterRepo, settlementRepo, glossaryController)
+	if err != nil {
+		return nil, err
+	}
+
+	journalRepo, err := journalrepo.New(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	journalController, err := journal.NewController(journalRepo, settlementRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenController, err := token.NewController(tokenRepo)
 	if err != nil {
 		return nil, err
 	}
@@ -141,5 +293,28 @@ func makeControllers(pool *pgxpool.Pool) ([]server.Controller, error) {
 		settlementController,
 		survivorController,
 		glossaryController,
+		knowledgeController,
+		eventDeckController,
+		diceController,
+		endeavorController,
+		craftingController,
+		loadoutController,
+		monsterController,
+		journalController,
+		tokenController,
 	}, nil
 }
+
+func milestoneRules(path string) (survivordomain.MilestoneRules, error) {
+	if path == "" {
+		return survivordomain.DefaultMilestoneRules, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open milestone rules: %w", err)
+	}
+	defer f.Close()
+
+	return survivordomain.ReadMilestoneRules(f)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+)
+
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if request.TokenScope(ctx) != "" {
+			response.Forbidden(ctx, w, fmt.Errorf("access tokens cannot use this route"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type Settlements interface {
+	Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+}
+
+func SettlementID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id, err := request.SettlementIDFromURL(r)
+		if err != nil {
+			response.InternalServerError(ctx, w, err)
+			return
+		}
+
+		ctx = request.SetSettlementID(ctx, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func RequireSettlement(s Settlements) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			settlement, err := s.Get(ctx, request.UserID(ctx), request.SettlementID(ctx))
+			if err != nil {
+				response.InternalServerError(ctx, w, fmt.Errorf("unable to retrieve settlement: %w", err))
+				return
+			}
+			if settlement == nil {
+				response.NotFound(ctx, w, fmt.Errorf("settlement not found"))
+				return
+			}
+
+			ctx = request.SetSettlement(ctx, settlement)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package monster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/monster/domain"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		Progression(ctx context.Context, settlementID uuid.UUID) (domain.Progression, error)
+		SetUnlocked(ctx context.Context, settlementID uuid.UUID, monsterID string, unlocked bool) error
+		Schedule(ctx context.Context, settlementID uuid.UUID, m domain.Monster, level, year int) (domain.Encounter, error)
+		RecordVictory(ctx context.Context, settlementID uuid.UUID, m domain.Monster, level int, recordedBy string) (domain.Defeat, error)
+	}
+	Settlements interface {
+		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	}
+	Glossary interface {
+		Monsters() []glossary.Monster
+	}
+	Controller struct {
+		records     Repo
+		settlements Settlements
+		catalog     []domain.Monster
+		monsters    map[string]domain.Monster
+	}
+	UnlockRequest struct {
+		Unlocked bool `json:"unlocked"`
+	}
+	EncounterRequest struct {
+		Monster string `json:"monster"`
+		Level   int    `json:"level"`
+		Year    *int   `json:"year,omitempty"`
+	}
+	VictoryRequest struct {
+		Monster string `json:"monster"`
+		Level   int    `json:"level"`
+	}
+)
+
+func NewController(r Repo, s Settlements, g Glossary) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("glossary cannot be nil")
+	}
+
+	var catalog []domain.Monster
+	monsters := map[string]domain.Monster{}
+	for _, entry := range g.Monsters() {
+		m := domain.Monster{ID: entry.ID, Name: entry.Name, Type: entry.Type, Levels: entry.Levels}
+		if err := m.Validate(); err != nil {
+			return nil, err
+		}
+		catalog = append(catalog, m)
+		monsters[m.ID] = m
+	}
+
+	return &Controller{records: r, settlements: s, catalog: catalog, monsters: monsters}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
+		gr.Get("/settlements/{id}/monsters", c.getProgression)
+		gr.Get("/settlements/{id}/monsters/huntable", c.getHuntable)
+		gr.Put("/settlements/{id}/monsters/{monsterID}", c.setUnlocked)
+		gr.Post("/settlements/{id}/monsters/encounters", c.scheduleEncounter)
+		gr.Post("/settlements/{id}/monsters/defeats", c.recordVictory)
+	})
+}
+
+func (c Controller) getProgression(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	progression, err := c.records.Progression(ctx, request.SettlementID(ctx))
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving monster progression: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, progression)
+}
+
+func (c Controller) getHuntable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	settlement := request.Settlement(ctx)
+	year := settlement.CurrentYear
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			response.BadRequest(ctx, w, fmt.Errorf("invalid year"))
+			return
+		}
+		year = parsed
+	}
+
+	progression, err := c.records.Progression(ctx, settlement.ID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving monster progression: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, domain.Hunts(c.catalog, progression, year))
+}
+
+func (c Controller) setUnlocked(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	m, ok := c.monsters[chi.URLParam(r, "monsterID")]
+	if !ok {
+		response.NotFound(ctx, w, fmt.Errorf("monster not found"))
+		return
+	}
+
+	var body UnlockRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	settlementID := request.SettlementID(ctx)
+	if err := c.records.SetUnlocked(ctx, settlementID, m.ID, body.Unlocked); err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error updating settlement monster: %w", err))
+		return
+	}
+
+	progression, err := c.records.Progression(ctx, settlementID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving monster progression: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, progression)
+}
+
+func (c Controller) scheduleEncounter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body EncounterRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	m, ok := c.monsters[body.Monster]
+	if !ok {
+		response.BadRequest(ctx, w, fmt.Errorf("unknown monster: %s", body.Monster))
+		return
+	}
+
+	settlement := request.Settlement(ctx)
+	year := settlement.CurrentYear
+	if body.Year != nil {
+		year = *body.Year
+	}
+
+	encounter, err := c.records.Schedule(ctx, settlement.ID, m, body.Level, year)
+	if errors.Is(err, domain.ErrInvalidEncounter) {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+	if errors.Is(err, domain.ErrMonsterLocked) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error scheduling nemesis encounter: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, encounter)
+}
+
+func (c Controller) recordVictory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body VictoryRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	m, ok := c.monsters[body.Monster]
+	if !ok {
+		response.BadRequest(ctx, w, fmt.Errorf("unknown monster: %s", body.Monster))
+		return
+	}
+	if !m.ValidLevel(body.Level) {
+		response.BadRequest(ctx, w, fmt.Errorf("level must be between 1 and %d", m.Levels))
+		return
+	}
+
+	defeat, err := c.records.RecordVictory(ctx, request.SettlementID(ctx), m, body.Level, request.UserID(ctx))
+	if errors.Is(err, domain.ErrMonsterLocked) || errors.Is(err, domain.ErrNotHuntable) || errors.Is(err, domain.ErrEncounterNotFound) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error recording monster defeat: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, defeat)
+}
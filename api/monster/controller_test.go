@@ -0,0 +1,282 @@
+package monster_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/glossary"
+	"github.com/failuretoload/datamonster/monster"
+	"github.com/failuretoload/datamonster/monster/domain"
+	monsterRepo "github.com/failuretoload/datamonster/monster/repo"
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/settlement"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requester *testenv.Requester
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for monster tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	glossaryStub := testenv.NewGlossaryStub(`{"monsters":[
+		{"id":"lion","name":"White Lion","source":"core","type":"quarry","levels":3},
+		{"id":"antelope","name":"Screaming Antelope","source":"core","type":"quarry","levels":3},
+		{"id":"butcher","name":"Butcher","source":"core","type":"nemesis","levels":3}
+	]}`)
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementRepo, err := settlementRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	progressionRepo, err := monsterRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	monsterController, err := monster.NewController(progressionRepo, settlementRepo, glossaryController)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{settlementController, monsterController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func huntable(t *testing.T, userID, settlementID, year string) []domain.Huntable {
+	raw, status := requester.GetHuntable(userID, settlementID, year)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var hunts []domain.Huntable
+	require.NoError(t, json.NewDecoder(raw).Decode(&hunts))
+	return hunts
+}
+
+func TestMonsters_EmptyProgression(t *testing.T) {
+	userID := "monster-empty-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	raw, status := requester.GetMonsters(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+
+	var p domain.Progression
+	require.NoError(t, json.NewDecoder(raw).Decode(&p))
+	assert.Empty(t, p.Unlocked)
+	assert.Empty(t, p.Encounters)
+	assert.Empty(t, p.Defeats)
+	assert.Empty(t, huntable(t, userID, settlementID, ""))
+}
+
+func TestMonsters_UnlockQuarry(t *testing.T) {
+	userID := "monster-unlock-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	raw, status := requester.UnlockMonster(userID, settlementID, "lion", true)
+	require.Equal(t, http.StatusOK, status, raw.String())
+	var p domain.Progression
+	require.NoError(t, json.NewDecoder(raw).Decode(&p))
+	assert.Equal(t, []string{"lion"}, p.Unlocked)
+
+	_, status = requester.UnlockMonster(userID, settlementID, "lion", true)
+	require.Equal(t, http.StatusOK, status, "unlocking twice is harmless")
+
+	hunts := huntable(t, userID, settlementID, "")
+	require.Len(t, hunts, 1)
+	assert.Equal(t, domain.Huntable{Monster: "lion", Name: "White Lion", Type: domain.TypeQuarry, Levels: []int{1}}, hunts[0])
+
+	_, status = requester.UnlockMonster(userID, settlementID, "lion", false)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, huntable(t, userID, settlementID, ""))
+
+	_, status = requester.UnlockMonster(userID, settlementID, "dragon-king", true)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestMonsters_QuarryVictoriesOpenLevels(t *testing.T) {
+	userID := "monster-quarry-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.RecordVictory(userID, settlementID, "lion", 1)
+	assert.Equal(t, http.StatusConflict, status, "locked quarries cannot be hunted")
+
+	_, status = requester.UnlockMonster(userID, settlementID, "lion", true)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.RecordVictory(userID, settlementID, "lion", 2)
+	assert.Equal(t, http.StatusConflict, status, "level 2 opens after a level 1 victory")
+
+	raw, status := requester.RecordVictory(userID, settlementID, "lion", 1)
+	require.Equal(t, http.StatusOK, status, raw.String())
+	var d domain.Defeat
+	require.NoError(t, json.NewDecoder(raw).Decode(&d))
+	assert.Equal(t, "lion", d.Monster)
+	assert.Equal(t, domain.TypeQuarry, d.Type)
+	assert.Equal(t, 1, d.Level)
+	assert.Equal(t, 0, d.Year)
+	assert.Equal(t, userID, d.RecordedBy)
+
+	hunts := huntable(t, userID, settlementID, "")
+	require.Len(t, hunts, 1)
+	assert.Equal(t, []int{1, 2}, hunts[0].Levels)
+
+	_, status = requester.RecordVictory(userID, settlementID, "lion", 4)
+	assert.Equal(t, http.StatusBadRequest, status)
+	_, status = requester.RecordVictory(userID, settlementID, "dragon-king", 1)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestMonsters_NemesisEncounter(t *testing.T) {
+	userID := "monster-nemesis-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.ScheduleEncounter(userID, settlementID, `{"monster":"butcher","level":1}`)
+	assert.Equal(t, http.StatusConflict, status, "nemeses are unlocked before they are scheduled")
+
+	_, status = requester.UnlockMonster(userID, settlementID, "butcher", true)
+	require.Equal(t, http.StatusOK, status)
+
+	raw, status := requester.ScheduleEncounter(userID, settlementID, `{"monster":"butcher","level":1}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+	var now domain.Encounter
+	require.NoError(t, json.NewDecoder(raw).Decode(&now))
+
+	raw, status = requester.ScheduleEncounter(userID, settlementID, `{"monster":"butcher","level":2,"year":5}`)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	raw, status = requester.GetTimeline(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var timeline []settlementdomain.TimelineEntry
+	require.NoError(t, json.NewDecoder(raw).Decode(&timeline))
+	require.Len(t, timeline, 2)
+	assert.Equal(t, "Nemesis Encounter - Butcher Lvl 1", timeline[0].Event)
+	assert.Equal(t, 5, timeline[1].Year)
+
+	hunts := huntable(t, userID, settlementID, "")
+	require.Len(t, hunts, 1)
+	assert.Equal(t, []int{1}, hunts[0].Levels)
+	assert.Equal(t, now.ID, *hunts[0].Encounter)
+	assert.Len(t, huntable(t, userID, settlementID, "5"), 1)
+
+	_, status = requester.RecordVictory(userID, settlementID, "butcher", 2)
+	assert.Equal(t, http.StatusConflict, status, "the level 2 encounter is not this year")
+
+	raw, status = requester.RecordVictory(userID, settlementID, "butcher", 1)
+	require.Equal(t, http.StatusOK, status, raw.String())
+	var d domain.Defeat
+	require.NoError(t, json.NewDecoder(raw).Decode(&d))
+	require.NotNil(t, d.Encounter)
+	assert.Equal(t, now.ID, *d.Encounter)
+
+	assert.Empty(t, huntable(t, userID, settlementID, ""))
+
+	raw, status = requester.GetTimeline(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.NewDecoder(raw).Decode(&timeline))
+	assert.True(t, timeline[0].Completed)
+	assert.False(t, timeline[1].Completed)
+
+	_, status = requester.RecordVictory(userID, settlementID, "butcher", 1)
+	assert.Equal(t, http.StatusConflict, status, "an encounter is only won once")
+}
+
+func TestMonsters_InvalidEncounters(t *testing.T) {
+	userID := "monster-invalid-encounter-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	_, status := requester.UnlockMonster(userID, settlementID, "lion", true)
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.UnlockMonster(userID, settlementID, "butcher", true)
+	require.Equal(t, http.StatusOK, status)
+
+	for _, body := range []string{
+		`{"monster":"lion","level":1}`,
+		`{"monster":"butcher","level":0}`,
+		`{"monster":"butcher","level":4}`,
+		`{"monster":"butcher","level":1,"year":-1}`,
+		`{"monster":"unknown","level":1}`,
+	} {
+		_, status := requester.ScheduleEncounter(userID, settlementID, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+}
+
+func TestMonsters_NemesisVictoryFeedsStats(t *testing.T) {
+	userID := "monster-stats-user"
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.UnlockMonster(userID, settlementID, "lion", true)
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.UnlockMonster(userID, settlementID, "butcher", true)
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.ScheduleEncounter(userID, settlementID, `{"monster":"butcher","level":1}`)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.RecordVictory(userID, settlementID, "lion", 1)
+	require.Equal(t, http.StatusOK, status)
+	_, status = requester.RecordVictory(userID, settlementID, "butcher", 1)
+	require.Equal(t, http.StatusOK, status)
+
+	raw, status := requester.GetSettlementStats(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var stats settlementdomain.Stats
+	require.NoError(t, json.NewDecoder(raw).Decode(&stats))
+	assert.Equal(t, 1, stats.QuarryVictories)
+	assert.Equal(t, 1, stats.NemesisVictories)
+
+	keys := []string{}
+	for _, m := range stats.Milestones {
+		keys = append(keys, m.Key)
+	}
+	assert.Contains(t, keys, "first-nemesis")
+}
+
+func TestMonsters_SettlementIsolation(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("monster-owner")
+	require.NoError(t, err)
+
+	_, status := requester.GetMonsters("monster-intruder", settlementID)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.UnlockMonster("monster-intruder", settlementID, "lion", true)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.RecordVictory("monster-intruder", settlementID, "lion", 1)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.ScheduleEncounter("monster-intruder", settlementID, `{}`)
+	assert.Equal(t, http.StatusNotFound, status, "ownership is checked before the body")
+}
+
+func TestMonsters_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.GetMonsters("unauthorized", testenv.UUIDString())
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
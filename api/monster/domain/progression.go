@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	TypeQuarry  = "quarry"
+	TypeNemesis = "nemesis"
+)
+
+var (
+	ErrMonsterLocked     = errors.New("monster has not been unlocked")
+	ErrNotHuntable       = errors.New("monster cannot be fought at that level this year")
+	ErrEncounterNotFound = errors.New("nemesis encounter not found")
+	ErrInvalidEncounter  = errors.New("invalid nemesis encounter")
+)
+
+type Monster struct {
+	ID     string
+	Name   string
+	Type   string
+	Levels int
+}
+
+type Encounter struct {
+	ID       uuid.UUID `json:"id"`
+	Monster  string    `json:"monster"`
+	Level    int       `json:"level"`
+	Year     int       `json:"year"`
+	Timeline uuid.UUID `json:"timeline"`
+	Defeated bool      `json:"defeated"`
+}
+
+type Defeat struct {
+	ID         uuid.UUID  `json:"id"`
+	Monster    string     `json:"monster"`
+	Type       string     `json:"type"`
+	Level      int        `json:"level"`
+	Year       int        `json:"year"`
+	Encounter  *uuid.UUID `json:"encounter,omitempty"`
+	RecordedBy string     `json:"recordedBy"`
+	At         time.Time  `json:"at"`
+}
+
+type Progression struct {
+	Unlocked   []string    `json:"unlocked"`
+	Encounters []Encounter `json:"encounters"`
+	Defeats    []Defeat    `json:"defeats"`
+}
+
+type Huntable struct {
+	Monster   string     `json:"monster"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Levels    []int      `json:"levels"`
+	Encounter *uuid.UUID `json:"encounter,omitempty"`
+}
+
+func ValidType(t string) bool {
+	return t == TypeQuarry || t == TypeNemesis
+}
+
+func (m Monster) Validate() error {
+	if !ValidType(m.Type) {
+		return fmt.Errorf("monster %s has invalid type %q", m.ID, m.Type)
+	}
+	if m.Levels < 1 {
+		return fmt.Errorf("monster %s must have at least one level", m.ID)
+	}
+	return nil
+}
+
+func (m Monster) ValidLevel(level int) bool {
+	return level >= 1 && level <= m.Levels
+}
+
+func EncounterEvent(m Monster, level int) string {
+	return fmt.Sprintf("Nemesis Encounter - %s Lvl %d", m.Name, level)
+}
+
+func NewEncounter(m Monster, unlocked bool, level, year, currentYear int) (Encounter, error) {
+	if m.Type != TypeNemesis {
+		return Encounter{}, fmt.Errorf("%w: %s is not a nemesis", ErrInvalidEncounter, m.Name)
+	}
+	if !m.ValidLevel(level) {
+		return Encounter{}, fmt.Errorf("%w: level must be between 1 and %d", ErrInvalidEncounter, m.Levels)
+	}
+	if year < currentYear {
+		return Encounter{}, fmt.Errorf("%w: cannot schedule before lantern year %d", ErrInvalidEncounter, currentYear)
+	}
+	if !unlocked {
+		return Encounter{}, ErrMonsterLocked
+	}
+	return Encounter{Monster: m.ID, Level: level, Year: year}, nil
+}
+
+func (p Progression) IsUnlocked(monster string) bool {
+	for _, id := range p.Unlocked {
+		if id == monster {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Progression) HighestDefeated(monster string) int {
+	highest := 0
+	for _, d := range p.Defeats {
+		if d.Monster == monster && d.Level > highest {
+			highest = d.Level
+		}
+	}
+	return highest
+}
+
+func Hunts(catalog []Monster, p Progression, year int) []Huntable {
+	hunts := []Huntable{}
+	for _, m := range catalog {
+		switch m.Type {
+		case TypeQuarry:
+			if !p.IsUnlocked(m.ID) {
+				continue
+			}
+			top := min(p.HighestDefeated(m.ID)+1, m.Levels)
+			levels := make([]int, top)
+			for i := range levels {
+				levels[i] = i + 1
+			}
+			hunts = append(hunts, Huntable{Monster: m.ID, Name: m.Name, Type: m.Type, Levels: levels})
+		case TypeNemesis:
+			for _, e := range p.Encounters {
+				if e.Monster != m.ID || e.Year != year || e.Defeated {
+					continue
+				}
+				id := e.ID
+				hunts = append(hunts, Huntable{
+					Monster:   m.ID,
+					Name:      m.Name,
+					Type:      m.Type,
+					Levels:    []int{e.Level},
+					Encounter: &id,
+				})
+			}
+		}
+	}
+	return hunts
+}
+
+func Victory(m Monster, p Progression, level, year int) (Huntable, error) {
+	if m.Type == TypeQuarry && !p.IsUnlocked(m.ID) {
+		return Huntable{}, ErrMonsterLocked
+	}
+	for _, h := range Hunts([]Monster{m}, p, year) {
+		for _, l := range h.Levels {
+			if l == level {
+				return h, nil
+			}
+		}
+	}
+	if m.Type == TypeNemesis {
+		return Huntable{}, ErrEncounterNotFound
+	}
+	return Huntable{}, ErrNotHuntable
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	lion     = Monster{ID: "lion", Name: "White Lion", Type: TypeQuarry, Levels: 3}
+	antelope = Monster{ID: "antelope", Name: "Screaming Antelope", Type: TypeQuarry, Levels: 3}
+	butcher  = Monster{ID: "butcher", Name: "Butcher", Type: TypeNemesis, Levels: 3}
+)
+
+func TestMonsterValidate(t *testing.T) {
+	assert.NoError(t, lion.Validate())
+	assert.Error(t, Monster{ID: "x", Type: "boss", Levels: 1}.Validate())
+	assert.Error(t, Monster{ID: "x", Type: TypeQuarry}.Validate())
+}
+
+func TestNewEncounter(t *testing.T) {
+	e, err := NewEncounter(butcher, true, 2, 4, 3)
+	require.NoError(t, err)
+	assert.Equal(t, Encounter{Monster: "butcher", Level: 2, Year: 4}, e)
+
+	_, err = NewEncounter(lion, true, 1, 4, 3)
+	assert.ErrorIs(t, err, ErrInvalidEncounter, "quarries are not scheduled")
+	_, err = NewEncounter(butcher, true, 4, 4, 3)
+	assert.ErrorIs(t, err, ErrInvalidEncounter)
+	_, err = NewEncounter(butcher, true, 1, 2, 3)
+	assert.ErrorIs(t, err, ErrInvalidEncounter, "the past cannot be scheduled")
+	_, err = NewEncounter(butcher, false, 1, 4, 3)
+	assert.ErrorIs(t, err, ErrMonsterLocked)
+}
+
+func TestHuntsOpensQuarryLevelsWithVictories(t *testing.T) {
+	p := Progression{Unlocked: []string{"lion"}}
+	hunts := Hunts([]Monster{lion, antelope}, p, 1)
+	require.Len(t, hunts, 1, "locked quarries are not huntable")
+	assert.Equal(t, []int{1}, hunts[0].Levels)
+
+	p.Defeats = []Defeat{{Monster: "lion", Level: 1}}
+	assert.Equal(t, []int{1, 2}, Hunts([]Monster{lion}, p, 1)[0].Levels)
+
+	p.Defeats = append(p.Defeats, Defeat{Monster: "lion", Level: 3})
+	assert.Equal(t, []int{1, 2, 3}, Hunts([]Monster{lion}, p, 1)[0].Levels, "capped at the highest level")
+}
+
+func TestHuntsOnlyOffersNemesisInItsYear(t *testing.T) {
+	scheduled := uuid.Must(uuid.NewV7())
+	beaten := uuid.Must(uuid.NewV7())
+	p := Progression{
+		Unlocked: []string{"butcher"},
+		Encounters: []Encounter{
+			{ID: scheduled, Monster: "butcher", Level: 2, Year: 4},
+			{ID: beaten, Monster: "butcher", Level: 1, Year: 4, Defeated: true},
+			{ID: uuid.Must(uuid.NewV7()), Monster: "butcher", Level: 3, Year: 9},
+		},
+	}
+
+	assert.Empty(t, Hunts([]Monster{butcher}, p, 3))
+
+	hunts := Hunts([]Monster{butcher}, p, 4)
+	require.Len(t, hunts, 1)
+	assert.Equal(t, []int{2}, hunts[0].Levels)
+	assert.Equal(t, scheduled, *hunts[0].Encounter)
+}
+
+func TestVictory(t *testing.T) {
+	encounter := uuid.Must(uuid.NewV7())
+	p := Progression{
+		Unlocked:   []string{"lion", "butcher"},
+		Encounters: []Encounter{{ID: encounter, Monster: "butcher", Level: 1, Year: 2}},
+	}
+
+	h, err := Victory(lion, p, 1, 2)
+	require.NoError(t, err)
+	assert.Nil(t, h.Encounter)
+
+	_, err = Victory(lion, p, 2, 2)
+	assert.ErrorIs(t, err, ErrNotHuntable)
+
+	_, err = Victory(antelope, p, 1, 2)
+	assert.ErrorIs(t, err, ErrMonsterLocked)
+
+	h, err = Victory(butcher, p, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, encounter, *h.Encounter)
+
+	_, err = Victory(butcher, p, 1, 3)
+	assert.ErrorIs(t, err, ErrEncounterNotFound)
+}
+
+func TestEncounterEvent(t *testing.T) {
+	assert.Equal(t, "Nemesis Encounter - Butcher Lvl 2", EncounterEvent(butcher, 2))
+}
@@ -0,0 +1,257 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/monster/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	lockSettlement = `SELECT year FROM settlement WHERE external_id = $1 FOR UPDATE`
+	getUnlocked    = `SELECT monster_id FROM settlement_monster WHERE settlement_id = $1 ORDER BY monster_id`
+	unlockMonster  = `INSERT INTO settlement_monster (settlement_id, monster_id) VALUES ($1, $2)
+ON CONFLICT (settlement_id, monster_id) DO NOTHING`
+	removeMonster = `DELETE FROM settlement_monster WHERE settlement_id = $1 AND monster_id = $2`
+	getEncounters = `SELECT external_id, monster_id, level, year, timeline_id, defeated
+FROM settlement_nemesis_encounter
+WHERE settlement_id = $1
+ORDER BY year, id`
+	getDefeats = `SELECT external_id, monster_id, monster_type, level, year, encounter_id, recorded_by, created_at
+FROM settlement_monster_defeat
+WHERE settlement_id = $1
+ORDER BY id`
+	insertTimelineEntry = `INSERT INTO settlement_timeline (settlement_id, year, event) VALUES ($1, $2, $3)
+RETURNING external_id`
+	insertEncounter = `INSERT INTO settlement_nemesis_encounter (settlement_id, monster_id, level, year, timeline_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING external_id`
+	insertDefeat = `INSERT INTO settlement_monster_defeat (settlement_id, monster_id, monster_type, level, year, encounter_id, recorded_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING external_id, created_at`
+	defeatEncounter = `UPDATE settlement_nemesis_encounter SET defeated = true
+WHERE settlement_id = $1 AND external_id = $2
+RETURNING timeline_id`
+	completeTimelineEntry = `UPDATE settlement_timeline SET completed = true WHERE external_id = $1`
+)
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("monster repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) Progression(ctx context.Context, settlementID uuid.UUID) (domain.Progression, error) {
+	return readProgression(ctx, r.db, settlementID)
+}
+
+func (r Postgres) SetUnlocked(ctx context.Context, settlementID uuid.UUID, monsterID string, unlocked bool) error {
+	query := removeMonster
+	if unlocked {
+		query = unlockMonster
+	}
+
+	_, err := r.db.Exec(ctx, query, settlementID, monsterID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to update settlement monster")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+	return nil
+}
+
+func (r Postgres) Schedule(ctx context.Context, settlementID uuid.UUID, m domain.Monster, level, year int) (domain.Encounter, error) {
+	var encounter domain.Encounter
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		currentYear, err := lock(ctx, tx, settlementID)
+		if err != nil {
+			return err
+		}
+
+		progression, err := readProgression(ctx, tx, settlementID)
+		if err != nil {
+			return err
+		}
+
+		encounter, err = domain.NewEncounter(m, progression.IsUnlocked(m.ID), level, year, currentYear)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx, insertTimelineEntry, settlementID, year, domain.EncounterEvent(m, level)).Scan(&encounter.Timeline)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to add encounter to timeline")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		err = tx.QueryRow(ctx, insertEncounter, settlementID, m.ID, level, year, encounter.Timeline).Scan(&encounter.ID)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to schedule nemesis encounter")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Encounter{}, err
+	}
+
+	return encounter, nil
+}
+
+func (r Postgres) RecordVictory(ctx context.Context, settlementID uuid.UUID, m domain.Monster, level int, recordedBy string) (domain.Defeat, error) {
+	defeat := domain.Defeat{Monster: m.ID, Type: m.Type, Level: level, RecordedBy: recordedBy}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		year, err := lock(ctx, tx, settlementID)
+		if err != nil {
+			return err
+		}
+		defeat.Year = year
+
+		progression, err := readProgression(ctx, tx, settlementID)
+		if err != nil {
+			return err
+		}
+
+		hunt, err := domain.Victory(m, progression, level, year)
+		if err != nil {
+			return err
+		}
+		defeat.Encounter = hunt.Encounter
+
+		err = tx.QueryRow(ctx, insertDefeat,
+			settlementID,
+			m.ID,
+			m.Type,
+			level,
+			year,
+			defeat.Encounter,
+			recordedBy,
+		).Scan(&defeat.ID, &defeat.At)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to record monster defeat")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		if defeat.Encounter == nil {
+			return nil
+		}
+
+		var timelineID uuid.UUID
+		err = tx.QueryRow(ctx, defeatEncounter, settlementID, *defeat.Encounter).Scan(&timelineID)
+		if err == nil {
+			_, err = tx.Exec(ctx, completeTimelineEntry, timelineID)
+		}
+		if err != nil {
+			safeErr := fmt.Errorf("unable to close nemesis encounter")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Defeat{}, err
+	}
+
+	return defeat, nil
+}
+
+func lock(ctx context.Context, tx pgx.Tx, settlementID uuid.UUID) (int, error) {
+	var year int
+	err := tx.QueryRow(ctx, lockSettlement, settlementID).Scan(&year)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to lock settlement")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return 0, safeErr
+	}
+	return year, nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func readProgression(ctx context.Context, q querier, settlementID uuid.UUID) (domain.Progression, error) {
+	p := domain.Progression{}
+
+	rows, err := q.Query(ctx, getUnlocked, settlementID)
+	if err == nil {
+		p.Unlocked, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query unlocked monsters")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Progression{}, safeErr
+	}
+
+	rows, err = q.Query(ctx, getEncounters, settlementID)
+	if err == nil {
+		p.Encounters, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Encounter, error) {
+			var e domain.Encounter
+			err := row.Scan(&e.ID, &e.Monster, &e.Level, &e.Year, &e.Timeline, &e.Defeated)
+			return e, err
+		})
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query nemesis encounters")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Progression{}, safeErr
+	}
+
+	rows, err = q.Query(ctx, getDefeats, settlementID)
+	if err == nil {
+		p.Defeats, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Defeat, error) {
+			var d domain.Defeat
+			err := row.Scan(&d.ID, &d.Monster, &d.Type, &d.Level, &d.Year, &d.Encounter, &d.RecordedBy, &d.At)
+			return d, err
+		})
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query monster defeats")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Progression{}, safeErr
+	}
+
+	return p, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
 	"github.com/go-chi/chi/v5"
 	"github.com/gofrs/uuid/v5"
 )
@@ -13,6 +14,8 @@ const (
 	userIDKey        contextKey = "userId"
 	correlationIDKey contextKey = "correlationID"
 	settlementIDKey  contextKey = "settlementID"
+	settlementKey    contextKey = "settlement"
+	tokenScopeKey    contextKey = "tokenScope"
 )
 
 type (
@@ -30,6 +33,17 @@ func SetUserID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, userIDKey, id)
 }
 
+func TokenScope(ctx context.Context) string {
+	if val, ok := ctx.Value(tokenScopeKey).(string); ok {
+		return val
+	}
+	return ""
+}
+
+func SetTokenScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, tokenScopeKey, scope)
+}
+
 func SettlementID(ctx context.Context) uuid.UUID {
 	if val, ok := ctx.Value(settlementIDKey).(uuid.UUID); ok {
 		return val
@@ -42,6 +56,18 @@ func SetSettlementID(ctx context.Context, id uuid.UUID) context.Context {
 	return context.WithValue(ctx, settlementIDKey, id)
 }
 
+func Settlement(ctx context.Context) *settlementdomain.Settlement {
+	if val, ok := ctx.Value(settlementKey).(*settlementdomain.Settlement); ok {
+		return val
+	}
+
+	return nil
+}
+
+func SetSettlement(ctx context.Context, s *settlementdomain.Settlement) context.Context {
+	return context.WithValue(ctx, settlementKey, s)
+}
+
 func SettlementIDFromURL(r *http.Request) (uuid.UUID, error) {
 	rawID := chi.URLParam(r, "id")
 	id, err := uuid.FromString(rawID)
@@ -26,11 +26,26 @@ func Unauthorized(ctx context.Context, rw http.ResponseWriter, err error) {
 	writeError(ctx, rw, http.StatusUnauthorized)
 }
 
+func Forbidden(ctx context.Context, rw http.ResponseWriter, err error) {
+	slog.Error("forbidden", slog.Any("error", err))
+	writeError(ctx, rw, http.StatusForbidden)
+}
+
 func NotFound(ctx context.Context, rw http.ResponseWriter, err error) {
 	slog.Error("not found", slog.Any("error", err))
 	writeError(ctx, rw, http.StatusNotFound)
 }
 
+func Conflict(ctx context.Context, rw http.ResponseWriter, err error) {
+	slog.Error("conflict", slog.Any("error", err))
+	writeError(ctx, rw, http.StatusConflict)
+}
+
+func UnsupportedMediaType(ctx context.Context, rw http.ResponseWriter, err error) {
+	slog.Error("unsupported media type", slog.Any("error", err))
+	writeError(ctx, rw, http.StatusUnsupportedMediaType)
+}
+
 func NoContent(rw http.ResponseWriter) {
 	rw.WriteHeader(http.StatusNoContent)
 }
@@ -2,9 +2,11 @@ package settlement
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/failuretoload/datamonster/glossary"
 	"github.com/failuretoload/datamonster/request"
 	"github.com/failuretoload/datamonster/response"
 	"github.com/failuretoload/datamonster/settlement/domain"
@@ -17,27 +19,49 @@ type (
 		All(ctx context.Context, userID string) ([]domain.Settlement, error)
 		Insert(ctx context.Context, s domain.Settlement) (uuid.UUID, error)
 		Get(ctx context.Context, userID string, settlementID uuid.UUID) (*domain.Settlement, error)
+		Stats(ctx context.Context, userID string, settlementID uuid.UUID) (*domain.Stats, error)
+		FiredMilestones(ctx context.Context, settlementID uuid.UUID) ([]string, error)
+		RecordMilestone(ctx context.Context, userID string, settlementID uuid.UUID, milestone string) error
+		AddInnovation(ctx context.Context, userID string, settlementID, innovationID uuid.UUID) (*domain.Settlement, error)
+		Found(ctx context.Context, s domain.Settlement, f domain.Founding) (uuid.UUID, error)
+		Timeline(ctx context.Context, userID string, settlementID uuid.UUID) ([]domain.TimelineEntry, error)
+	}
+	Glossary interface {
+		Campaign(id string) (glossary.Campaign, bool)
+		Innovation(id string) (glossary.Innovation, bool)
 	}
 	Controller struct {
-		records Repo
+		records  Repo
+		glossary Glossary
 	}
 	CreateSettlementRequest struct {
-		Name string `json:"name"`
+		Name     string `json:"name"`
+		Campaign string `json:"campaign,omitempty"`
+	}
+	AddInnovationRequest struct {
+		InnovationID uuid.UUID `json:"innovationId"`
 	}
 )
 
-func NewController(r Repo) (*Controller, error) {
+func NewController(r Repo, g Glossary) (*Controller, error) {
 	if r == nil {
 		return nil, fmt.Errorf("repo cannot be nil")
 	}
+	if g == nil {
+		return nil, fmt.Errorf("glossary cannot be nil")
+	}
 
-	return &Controller{records: r}, nil
+	return &Controller{records: r, glossary: g}, nil
 }
 
 func (c Controller) RegisterRoutes(r chi.Router) {
 	r.Get("/settlements", c.getSettlements)
 	r.Post("/settlements", c.createSettlement)
 	r.Get("/settlements/{id}", c.getSettlement)
+	r.Get("/settlements/{id}/stats", c.getStats)
+	r.Get("/settlements/{id}/timeline", c.getTimeline)
+	r.Post("/settlements/{id}/milestones/{milestone}", c.recordMilestone)
+	r.Post("/settlements/{id}/innovations", c.addInnovation)
 }
 
 func (c Controller) getSettlements(w http.ResponseWriter, r *http.Request) {
@@ -78,7 +102,25 @@ func (c Controller) createSettlement(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	settlementID, err := c.records.Insert(ctx, domain.Settlement{Name: body.Name, Owner: userID})
+	settlement := domain.Settlement{Name: body.Name, Owner: userID}
+	var settlementID uuid.UUID
+	if body.Campaign == "" {
+		settlementID, err = c.records.Insert(ctx, settlement)
+	} else {
+		campaign, ok := c.glossary.Campaign(body.Campaign)
+		if !ok {
+			response.BadRequest(ctx, w, fmt.Errorf("unknown campaign: %s", body.Campaign))
+			return
+		}
+
+		var founding domain.Founding
+		settlement, founding, err = fromCampaign(settlement, campaign)
+		if err != nil {
+			response.InternalServerError(ctx, w, fmt.Errorf("invalid campaign template: %w", err))
+			return
+		}
+		settlementID, err = c.records.Found(ctx, settlement, founding)
+	}
 	if err != nil {
 		response.InternalServerError(ctx, w, fmt.Errorf("unable to persist settlement: %w", err))
 	} else {
@@ -112,3 +154,186 @@ func (c Controller) getSettlement(w http.ResponseWriter, r *http.Request) {
 
 	response.OK(ctx, w, settlement)
 }
+
+func (c Controller) getStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := request.UserID(ctx)
+	if userID == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("userID is required"))
+		return
+	}
+
+	settlementID, err := request.SettlementIDFromURL(r)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	stats, repoErr := c.records.Stats(ctx, userID, settlementID)
+	if repoErr != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("unable to compute settlement stats: %w", repoErr))
+		return
+	}
+	if stats == nil {
+		response.NotFound(ctx, w, fmt.Errorf("settlement not found"))
+		return
+	}
+
+	fired, repoErr := c.records.FiredMilestones(ctx, settlementID)
+	if repoErr != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("unable to retrieve milestones: %w", repoErr))
+		return
+	}
+
+	stats.Milestones = domain.PendingMilestones(*stats, fired)
+	response.OK(ctx, w, stats)
+}
+
+func (c Controller) getTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := request.UserID(ctx)
+	if userID == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("userID is required"))
+		return
+	}
+
+	settlementID, err := request.SettlementIDFromURL(r)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	settlement, repoErr := c.records.Get(ctx, userID, settlementID)
+	if repoErr != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("unable to retrieve settlement: %w", repoErr))
+		return
+	}
+	if settlement == nil {
+		response.NotFound(ctx, w, fmt.Errorf("settlement not found"))
+		return
+	}
+
+	timeline, repoErr := c.records.Timeline(ctx, userID, settlementID)
+	if repoErr != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("unable to retrieve timeline: %w", repoErr))
+		return
+	}
+
+	response.OK(ctx, w, timeline)
+}
+
+func (c Controller) recordMilestone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := request.UserID(ctx)
+	if userID == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("userID is required"))
+		return
+	}
+
+	settlementID, err := request.SettlementIDFromURL(r)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	milestone := chi.URLParam(r, "milestone")
+	if !domain.ValidMilestone(milestone) {
+		response.BadRequest(ctx, w, fmt.Errorf("unknown milestone: %s", milestone))
+		return
+	}
+
+	settlement, repoErr := c.records.Get(ctx, userID, settlementID)
+	if repoErr != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("unable to retrieve settlement: %w", repoErr))
+		return
+	}
+	if settlement == nil {
+		response.NotFound(ctx, w, fmt.Errorf("settlement not found"))
+		return
+	}
+
+	if err := c.records.RecordMilestone(ctx, userID, settlementID, milestone); err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("unable to record milestone: %w", err))
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (c Controller) addInnovation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := request.UserID(ctx)
+	if userID == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("userID is required"))
+		return
+	}
+
+	settlementID, err := request.SettlementIDFromURL(r)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	var body AddInnovationRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if body.InnovationID == uuid.Nil {
+		response.BadRequest(ctx, w, fmt.Errorf("innovationId is required"))
+		return
+	}
+	if _, ok := c.glossary.Innovation(body.InnovationID.String()); !ok {
+		response.BadRequest(ctx, w, fmt.Errorf("unknown innovation: %s", body.InnovationID))
+		return
+	}
+
+	settlement, repoErr := c.records.AddInnovation(ctx, userID, settlementID, body.InnovationID)
+	if errors.Is(repoErr, domain.ErrPrincipleChosen) {
+		response.Conflict(ctx, w, repoErr)
+		return
+	}
+	if repoErr != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("unable to add innovation: %w", repoErr))
+		return
+	}
+	if settlement == nil {
+		response.NotFound(ctx, w, fmt.Errorf("settlement not found"))
+		return
+	}
+
+	response.OK(ctx, w, settlement)
+}
+
+func fromCampaign(s domain.Settlement, c glossary.Campaign) (domain.Settlement, domain.Founding, error) {
+	s.SurvivalLimit = c.SurvivalLimit
+	s.DepartingSurvival = c.DepartingSurvival
+	s.Innovations = make([]uuid.UUID, 0, len(c.Innovations))
+	for _, raw := range c.Innovations {
+		id, err := uuid.FromString(raw)
+		if err != nil {
+			return domain.Settlement{}, domain.Founding{}, fmt.Errorf("campaign %s has invalid innovation %s", c.ID, raw)
+		}
+		s.Innovations = append(s.Innovations, id)
+	}
+
+	var f domain.Founding
+	for _, entry := range c.Timeline {
+		f.Timeline = append(f.Timeline, domain.TimelineEntry{Year: entry.Year, Event: entry.Event})
+	}
+	for _, sv := range c.Survivors {
+		f.Survivors = append(f.Survivors, domain.StartingSurvivor{
+			Name:     sv.Name,
+			Gender:   sv.Gender,
+			Survival: sv.Survival,
+			Movement: sv.Movement,
+			Accuracy: sv.Accuracy,
+			Strength: sv.Strength,
+			Evasion:  sv.Evasion,
+			Luck:     sv.Luck,
+			Speed:    sv.Speed,
+		})
+	}
+
+	return s, f, nil
+}
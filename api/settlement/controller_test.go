@@ -3,15 +3,20 @@ package settlement_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"testing"
 
+	"github.com/failuretoload/datamonster/glossary"
 	"github.com/failuretoload/datamonster/server"
 	"github.com/failuretoload/datamonster/settlement"
 	"github.com/failuretoload/datamonster/settlement/domain"
 	"github.com/failuretoload/datamonster/settlement/repo"
+	"github.com/failuretoload/datamonster/survivor"
+	survivorDomain "github.com/failuretoload/datamonster/survivor/domain"
+	survivorRepo "github.com/failuretoload/datamonster/survivor/repo"
 	"github.com/failuretoload/datamonster/testenv"
 	"github.com/gofrs/uuid/v5"
 	"github.com/stretchr/testify/assert"
@@ -23,6 +28,12 @@ var (
 	requester   *testenv.Requester
 )
 
+const (
+	lanternCampaignID   = "019412a0-0009-7000-8000-000000000009"
+	lanternInnovationID = "019412a0-0005-7000-8000-000000000005"
+	brokenCampaignID    = "019412a0-00ff-7000-8000-0000000000ff"
+)
+
 func TestMain(m *testing.M) {
 	var err error
 	dbContainer, err = testenv.NewDBContainer(context.Background())
@@ -36,12 +47,59 @@ func TestMain(m *testing.M) {
 		log.Fatal(err)
 	}
 
-	controller, err := settlement.NewController(repo)
+	glossaryStub := testenv.NewGlossaryStub(fmt.Sprintf(`{"innovations":[
+		{"id":"%s","name":"Language","source":"core","keywords":"language"},
+		{"id":"%s","name":"Survival of the Fittest","source":"core","keywords":"principle"},
+		{"id":"%s","name":"Protect the Young","source":"core","keywords":"principle"}
+	],"campaigns":[{
+		"id":"%s",
+		"name":"People of the Lantern",
+		"source":"core",
+		"survivalLimit":1,
+		"departingSurvival":2,
+		"innovations":["%s"],
+		"timeline":[{"year":1,"event":"Returning Survivors"},{"year":2,"event":"Endless Screams"}],
+		"survivors":[
+			{"name":"Allister","gender":"M","survival":1,"movement":5},
+			{"name":"Erza","gender":"F","survival":1,"movement":5},
+			{"name":"Zachary","gender":"M","survival":1,"movement":5},
+			{"name":"Lucy","gender":"F","survival":1,"movement":5}
+		]
+	},{
+		"id":"%s",
+		"name":"Broken",
+		"source":"core",
+		"survivalLimit":1,
+		"innovations":[],
+		"timeline":[{"year":1,"event":"Returning Survivors"}],
+		"survivors":[
+			{"name":"Twin","gender":"M","survival":1,"movement":5},
+			{"name":"Twin","gender":"F","survival":1,"movement":5}
+		]
+	}]}`, lanternInnovationID, domain.PrincipleSurvivalOfTheFittest, domain.PrincipleProtectTheYoung,
+		lanternCampaignID, lanternInnovationID, brokenCampaignID))
+	defer glossaryStub.Close()
+
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	controller, err := settlement.NewController(repo, glossaryController)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	requester, err = testenv.NewRequester([]server.Controller{controller})
+	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	survivorController, err := survivor.NewController(survivorRepo, repo, survivorDomain.DefaultMilestoneRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{controller, survivorController})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -185,3 +243,263 @@ func TestGetSettlement_IsolatesUserData(t *testing.T) {
 	_, status := requester.GetSettlement(user1, user2SettlementID)
 	assert.Equal(t, http.StatusNotFound, status)
 }
+
+func TestGetStats_EmptySettlement(t *testing.T) {
+	userID := "stats-empty-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	body, status := requester.GetSettlementStats(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+
+	var stats domain.Stats
+	require.NoError(t, json.NewDecoder(body).Decode(&stats))
+	assert.Equal(t, 0, stats.Population)
+	assert.Equal(t, 0, stats.DeathCount)
+	assert.Equal(t, 0, stats.Total)
+	assert.Empty(t, stats.Milestones)
+}
+
+func TestGetStats_CountsSurvivors(t *testing.T) {
+	userID := "stats-count-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	statuses := []string{"Alive", "Cannot depart", "Dead", "Retired"}
+	for i, s := range statuses {
+		rawSurvivor, status := requester.CreateSurvivor(userID, settlementID, s+" Survivor")
+		require.Equal(t, http.StatusOK, status)
+
+		var created survivorDomain.Survivor
+		require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&created))
+
+		body := fmt.Sprintf(`{"strength":%d,"status":"%s"}`, i*2, s)
+		_, status = requester.UpdateSurvivor(userID, settlementID, created.ID.String(), body)
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	body, status := requester.GetSettlementStats(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+
+	var stats domain.Stats
+	require.NoError(t, json.NewDecoder(body).Decode(&stats))
+	assert.Equal(t, 2, stats.Population)
+	assert.Equal(t, 1, stats.DeathCount)
+	assert.Equal(t, 1, stats.Retired)
+	assert.Equal(t, 4, stats.Total)
+	assert.InDelta(t, 1.0, stats.Averages.Strength, 0.001)
+	assert.InDelta(t, 5.0, stats.Averages.Movement, 0.001)
+	require.Len(t, stats.Milestones, 1)
+	assert.Equal(t, "first-death", stats.Milestones[0].Key)
+}
+
+func TestRecordMilestone_ClearsPending(t *testing.T) {
+	userID := "stats-milestone-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	rawSurvivor, status := requester.CreateSurvivor(userID, settlementID, "Doomed")
+	require.Equal(t, http.StatusOK, status)
+
+	var created survivorDomain.Survivor
+	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&created))
+
+	_, status = requester.UpdateSurvivor(userID, settlementID, created.ID.String(), `{"status":"Dead"}`)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.RecordMilestone(userID, settlementID, "first-death")
+	require.Equal(t, http.StatusNoContent, status)
+
+	body, status := requester.GetSettlementStats(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+
+	var stats domain.Stats
+	require.NoError(t, json.NewDecoder(body).Decode(&stats))
+	require.Len(t, stats.Milestones, 1)
+	assert.Equal(t, "population-0", stats.Milestones[0].Key)
+}
+
+func TestRecordMilestone_Unknown(t *testing.T) {
+	userID := "stats-unknown-milestone-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.RecordMilestone(userID, settlementID, "not-a-milestone")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestRecordMilestone_OtherUsersSettlement(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("milestone-owner-user")
+	require.NoError(t, err)
+
+	_, status := requester.RecordMilestone("milestone-other-user", settlementID, "first-death")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestGetStats_NotFound(t *testing.T) {
+	_, status := requester.GetSettlementStats("stats-notfound-user", testenv.UUIDString())
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestGetStats_IsolatesUserData(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("stats-owner-user")
+	require.NoError(t, err)
+
+	_, status := requester.GetSettlementStats("stats-other-user", settlementID)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestAddInnovation_Success(t *testing.T) {
+	userID := "add-innovation-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	innovationID := uuid.FromStringOrNil(lanternInnovationID)
+	for range 2 {
+		body, status := requester.AddInnovation(userID, settlementID, innovationID.String())
+		require.Equal(t, http.StatusOK, status)
+
+		var s domain.Settlement
+		require.NoError(t, json.NewDecoder(body).Decode(&s))
+		assert.Equal(t, []uuid.UUID{innovationID}, s.Innovations)
+	}
+}
+
+func TestAddInnovation_NotFound(t *testing.T) {
+	_, status := requester.AddInnovation("add-innovation-notfound-user", testenv.UUIDString(), lanternInnovationID)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestAddInnovation_UnknownInnovation(t *testing.T) {
+	userID := "add-innovation-unknown-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.AddInnovation(userID, settlementID, testenv.UUIDString())
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAddInnovation_PrincipleAlreadyChosen(t *testing.T) {
+	userID := "add-innovation-principle-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	_, status := requester.AddInnovation(userID, settlementID, domain.PrincipleProtectTheYoung)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.AddInnovation(userID, settlementID, domain.PrincipleSurvivalOfTheFittest)
+	assert.Equal(t, http.StatusConflict, status)
+
+	_, status = requester.AddInnovation(userID, settlementID, domain.PrincipleProtectTheYoung)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestGetStats_FirstBirthMilestone(t *testing.T) {
+	userID := "stats-first-birth-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	var parents []string
+	for _, name := range []string{"Parent One", "Parent Two"} {
+		raw, status := requester.CreateSurvivor(userID, settlementID, name)
+		require.Equal(t, http.StatusOK, status)
+
+		var parent survivorDomain.Survivor
+		require.NoError(t, json.NewDecoder(raw).Decode(&parent))
+		parents = append(parents, parent.ID.String())
+	}
+
+	body := fmt.Sprintf(`{"parents":["%s","%s"],"name":"Child","gender":"F"}`, parents[0], parents[1])
+	_, status := requester.BirthSurvivor(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	raw, status := requester.GetSettlementStats(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+
+	var stats domain.Stats
+	require.NoError(t, json.NewDecoder(raw).Decode(&stats))
+	assert.Equal(t, 3, stats.Population)
+	assert.Equal(t, 1, stats.Births)
+	require.Len(t, stats.Milestones, 1)
+	assert.Equal(t, "first-birth", stats.Milestones[0].Key)
+}
+
+func TestCreateSettlement_FromCampaign(t *testing.T) {
+	userID := "create-campaign-user"
+
+	respBody, status := requester.CreateSettlementWithBody(userID, fmt.Sprintf(`{"name":"Lantern Hoard","campaign":"%s"}`, lanternCampaignID))
+	require.Equal(t, http.StatusOK, status)
+
+	var settlementID uuid.UUID
+	require.NoError(t, json.NewDecoder(respBody).Decode(&settlementID))
+
+	body, status := requester.GetSettlement(userID, settlementID.String())
+	require.Equal(t, http.StatusOK, status)
+
+	var s domain.Settlement
+	require.NoError(t, json.NewDecoder(body).Decode(&s))
+	assert.Equal(t, 1, s.SurvivalLimit)
+	assert.Equal(t, 2, s.DepartingSurvival)
+	assert.Equal(t, []uuid.UUID{uuid.FromStringOrNil(lanternInnovationID)}, s.Innovations)
+
+	body, status = requester.GetTimeline(userID, settlementID.String())
+	require.Equal(t, http.StatusOK, status)
+
+	var timeline []domain.TimelineEntry
+	require.NoError(t, json.NewDecoder(body).Decode(&timeline))
+	require.Len(t, timeline, 2)
+	assert.Equal(t, 1, timeline[0].Year)
+	assert.Equal(t, "Returning Survivors", timeline[0].Event)
+	assert.False(t, timeline[0].Completed)
+
+	body, status = requester.GetSurvivors(userID, settlementID.String())
+	require.Equal(t, http.StatusOK, status)
+
+	var page survivorDomain.SurvivorPage
+	require.NoError(t, json.NewDecoder(body).Decode(&page))
+	require.Len(t, page.Survivors, 4)
+	for _, sv := range page.Survivors {
+		assert.Equal(t, 1, sv.Survival)
+		assert.Equal(t, 5, sv.Movement)
+	}
+}
+
+func TestCreateSettlement_UnknownCampaign(t *testing.T) {
+	_, status := requester.CreateSettlementWithBody("unknown-campaign-user", `{"name":"Nowhere","campaign":"not-a-campaign"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestCreateSettlement_CampaignSeedIsAtomic(t *testing.T) {
+	userID := "broken-campaign-user"
+
+	_, status := requester.CreateSettlementWithBody(userID, fmt.Sprintf(`{"name":"Half Built","campaign":"%s"}`, brokenCampaignID))
+	require.Equal(t, http.StatusInternalServerError, status)
+
+	body, status := requester.GetSettlements(userID)
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, "[]", body.String())
+}
+
+func TestGetTimeline_Empty(t *testing.T) {
+	userID := "empty-timeline-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	body, status := requester.GetTimeline(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, "[]", body.String())
+}
+
+func TestGetTimeline_NotFound(t *testing.T) {
+	_, status := requester.GetTimeline("timeline-not-found-user", testenv.UUIDString())
+	assert.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "github.com/gofrs/uuid/v5"
+
+type TimelineEntry struct {
+	ID        uuid.UUID `json:"id"`
+	Year      int       `json:"year"`
+	Event     string    `json:"event"`
+	Completed bool      `json:"completed"`
+}
+
+type StartingSurvivor struct {
+	Name     string
+	Gender   string
+	Survival int
+	Movement int
+	Accuracy int
+	Strength int
+	Evasion  int
+	Luck     int
+	Speed    int
+}
+
+type Founding struct {
+	Timeline  []TimelineEntry
+	Survivors []StartingSurvivor
+}
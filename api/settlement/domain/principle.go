@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var ErrPrincipleChosen = errors.New("the settlement has already chosen this principle")
+
+const (
+	PrincipleSurvivalOfTheFittest = "019412a0-0029-7000-8000-000000000029"
+	PrincipleProtectTheYoung      = "019412a0-002a-7000-8000-00000000002a"
+)
+
+var Principles = [][]uuid.UUID{
+	{uuid.Must(uuid.FromString(PrincipleSurvivalOfTheFittest)), uuid.Must(uuid.FromString(PrincipleProtectTheYoung))},
+}
+
+func Alternatives(innovationID uuid.UUID) []uuid.UUID {
+	for _, choices := range Principles {
+		if !slices.Contains(choices, innovationID) {
+			continue
+		}
+
+		var others []uuid.UUID
+		for _, id := range choices {
+			if id != innovationID {
+				others = append(others, id)
+			}
+		}
+		return others
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+type Stats struct {
+	Population       int          `json:"population"`
+	DeathCount       int          `json:"deathCount"`
+	Retired          int          `json:"retired"`
+	CeasedToExist    int          `json:"ceasedToExist"`
+	Total            int          `json:"total"`
+	Births           int          `json:"births"`
+	Innovations      int          `json:"innovations"`
+	QuarryVictories  int          `json:"quarryVictories"`
+	NemesisVictories int          `json:"nemesisVictories"`
+	Averages         StatAverages `json:"averages"`
+	Milestones       []Milestone  `json:"milestones"`
+}
+
+type StatAverages struct {
+	HuntXP        float64 `json:"huntxp"`
+	Survival      float64 `json:"survival"`
+	Movement      float64 `json:"movement"`
+	Accuracy      float64 `json:"accuracy"`
+	Strength      float64 `json:"strength"`
+	Evasion       float64 `json:"evasion"`
+	Luck          float64 `json:"luck"`
+	Speed         float64 `json:"speed"`
+	Insanity      float64 `json:"insanity"`
+	Courage       float64 `json:"courage"`
+	Understanding float64 `json:"understanding"`
+}
+
+type Milestone struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Event string `json:"event"`
+}
+
+type milestoneRule struct {
+	Milestone
+	reached func(Stats) bool
+}
+
+var milestoneRules = []milestoneRule{
+	{
+		Milestone: Milestone{Key: "first-birth", Name: "First child is born", Event: "Principle: New Life"},
+		reached:   func(s Stats) bool { return s.Births >= 1 },
+	},
+	{
+		Milestone: Milestone{Key: "first-death", Name: "First time death count is updated", Event: "Principle: Death"},
+		reached:   func(s Stats) bool { return s.DeathCount >= 1 },
+	},
+	{
+		Milestone: Milestone{Key: "population-15", Name: "Population reaches 15", Event: "Principle: Society"},
+		reached:   func(s Stats) bool { return s.Population >= 15 },
+	},
+	{
+		Milestone: Milestone{Key: "innovations-5", Name: "Settlement has 5 innovations", Event: "Hooded Knight"},
+		reached:   func(s Stats) bool { return s.Innovations >= 5 },
+	},
+	{
+		Milestone: Milestone{Key: "first-nemesis", Name: "First nemesis is defeated", Event: "Principle: Conviction"},
+		reached:   func(s Stats) bool { return s.NemesisVictories >= 1 },
+	},
+	{
+		Milestone: Milestone{Key: "population-0", Name: "Population reaches 0", Event: "Game Over"},
+		reached:   func(s Stats) bool { return s.Total > 0 && s.Population == 0 },
+	},
+}
+
+func ValidMilestone(key string) bool {
+	for _, rule := range milestoneRules {
+		if rule.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func PendingMilestones(s Stats, fired []string) []Milestone {
+	done := make(map[string]bool, len(fired))
+	for _, key := range fired {
+		done[key] = true
+	}
+
+	pending := []Milestone{}
+	for _, rule := range milestoneRules {
+		if !done[rule.Key] && rule.reached(s) {
+			pending = append(pending, rule.Milestone)
+		}
+	}
+
+	return pending
+}
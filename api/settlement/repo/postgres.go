@@ -12,14 +12,15 @@ import (
 )
 
 type settlement struct {
-	ID                  int       `db:"id"`
-	ExternalID          uuid.UUID `db:"external_id"`
-	Owner               string    `db:"owner"`
-	Name                string    `db:"name"`
-	SurvivalLimit       int       `db:"survival_limit"`
-	DepartingSurvival   int       `db:"departing_survival"`
-	CollectiveCognition int       `db:"collective_cognition"`
-	CurrentYear         int       `db:"year"`
+	ID                  int         `db:"id"`
+	ExternalID          uuid.UUID   `db:"external_id"`
+	Owner               string      `db:"owner"`
+	Name                string      `db:"name"`
+	SurvivalLimit       int         `db:"survival_limit"`
+	DepartingSurvival   int         `db:"departing_survival"`
+	CollectiveCognition int         `db:"collective_cognition"`
+	CurrentYear         int         `db:"year"`
+	Innovations         []uuid.UUID `db:"innovations"`
 }
 
 const (
@@ -31,6 +32,61 @@ const (
 	departingSurvival   = "departing_survival"
 	collectiveCognition = "collective_cognition"
 	year                = "year"
+	innovations         = "innovations"
+)
+
+const (
+	getStats = `SELECT
+	COUNT(sv.id) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')) AS population,
+	COUNT(sv.id) FILTER (WHERE sv.status = 'Dead') AS death_count,
+	COUNT(sv.id) FILTER (WHERE sv.status = 'Retired') AS retired,
+	COUNT(sv.id) FILTER (WHERE sv.status = 'Ceased to exist') AS ceased_to_exist,
+	COUNT(sv.id) AS total,
+	COUNT(sv.id) FILTER (WHERE EXISTS (SELECT 1 FROM survivor_parent p WHERE p.child_id = sv.external_id)) AS births,
+	CARDINALITY(s.innovations) AS innovations,
+	(SELECT COUNT(*) FROM settlement_monster_defeat d WHERE d.settlement_id = s.external_id AND d.monster_type = 'quarry') AS quarry_victories,
+	(SELECT COUNT(*) FROM settlement_monster_defeat d WHERE d.settlement_id = s.external_id AND d.monster_type = 'nemesis') AS nemesis_victories,
+	COALESCE(AVG(sv.hunt_xp) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS hunt_xp,
+	COALESCE(AVG(sv.survival) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS survival,
+	COALESCE(AVG(sv.movement) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS movement,
+	COALESCE(AVG(sv.accuracy) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS accuracy,
+	COALESCE(AVG(sv.strength) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS strength,
+	COALESCE(AVG(sv.evasion) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS evasion,
+	COALESCE(AVG(sv.luck) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS luck,
+	COALESCE(AVG(sv.speed) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS speed,
+	COALESCE(AVG(sv.insanity) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS insanity,
+	COALESCE(AVG(sv.courage) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS courage,
+	COALESCE(AVG(sv.understanding) FILTER (WHERE sv.status IN ('Alive', 'Cannot depart')), 0)::float8 AS understanding
+FROM settlement s
+LEFT JOIN survivor sv ON sv.settlement_id = s.external_id
+WHERE s.owner = $1 AND s.external_id = $2
+GROUP BY s.id`
+	getFiredMilestones = `SELECT milestone FROM settlement_milestone WHERE settlement_id = $1`
+	recordMilestone    = `INSERT INTO settlement_milestone (settlement_id, milestone, year)
+SELECT external_id, $3, year FROM settlement WHERE owner = $1 AND external_id = $2
+ON CONFLICT (settlement_id, milestone) DO NOTHING`
+)
+
+type timelineEntry struct {
+	ID           int       `db:"id"`
+	ExternalID   uuid.UUID `db:"external_id"`
+	SettlementID uuid.UUID `db:"settlement_id"`
+	Year         int       `db:"year"`
+	Event        string    `db:"event"`
+	Completed    bool      `db:"completed"`
+}
+
+const (
+	insertTimelineEntry    = `INSERT INTO settlement_timeline (settlement_id, year, event) VALUES ($1, $2, $3)`
+	insertStartingSurvivor = `INSERT INTO survivor (
+	settlement_id, name, birth, gender, hunt_xp, survival, movement, accuracy, strength, evasion, luck, speed,
+	insanity, systemic_pressure, torment, lumi, courage, understanding
+)
+VALUES ($1, $2, 0, $3, 0, $4, $5, $6, $7, $8, $9, $10, 0, 0, 0, 0, 0, 0)`
+	getTimeline = `SELECT t.* FROM settlement_timeline t
+JOIN settlement s ON s.external_id = t.settlement_id
+WHERE s.owner = $1 AND s.external_id = $2
+ORDER BY t.year, t.id`
 )
 
 type Postgres struct {
@@ -66,19 +122,91 @@ func (r Postgres) All(ctx context.Context, userID string) ([]domain.Settlement,
 }
 
 func (r Postgres) Insert(ctx context.Context, s domain.Settlement) (uuid.UUID, error) {
+	return insertSettlement(ctx, r.db, s)
+}
+
+func (r Postgres) Found(ctx context.Context, s domain.Settlement, f domain.Founding) (uuid.UUID, error) {
+	var settlementID uuid.UUID
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		settlementID, err = insertSettlement(ctx, tx, s)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range f.Timeline {
+			if _, err := tx.Exec(ctx, insertTimelineEntry, settlementID, entry.Year, entry.Event); err != nil {
+				return fmt.Errorf("unable to seed timeline: %w", err)
+			}
+		}
+
+		for _, sv := range f.Survivors {
+			_, err := tx.Exec(ctx, insertStartingSurvivor,
+				settlementID,
+				sv.Name,
+				sv.Gender,
+				sv.Survival,
+				sv.Movement,
+				sv.Accuracy,
+				sv.Strength,
+				sv.Evasion,
+				sv.Luck,
+				sv.Speed,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to seed survivor %s: %w", sv.Name, err)
+			}
+		}
+
+		return nil
+	})
+
+	return settlementID, err
+}
+
+func (r Postgres) Timeline(ctx context.Context, userID string, settlementID uuid.UUID) ([]domain.TimelineEntry, error) {
+	rows, err := r.db.Query(ctx, getTimeline, userID, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[timelineEntry])
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := make([]domain.TimelineEntry, len(entries))
+	for i, e := range entries {
+		timeline[i] = domain.TimelineEntry{
+			ID:        e.ExternalID,
+			Year:      e.Year,
+			Event:     e.Event,
+			Completed: e.Completed,
+		}
+	}
+
+	return timeline, nil
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertSettlement(ctx context.Context, q rowQuerier, s domain.Settlement) (uuid.UUID, error) {
 	query := fmt.Sprintf(
-		"INSERT INTO %s (%s, %s, %s, %s, %s, %s) VALUES ($1, $2, $3, $4, $5, $6) RETURNING %s",
-		table, owner, name, survivalLimit, departingSurvival, collectiveCognition, year, externalID,
+		"INSERT INTO %s (%s, %s, %s, %s, %s, %s, %s) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING %s",
+		table, owner, name, survivalLimit, departingSurvival, collectiveCognition, year, innovations, externalID,
 	)
 
 	var externalID uuid.UUID
-	err := r.db.QueryRow(ctx, query,
+	err := q.QueryRow(ctx, query,
 		s.Owner,
 		s.Name,
 		s.SurvivalLimit,
 		s.DepartingSurvival,
 		s.CollectiveCognition,
 		s.CurrentYear,
+		innovationsOrEmpty(s.Innovations),
 	).Scan(&externalID)
 
 	return externalID, err
@@ -105,6 +233,95 @@ func (r Postgres) Get(ctx context.Context, userID string, settlementID uuid.UUID
 	return &result, nil
 }
 
+func (r Postgres) Stats(ctx context.Context, userID string, settlementID uuid.UUID) (*domain.Stats, error) {
+	var stats domain.Stats
+	avg := &stats.Averages
+	err := r.db.QueryRow(ctx, getStats, userID, settlementID).Scan(
+		&stats.Population,
+		&stats.DeathCount,
+		&stats.Retired,
+		&stats.CeasedToExist,
+		&stats.Total,
+		&stats.Births,
+		&stats.Innovations,
+		&stats.QuarryVictories,
+		&stats.NemesisVictories,
+		&avg.HuntXP,
+		&avg.Survival,
+		&avg.Movement,
+		&avg.Accuracy,
+		&avg.Strength,
+		&avg.Evasion,
+		&avg.Luck,
+		&avg.Speed,
+		&avg.Insanity,
+		&avg.Courage,
+		&avg.Understanding,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func (r Postgres) FiredMilestones(ctx context.Context, settlementID uuid.UUID) ([]string, error) {
+	rows, err := r.db.Query(ctx, getFiredMilestones, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (r Postgres) RecordMilestone(ctx context.Context, userID string, settlementID uuid.UUID, milestone string) error {
+	_, err := r.db.Exec(ctx, recordMilestone, userID, settlementID, milestone)
+	return err
+}
+
+func (r Postgres) AddInnovation(ctx context.Context, userID string, settlementID, innovationID uuid.UUID) (*domain.Settlement, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s = CASE WHEN $3 = ANY(%s) THEN %s ELSE array_append(%s, $3) END WHERE %s = $1 AND %s = $2 AND NOT (%s && $4) RETURNING *",
+		table, innovations, innovations, innovations, innovations, owner, externalID, innovations,
+	)
+
+	excluded := domain.Alternatives(innovationID)
+	if excluded == nil {
+		excluded = []uuid.UUID{}
+	}
+
+	row, err := r.db.Query(ctx, query, userID, settlementID, innovationID, excluded)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+
+	s, err := pgx.CollectExactlyOneRow(row, pgx.RowToStructByName[settlement])
+	if errors.Is(err, pgx.ErrNoRows) {
+		existing, err := r.Get(ctx, userID, settlementID)
+		if err != nil || existing == nil {
+			return nil, err
+		}
+		return nil, domain.ErrPrincipleChosen
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := toDTO(s)
+	return &result, nil
+}
+
+func innovationsOrEmpty(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return []uuid.UUID{}
+	}
+	return ids
+}
+
 func toDTOList(settlements []settlement) []domain.Settlement {
 	var settlementDTOs []domain.Settlement
 	for _, s := range settlements {
@@ -122,5 +339,6 @@ func toDTO(s settlement) domain.Settlement {
 		DepartingSurvival:   s.DepartingSurvival,
 		CollectiveCognition: s.CollectiveCognition,
 		CurrentYear:         s.CurrentYear,
+		Innovations:         s.Innovations,
 	}
 }
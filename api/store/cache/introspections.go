@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+type IntrospectionCache struct {
+	client valkey.Client
+	prefix string
+}
+
+func (s *SessionStore) Introspections() *IntrospectionCache {
+	return &IntrospectionCache{
+		client: s.client,
+		prefix: "introspection:",
+	}
+}
+
+func (c *IntrospectionCache) key(key string) string {
+	return c.prefix + key
+}
+
+func (c *IntrospectionCache) Set(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	return c.client.Do(ctx,
+		c.client.B().Set().Key(c.key(key)).Value(string(data)).Px(ttl).Build(),
+	).Error()
+}
+
+func (c *IntrospectionCache) Get(ctx context.Context, key string) ([]byte, error) {
+	result, err := c.client.Do(ctx,
+		c.client.B().Get().Key(c.key(key)).Build(),
+	).AsBytes()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *IntrospectionCache) Delete(ctx context.Context, key string) error {
+	return c.client.Do(ctx,
+		c.client.B().Del().Key(c.key(key)).Build(),
+	).Error()
+}
@@ -9,9 +9,12 @@ import (
 	"github.com/valkey-io/valkey-go"
 )
 
+const indexTTL = 7 * 24 * time.Hour
+
 type SessionStore struct {
-	client valkey.Client
-	prefix string
+	client      valkey.Client
+	prefix      string
+	indexPrefix string
 }
 
 func NewSessionStore(ctx context.Context) (*SessionStore, error) {
@@ -35,8 +38,9 @@ func NewSessionStore(ctx context.Context) (*SessionStore, error) {
 		return nil, fmt.Errorf("failed to ping valkey: %w", err)
 	}
 	return &SessionStore{
-		client: client,
-		prefix: "session:",
+		client:      client,
+		prefix:      "session:",
+		indexPrefix: "sessions:user:",
 	}, nil
 }
 
@@ -79,6 +83,47 @@ func (s *SessionStore) Delete(ctx context.Context, sessionID string) error {
 	).Error()
 }
 
+func (s *SessionStore) indexKey(userID string) string {
+	return s.indexPrefix + userID
+}
+
+func (s *SessionStore) IndexSession(ctx context.Context, userID, sessionID string, device []byte) error {
+	key := s.indexKey(userID)
+	for _, resp := range s.client.DoMulti(ctx,
+		s.client.B().Hset().Key(key).FieldValue().FieldValue(sessionID, string(device)).Build(),
+		s.client.B().Expire().Key(key).Seconds(int64(indexTTL.Seconds())).Build(),
+	) {
+		if err := resp.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SessionStore) IndexedSessions(ctx context.Context, userID string) (map[string][]byte, error) {
+	result, err := s.client.Do(ctx,
+		s.client.B().Hgetall().Key(s.indexKey(userID)).Build(),
+	).AsStrMap()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+
+	indexed := make(map[string][]byte, len(result))
+	for sessionID, device := range result {
+		indexed[sessionID] = []byte(device)
+	}
+	return indexed, nil
+}
+
+func (s *SessionStore) UnindexSession(ctx context.Context, userID, sessionID string) error {
+	return s.client.Do(ctx,
+		s.client.B().Hdel().Key(s.indexKey(userID)).Field(sessionID).Build(),
+	).Error()
+}
+
 func (s *SessionStore) Close() {
 	s.client.Close()
 }
@@ -0,0 +1,36 @@
+package cache_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/failuretoload/datamonster/store/cache"
+	"github.com/failuretoload/datamonster/store/sessiontest"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/require"
+)
+
+var valkeyContainer *testenv.ValkeyContainer
+
+func TestMain(m *testing.M) {
+	var err error
+	valkeyContainer, err = testenv.NewValkeyContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for cache tests: %v", err)
+	}
+
+	exitCode := m.Run()
+	valkeyContainer.Cleanup(context.Background())
+	os.Exit(exitCode)
+}
+
+func TestSessionStoreConformance(t *testing.T) {
+	t.Setenv("VALKEY_ADDR", valkeyContainer.Addr)
+	store, err := cache.NewSessionStore(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(store.Close)
+
+	sessiontest.Run(t, store)
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+type IntrospectionCache struct {
+	store  *SessionStore
+	prefix string
+}
+
+func (s *SessionStore) Introspections() *IntrospectionCache {
+	return &IntrospectionCache{
+		store:  s,
+		prefix: "introspection:",
+	}
+}
+
+func (c *IntrospectionCache) key(key string) string {
+	return c.prefix + key
+}
+
+func (c *IntrospectionCache) Set(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	return c.store.Set(ctx, c.key(key), data, ttl)
+}
+
+func (c *IntrospectionCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.store.Get(ctx, c.key(key))
+}
+
+func (c *IntrospectionCache) Delete(ctx context.Context, key string) error {
+	return c.store.Delete(ctx, c.key(key))
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const DefaultSweepInterval = time.Minute
+
+type entry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]entry
+	index    map[string]map[string][]byte
+	now      func() time.Time
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewSessionStore(interval time.Duration) *SessionStore {
+	s := &SessionStore{
+		sessions: map[string]entry{},
+		index:    map[string]map[string][]byte{},
+		now:      time.Now,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go s.run(interval)
+	return s
+}
+
+func (s *SessionStore) run(interval time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Sweep()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *SessionStore) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for sessionID, e := range s.sessions {
+		if !now.Before(e.expiresAt) {
+			delete(s.sessions, sessionID)
+		}
+	}
+
+	for userID, devices := range s.index {
+		for sessionID := range devices {
+			if _, ok := s.sessions[sessionID]; !ok {
+				delete(devices, sessionID)
+			}
+		}
+		if len(devices) == 0 {
+			delete(s.index, userID)
+		}
+	}
+}
+
+func (s *SessionStore) live(sessionID string) (entry, bool) {
+	e, ok := s.sessions[sessionID]
+	if !ok || !s.now().Before(e.expiresAt) {
+		return entry{}, false
+	}
+	return e, true
+}
+
+func (s *SessionStore) Set(_ context.Context, sessionID string, data []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[sessionID] = entry{
+		data:      append([]byte(nil), data...),
+		expiresAt: s.now().Add(ttl),
+	}
+	return nil
+}
+
+func (s *SessionStore) Exists(_ context.Context, sessionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.live(sessionID)
+	return ok, nil
+}
+
+func (s *SessionStore) Get(_ context.Context, sessionID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.live(sessionID)
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte(nil), e.data...), nil
+}
+
+func (s *SessionStore) Delete(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+func (s *SessionStore) IndexSession(_ context.Context, userID, sessionID string, device []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index[userID] == nil {
+		s.index[userID] = map[string][]byte{}
+	}
+	s.index[userID][sessionID] = append([]byte(nil), device...)
+	return nil
+}
+
+func (s *SessionStore) IndexedSessions(_ context.Context, userID string) (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indexed := make(map[string][]byte, len(s.index[userID]))
+	for sessionID, device := range s.index[userID] {
+		indexed[sessionID] = append([]byte(nil), device...)
+	}
+	return indexed, nil
+}
+
+func (s *SessionStore) UnindexSession(_ context.Context, userID, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.index[userID], sessionID)
+	return nil
+}
+
+func (s *SessionStore) Close() {
+	close(s.stop)
+	<-s.done
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/failuretoload/datamonster/store/sessiontest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStoreConformance(t *testing.T) {
+	store := NewSessionStore(DefaultSweepInterval)
+	t.Cleanup(store.Close)
+
+	sessiontest.Run(t, store)
+}
+
+func TestSweepEvictsExpiredSessions(t *testing.T) {
+	ctx := context.Background()
+	store := NewSessionStore(time.Hour)
+	t.Cleanup(store.Close)
+
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Set(ctx, "short", []byte("short"), time.Minute))
+	require.NoError(t, store.Set(ctx, "long", []byte("long"), time.Hour))
+	require.NoError(t, store.IndexSession(ctx, "user", "short", []byte("phone")))
+	require.NoError(t, store.IndexSession(ctx, "user", "long", []byte("laptop")))
+	require.NoError(t, store.IndexSession(ctx, "other", "short", []byte("tablet")))
+
+	now = now.Add(2 * time.Minute)
+	store.Sweep()
+
+	assert.NotContains(t, store.sessions, "short")
+	assert.Contains(t, store.sessions, "long")
+	assert.Equal(t, map[string][]byte{"long": []byte("laptop")}, store.index["user"])
+	assert.NotContains(t, store.index, "other", "empty indexes are dropped")
+}
+
+func TestSweeperRunsUntilClosed(t *testing.T) {
+	ctx := context.Background()
+	store := NewSessionStore(10 * time.Millisecond)
+
+	require.NoError(t, store.Set(ctx, "brief", []byte("brief"), time.Millisecond))
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		_, ok := store.sessions["brief"]
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+
+	store.Close()
+}
+
+func TestIntrospectionsAreNamespacedAndSwept(t *testing.T) {
+	ctx := context.Background()
+	store := NewSessionStore(time.Hour)
+	t.Cleanup(store.Close)
+
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	introspections := store.Introspections()
+	require.NoError(t, store.Set(ctx, "token", []byte("session"), time.Hour))
+	require.NoError(t, introspections.Set(ctx, "token", []byte("introspection"), time.Minute))
+
+	data, err := introspections.Get(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("introspection"), data)
+
+	data, err = store.Get(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("session"), data)
+
+	now = now.Add(2 * time.Minute)
+	store.Sweep()
+
+	data, err = introspections.Get(ctx, "token")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, introspections.Set(ctx, "token", []byte("introspection"), time.Minute))
+	require.NoError(t, introspections.Delete(ctx, "token"))
+	data, err = introspections.Get(ctx, "token")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	setIntrospection = `INSERT INTO auth_introspection (key, data, expires_at)
+VALUES ($1, $2, NOW() + $3::interval)
+ON CONFLICT (key) DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at`
+	getIntrospection    = `SELECT data FROM auth_introspection WHERE key = $1 AND expires_at > NOW()`
+	deleteIntrospection = `DELETE FROM auth_introspection WHERE key = $1`
+	sweepIntrospections = `DELETE FROM auth_introspection WHERE expires_at <= NOW()`
+)
+
+type IntrospectionCache struct {
+	db *pgxpool.Pool
+}
+
+func (s *SessionStore) Introspections() *IntrospectionCache {
+	return &IntrospectionCache{db: s.db}
+}
+
+func (c *IntrospectionCache) Set(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	_, err := c.db.Exec(ctx, setIntrospection, key, data, ttl)
+	return err
+}
+
+func (c *IntrospectionCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := c.db.QueryRow(ctx, getIntrospection, key).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (c *IntrospectionCache) Delete(ctx context.Context, key string) error {
+	_, err := c.db.Exec(ctx, deleteIntrospection, key)
+	return err
+}
@@ -85,3 +85,527 @@ func addFightingArtsToSurvivor(ctx context.Context, tx pgx.Tx) error {
 
 	return nil
 }
+
+func createSurvivorKnowledgeTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_survivors_external_id ON survivor(external_id);
+
+		CREATE TABLE IF NOT EXISTS survivor_knowledge (
+			id SERIAL PRIMARY KEY,
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			survivor_id UUID NOT NULL REFERENCES survivor(external_id) ON DELETE CASCADE,
+			knowledge_id UUID NOT NULL,
+			slot INTEGER NOT NULL,
+			observations INTEGER NOT NULL DEFAULT 0,
+			cognition INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (survivor_id, knowledge_id),
+			UNIQUE (survivor_id, slot)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_survivor_knowledge_settlement ON survivor_knowledge(settlement_id);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create survivor knowledge table: %w", err)
+	}
+
+	return nil
+}
+
+func createSettlementMilestoneTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_milestone (
+			id SERIAL PRIMARY KEY,
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			milestone VARCHAR(255) NOT NULL,
+			year INTEGER NOT NULL,
+			fired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (settlement_id, milestone)
+		);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement milestone table: %w", err)
+	}
+
+	return nil
+}
+
+func createSurvivorMilestoneTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS survivor_milestone (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			survivor_id UUID NOT NULL REFERENCES survivor(external_id) ON DELETE CASCADE,
+			stat VARCHAR(50) NOT NULL,
+			threshold INTEGER NOT NULL,
+			event VARCHAR(255) NOT NULL,
+			triggered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			resolved_at TIMESTAMPTZ,
+			UNIQUE (survivor_id, stat, threshold)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_survivor_milestone_pending ON survivor_milestone(survivor_id) WHERE resolved_at IS NULL;
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create survivor milestone table: %w", err)
+	}
+
+	return nil
+}
+
+func addInnovationsAndParentage(ctx context.Context, tx pgx.Tx) error {
+	alter := `
+		ALTER TABLE settlement ADD COLUMN IF NOT EXISTS innovations UUID[] NOT NULL DEFAULT '{}';
+
+		CREATE TABLE IF NOT EXISTS survivor_parent (
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			child_id UUID NOT NULL REFERENCES survivor(external_id) ON DELETE CASCADE,
+			parent_id UUID NOT NULL REFERENCES survivor(external_id) ON DELETE CASCADE,
+			PRIMARY KEY (child_id, parent_id),
+			CHECK (child_id <> parent_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_survivor_parent_parent ON survivor_parent(parent_id);
+		CREATE INDEX IF NOT EXISTS idx_survivor_parent_settlement ON survivor_parent(settlement_id);
+	`
+
+	_, err := tx.Exec(ctx, alter)
+	if err != nil {
+		return fmt.Errorf("failed to add innovations and parentage: %w", err)
+	}
+
+	return nil
+}
+
+func addSurvivorListIndexes(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_status ON survivor(settlement_id, status);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_birth ON survivor(settlement_id, birth, external_id);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to add survivor list indexes: %w", err)
+	}
+
+	return nil
+}
+
+func addSurvivorDeparting(ctx context.Context, tx pgx.Tx) error {
+	alter := `
+		ALTER TABLE survivor ADD COLUMN IF NOT EXISTS departing BOOLEAN NOT NULL DEFAULT false;
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_departing ON survivor(settlement_id) WHERE departing;
+	`
+
+	_, err := tx.Exec(ctx, alter)
+	if err != nil {
+		return fmt.Errorf("failed to add survivor departing flag: %w", err)
+	}
+
+	return nil
+}
+
+func createSettlementTimelineTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_timeline (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			year INTEGER NOT NULL,
+			event TEXT NOT NULL,
+			completed BOOLEAN NOT NULL DEFAULT false
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_settlement_timeline_settlement ON settlement_timeline(settlement_id, year);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement timeline table: %w", err)
+	}
+
+	return nil
+}
+
+func createSettlementEventDeckTables(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_event_deck (
+			settlement_id UUID PRIMARY KEY REFERENCES settlement(external_id),
+			seed BIGINT NOT NULL,
+			shuffles INTEGER NOT NULL DEFAULT 0,
+			draw_pile TEXT[] NOT NULL DEFAULT '{}',
+			discard_pile TEXT[] NOT NULL DEFAULT '{}',
+			current_card TEXT,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS settlement_event_log (
+			id SERIAL PRIMARY KEY,
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			action VARCHAR(32) NOT NULL,
+			card_id TEXT,
+			seed BIGINT NOT NULL,
+			shuffles INTEGER NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_settlement_event_log_settlement ON settlement_event_log(settlement_id, id);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement event deck tables: %w", err)
+	}
+
+	return nil
+}
+
+func createSettlementRollLogTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_roll_log (
+			id SERIAL PRIMARY KEY,
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			roller VARCHAR(255) NOT NULL,
+			year INTEGER NOT NULL,
+			expression VARCHAR(32) NOT NULL,
+			seed BIGINT NOT NULL,
+			dice INTEGER[] NOT NULL,
+			modifier INTEGER NOT NULL DEFAULT 0,
+			total INTEGER NOT NULL,
+			table_id TEXT,
+			result TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_settlement_roll_log_settlement ON settlement_roll_log(settlement_id, id);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement roll log table: %w", err)
+	}
+
+	return nil
+}
+
+func addSurvivorImpairmentsAndRollTargets(ctx context.Context, tx pgx.Tx) error {
+	alter := `
+		ALTER TABLE survivor ADD COLUMN IF NOT EXISTS impairments TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE settlement_roll_log ADD COLUMN IF NOT EXISTS survivor_id UUID REFERENCES survivor(external_id) ON DELETE SET NULL;
+	`
+
+	_, err := tx.Exec(ctx, alter)
+	if err != nil {
+		return fmt.Errorf("failed to add survivor impairments: %w", err)
+	}
+
+	return nil
+}
+
+func createSettlementEndeavorTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_endeavor (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			year INTEGER NOT NULL,
+			kind VARCHAR(16) NOT NULL CHECK (kind IN ('earned', 'spent')),
+			amount INTEGER NOT NULL CHECK (amount > 0),
+			source VARCHAR(255) NOT NULL,
+			created_by VARCHAR(255) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_settlement_endeavor_year ON settlement_endeavor(settlement_id, year, id);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement endeavor table: %w", err)
+	}
+
+	return nil
+}
+
+func createSettlementLocationAndStorageTables(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_location (
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			location_id TEXT NOT NULL,
+			built BOOLEAN NOT NULL DEFAULT false,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (settlement_id, location_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS settlement_storage (
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			kind VARCHAR(16) NOT NULL CHECK (kind IN ('resource', 'gear')),
+			item_id TEXT NOT NULL,
+			quantity INTEGER NOT NULL CHECK (quantity >= 0),
+			PRIMARY KEY (settlement_id, kind, item_id)
+		);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement location and storage tables: %w", err)
+	}
+
+	return nil
+}
+
+func createLoadoutTables(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS survivor_loadout (
+			survivor_id UUID PRIMARY KEY REFERENCES survivor(external_id) ON DELETE CASCADE,
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			grid TEXT[] NOT NULL CHECK (cardinality(grid) = 9),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_survivor_loadout_settlement ON survivor_loadout(settlement_id);
+
+		CREATE TABLE IF NOT EXISTS settlement_loadout_preset (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			name VARCHAR(100) NOT NULL,
+			grid TEXT[] NOT NULL CHECK (cardinality(grid) = 9),
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (settlement_id, name)
+		);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create loadout tables: %w", err)
+	}
+
+	return nil
+}
+
+func createMonsterProgressionTables(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_monster (
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			monster_id TEXT NOT NULL,
+			unlocked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (settlement_id, monster_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS settlement_nemesis_encounter (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			monster_id TEXT NOT NULL,
+			level INTEGER NOT NULL CHECK (level > 0),
+			year INTEGER NOT NULL,
+			timeline_id UUID NOT NULL REFERENCES settlement_timeline(external_id),
+			defeated BOOLEAN NOT NULL DEFAULT false
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_settlement_nemesis_encounter_year ON settlement_nemesis_encounter(settlement_id, year);
+
+		CREATE TABLE IF NOT EXISTS settlement_monster_defeat (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			monster_id TEXT NOT NULL,
+			monster_type VARCHAR(16) NOT NULL CHECK (monster_type IN ('quarry', 'nemesis')),
+			level INTEGER NOT NULL CHECK (level > 0),
+			year INTEGER NOT NULL,
+			encounter_id UUID UNIQUE REFERENCES settlement_nemesis_encounter(external_id),
+			recorded_by VARCHAR(255) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_settlement_monster_defeat_settlement ON settlement_monster_defeat(settlement_id, monster_type);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create monster progression tables: %w", err)
+	}
+
+	return nil
+}
+
+func createSettlementJournalTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS settlement_journal (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			survivor_id UUID REFERENCES survivor(external_id) ON DELETE SET NULL,
+			year INTEGER CHECK (year >= 0),
+			title VARCHAR(200) NOT NULL,
+			body TEXT NOT NULL DEFAULT '',
+			tags TEXT[] NOT NULL DEFAULT '{}',
+			search TSVECTOR GENERATED ALWAYS AS (
+				setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', body), 'B')
+			) STORED,
+			created_by VARCHAR(255) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_settlement_journal_settlement ON settlement_journal(settlement_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_settlement_journal_search ON settlement_journal USING GIN(search);
+		CREATE INDEX IF NOT EXISTS idx_settlement_journal_tags ON settlement_journal USING GIN(tags);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement journal table: %w", err)
+	}
+
+	return nil
+}
+
+func createSurvivorFateTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS survivor_fate (
+			survivor_id UUID PRIMARY KEY REFERENCES survivor(external_id) ON DELETE CASCADE,
+			settlement_id UUID NOT NULL REFERENCES settlement(external_id),
+			status VARCHAR(32) NOT NULL CHECK (status IN ('Dead', 'Retired', 'Ceased to exist')),
+			year INTEGER NOT NULL CHECK (year >= 0),
+			cause_kind VARCHAR(32) CHECK (cause_kind IN ('monster', 'event', 'severe-injury')),
+			cause VARCHAR(200),
+			phase VARCHAR(16) CHECK (phase IN ('hunt', 'showdown', 'settlement')),
+			epitaph TEXT NOT NULL DEFAULT '',
+			recorded_by VARCHAR(255) NOT NULL,
+			recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CHECK ((cause_kind IS NULL) = (cause IS NULL))
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_survivor_fate_settlement ON survivor_fate(settlement_id, year);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create survivor fate table: %w", err)
+	}
+
+	return nil
+}
+
+func createAccessTokenTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS access_token (
+			id SERIAL PRIMARY KEY,
+			external_id UUID NOT NULL UNIQUE DEFAULT uuidv7(),
+			user_id VARCHAR(255) NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			prefix VARCHAR(16) NOT NULL,
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			scope VARCHAR(16) NOT NULL CHECK (scope IN ('read', 'read-write')),
+			expires_at TIMESTAMPTZ,
+			last_used_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_access_token_user ON access_token(user_id, created_at);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create access token table: %w", err)
+	}
+
+	return nil
+}
+
+func createSessionTables(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS auth_session (
+			id VARCHAR(64) PRIMARY KEY,
+			data BYTEA NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_auth_session_expires ON auth_session(expires_at);
+
+		CREATE TABLE IF NOT EXISTS auth_session_index (
+			user_id VARCHAR(255) NOT NULL,
+			session_id VARCHAR(64) NOT NULL,
+			device BYTEA NOT NULL,
+			PRIMARY KEY (user_id, session_id)
+		);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create session tables: %w", err)
+	}
+
+	return nil
+}
+
+// addSurvivorSearchIndexes needs pg_trgm. Creating the extension requires
+// superuser or the CREATE privilege on the database, so a deploy whose
+// migration role has neither must create it beforehand.
+func addSurvivorSearchIndexes(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE EXTENSION IF NOT EXISTS pg_trgm;
+		CREATE INDEX IF NOT EXISTS idx_survivors_name_trgm ON survivor USING gin (name gin_trgm_ops);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_name_sort ON survivor(settlement_id, name, external_id);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to add survivor search indexes: %w", err)
+	}
+
+	return nil
+}
+
+func createIntrospectionTable(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE TABLE IF NOT EXISTS auth_introspection (
+			key TEXT PRIMARY KEY,
+			data BYTEA NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_auth_introspection_expires ON auth_introspection(expires_at);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to create introspection table: %w", err)
+	}
+
+	return nil
+}
+
+func addSurvivorStatSortIndexes(ctx context.Context, tx pgx.Tx) error {
+	create := `
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_hunt_xp ON survivor(settlement_id, hunt_xp, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_survival ON survivor(settlement_id, survival, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_movement ON survivor(settlement_id, movement, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_accuracy ON survivor(settlement_id, accuracy, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_strength ON survivor(settlement_id, strength, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_evasion ON survivor(settlement_id, evasion, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_luck ON survivor(settlement_id, luck, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_speed ON survivor(settlement_id, speed, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_insanity ON survivor(settlement_id, insanity, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_systemic_pressure ON survivor(settlement_id, systemic_pressure, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_torment ON survivor(settlement_id, torment, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_lumi ON survivor(settlement_id, lumi, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_courage ON survivor(settlement_id, courage, external_id);
+		CREATE INDEX IF NOT EXISTS idx_survivors_settlement_understanding ON survivor(settlement_id, understanding, external_id);
+	`
+
+	_, err := tx.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("failed to add survivor stat sort indexes: %w", err)
+	}
+
+	return nil
+}
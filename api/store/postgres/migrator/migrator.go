@@ -12,9 +12,30 @@ import (
 type migration func(context.Context, pgx.Tx) error
 
 var migrations = map[int]migration{
-	1: createSettlementTable,
-	2: createSurvivorTable,
-	3: addFightingArtsToSurvivor,
+	1:  createSettlementTable,
+	2:  createSurvivorTable,
+	3:  addFightingArtsToSurvivor,
+	4:  createSurvivorKnowledgeTable,
+	5:  createSettlementMilestoneTable,
+	6:  createSurvivorMilestoneTable,
+	7:  addInnovationsAndParentage,
+	8:  addSurvivorListIndexes,
+	9:  addSurvivorDeparting,
+	10: createSettlementTimelineTable,
+	11: createSettlementEventDeckTables,
+	12: createSettlementRollLogTable,
+	13: addSurvivorImpairmentsAndRollTargets,
+	14: createSettlementEndeavorTable,
+	15: createSettlementLocationAndStorageTables,
+	16: createLoadoutTables,
+	17: createMonsterProgressionTables,
+	18: createSettlementJournalTable,
+	19: createSurvivorFateTable,
+	20: createAccessTokenTable,
+	21: createSessionTables,
+	22: addSurvivorSearchIndexes,
+	23: createIntrospectionTable,
+	24: addSurvivorStatSortIndexes,
 }
 
 func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
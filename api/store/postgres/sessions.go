@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	DefaultSweepInterval = 5 * time.Minute
+
+	setSession = `INSERT INTO auth_session (id, data, expires_at)
+VALUES ($1, $2, NOW() + $3::interval)
+ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at`
+	getSession    = `SELECT data FROM auth_session WHERE id = $1 AND expires_at > NOW()`
+	sessionExists = `SELECT EXISTS (SELECT 1 FROM auth_session WHERE id = $1 AND expires_at > NOW())`
+	deleteSession = `DELETE FROM auth_session WHERE id = $1`
+	indexSession  = `INSERT INTO auth_session_index (user_id, session_id, device)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, session_id) DO UPDATE SET device = EXCLUDED.device`
+	indexedSessions = `SELECT session_id, device FROM auth_session_index WHERE user_id = $1`
+	unindexSession  = `DELETE FROM auth_session_index WHERE user_id = $1 AND session_id = $2`
+	sweepSessions   = `DELETE FROM auth_session WHERE expires_at <= NOW()`
+	sweepIndex      = `DELETE FROM auth_session_index i
+WHERE NOT EXISTS (SELECT 1 FROM auth_session s WHERE s.id = i.session_id)`
+)
+
+type SessionStore struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionStore(p *pgxpool.Pool) (*SessionStore, error) {
+	if p == nil {
+		return nil, errors.New("session store: pgx connection pool is required")
+	}
+	return &SessionStore{db: p}, nil
+}
+
+func (s *SessionStore) Set(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error {
+	_, err := s.db.Exec(ctx, setSession, sessionID, data, ttl)
+	return err
+}
+
+func (s *SessionStore) Exists(ctx context.Context, sessionID string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(ctx, sessionExists, sessionID).Scan(&exists)
+	return exists, err
+}
+
+func (s *SessionStore) Get(ctx context.Context, sessionID string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(ctx, getSession, sessionID).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *SessionStore) Delete(ctx context.Context, sessionID string) error {
+	_, err := s.db.Exec(ctx, deleteSession, sessionID)
+	return err
+}
+
+func (s *SessionStore) IndexSession(ctx context.Context, userID, sessionID string, device []byte) error {
+	_, err := s.db.Exec(ctx, indexSession, userID, sessionID, device)
+	return err
+}
+
+func (s *SessionStore) IndexedSessions(ctx context.Context, userID string) (map[string][]byte, error) {
+	rows, err := s.db.Query(ctx, indexedSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := map[string][]byte{}
+	var (
+		sessionID string
+		device    []byte
+	)
+	_, err = pgx.ForEachRow(rows, []any{&sessionID, &device}, func() error {
+		indexed[sessionID] = device
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return indexed, nil
+}
+
+func (s *SessionStore) UnindexSession(ctx context.Context, userID, sessionID string) error {
+	_, err := s.db.Exec(ctx, unindexSession, userID, sessionID)
+	return err
+}
+
+func (s *SessionStore) Sweep(ctx context.Context) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sweepSessions); err != nil {
+			return fmt.Errorf("unable to sweep sessions: %w", err)
+		}
+		if _, err := tx.Exec(ctx, sweepIndex); err != nil {
+			return fmt.Errorf("unable to sweep session index: %w", err)
+		}
+		if _, err := tx.Exec(ctx, sweepIntrospections); err != nil {
+			return fmt.Errorf("unable to sweep introspections: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *SessionStore) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Sweep(ctx); err != nil {
+				logger.Error(ctx, "sweeping expired sessions", logger.ErrorField(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package postgres_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/failuretoload/datamonster/store/postgres"
+	"github.com/failuretoload/datamonster/store/sessiontest"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dbContainer *testenv.DBContainer
+
+func TestMain(m *testing.M) {
+	var err error
+	dbContainer, err = testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for session store tests: %v", err)
+	}
+
+	exitCode := m.Run()
+	dbContainer.Cleanup()
+	os.Exit(exitCode)
+}
+
+func TestSessionStoreConformance(t *testing.T) {
+	store, err := postgres.NewSessionStore(dbContainer.PGPool)
+	require.NoError(t, err)
+
+	sessiontest.Run(t, store)
+}
+
+func TestSweepDeletesExpiredRows(t *testing.T) {
+	ctx := context.Background()
+	store, err := postgres.NewSessionStore(dbContainer.PGPool)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Set(ctx, "sweep-short", []byte("short"), time.Second))
+	require.NoError(t, store.Set(ctx, "sweep-long", []byte("long"), time.Hour))
+	require.NoError(t, store.IndexSession(ctx, "sweep-user", "sweep-short", []byte("phone")))
+	require.NoError(t, store.IndexSession(ctx, "sweep-user", "sweep-long", []byte("laptop")))
+
+	time.Sleep(1100 * time.Millisecond)
+	require.NoError(t, store.Sweep(ctx))
+
+	var rows int
+	require.NoError(t, dbContainer.PGPool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM auth_session WHERE id IN ('sweep-short', 'sweep-long')`,
+	).Scan(&rows))
+	assert.Equal(t, 1, rows)
+
+	indexed, err := store.IndexedSessions(ctx, "sweep-user")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"sweep-long": []byte("laptop")}, indexed)
+}
+
+func TestIntrospections(t *testing.T) {
+	ctx := context.Background()
+	store, err := postgres.NewSessionStore(dbContainer.PGPool)
+	require.NoError(t, err)
+	introspections := store.Introspections()
+
+	require.NoError(t, introspections.Set(ctx, "introspect-short", []byte("short"), time.Second))
+	require.NoError(t, introspections.Set(ctx, "introspect-long", []byte("long"), time.Hour))
+
+	data, err := introspections.Get(ctx, "introspect-long")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("long"), data)
+
+	time.Sleep(1100 * time.Millisecond)
+	data, err = introspections.Get(ctx, "introspect-short")
+	require.NoError(t, err)
+	assert.Nil(t, data, "expired rows are ignored before the sweep")
+
+	require.NoError(t, store.Sweep(ctx))
+	var rows int
+	require.NoError(t, dbContainer.PGPool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM auth_introspection WHERE key IN ('introspect-short', 'introspect-long')`,
+	).Scan(&rows))
+	assert.Equal(t, 1, rows)
+
+	require.NoError(t, introspections.Delete(ctx, "introspect-long"))
+	data, err = introspections.Get(ctx, "introspect-long")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestNewSessionStoreRequiresPool(t *testing.T) {
+	_, err := postgres.NewSessionStore(nil)
+	assert.Error(t, err)
+}
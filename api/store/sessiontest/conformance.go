@@ -0,0 +1,147 @@
+package sessiontest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/failuretoload/datamonster/auth"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ExpiryTTL = time.Second
+
+func Run(t *testing.T, store auth.SessionStore) {
+	t.Run("set get and delete", func(t *testing.T) { testSetGetDelete(t, store) })
+	t.Run("set replaces data", func(t *testing.T) { testSetReplaces(t, store) })
+	t.Run("missing session", func(t *testing.T) { testMissing(t, store) })
+	t.Run("sessions expire", func(t *testing.T) { testExpiry(t, store) })
+	t.Run("index", func(t *testing.T) { testIndex(t, store) })
+	t.Run("index is per user", func(t *testing.T) { testIndexIsolation(t, store) })
+}
+
+func unique(t *testing.T) string {
+	id, err := uuid.NewV4()
+	require.NoError(t, err)
+	return id.String()
+}
+
+func testSetGetDelete(t *testing.T, store auth.SessionStore) {
+	ctx := context.Background()
+	sessionID := unique(t)
+
+	require.NoError(t, store.Set(ctx, sessionID, []byte(`{"user_id":"a"}`), time.Hour))
+
+	data, err := store.Get(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`{"user_id":"a"}`), data)
+
+	exists, err := store.Exists(ctx, sessionID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, store.Delete(ctx, sessionID))
+
+	data, err = store.Get(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	exists, err = store.Exists(ctx, sessionID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func testSetReplaces(t *testing.T, store auth.SessionStore) {
+	ctx := context.Background()
+	sessionID := unique(t)
+
+	require.NoError(t, store.Set(ctx, sessionID, []byte("first"), time.Hour))
+	require.NoError(t, store.Set(ctx, sessionID, []byte("second"), time.Hour))
+
+	data, err := store.Get(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), data)
+}
+
+func testMissing(t *testing.T, store auth.SessionStore) {
+	ctx := context.Background()
+	sessionID := unique(t)
+
+	data, err := store.Get(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	exists, err := store.Exists(ctx, sessionID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, store.Delete(ctx, sessionID))
+	assert.NoError(t, store.UnindexSession(ctx, unique(t), sessionID))
+
+	indexed, err := store.IndexedSessions(ctx, unique(t))
+	require.NoError(t, err)
+	assert.NotNil(t, indexed)
+	assert.Empty(t, indexed)
+}
+
+func testExpiry(t *testing.T, store auth.SessionStore) {
+	ctx := context.Background()
+	short, long := unique(t), unique(t)
+
+	require.NoError(t, store.Set(ctx, short, []byte("short"), ExpiryTTL))
+	require.NoError(t, store.Set(ctx, long, []byte("long"), time.Hour))
+
+	require.Eventually(t, func() bool {
+		exists, err := store.Exists(ctx, short)
+		return err == nil && !exists
+	}, 5*ExpiryTTL, 50*time.Millisecond)
+
+	data, err := store.Get(ctx, short)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	data, err = store.Get(ctx, long)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("long"), data)
+}
+
+func testIndex(t *testing.T, store auth.SessionStore) {
+	ctx := context.Background()
+	userID, first, second := unique(t), unique(t), unique(t)
+
+	require.NoError(t, store.IndexSession(ctx, userID, first, []byte(`{"ip":"1"}`)))
+	require.NoError(t, store.IndexSession(ctx, userID, second, []byte(`{"ip":"2"}`)))
+	require.NoError(t, store.IndexSession(ctx, userID, first, []byte(`{"ip":"3"}`)))
+
+	indexed, err := store.IndexedSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		first:  []byte(`{"ip":"3"}`),
+		second: []byte(`{"ip":"2"}`),
+	}, indexed)
+
+	require.NoError(t, store.UnindexSession(ctx, userID, first))
+
+	indexed, err = store.IndexedSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{second: []byte(`{"ip":"2"}`)}, indexed)
+}
+
+func testIndexIsolation(t *testing.T, store auth.SessionStore) {
+	ctx := context.Background()
+	alice, bob, sessionID := unique(t), unique(t), unique(t)
+
+	require.NoError(t, store.IndexSession(ctx, alice, sessionID, []byte("alice")))
+
+	indexed, err := store.IndexedSessions(ctx, bob)
+	require.NoError(t, err)
+	assert.Empty(t, indexed)
+
+	require.NoError(t, store.UnindexSession(ctx, bob, sessionID))
+
+	indexed, err = store.IndexedSessions(ctx, alice)
+	require.NoError(t, err)
+	assert.Len(t, indexed, 1)
+}
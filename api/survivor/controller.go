@@ -2,10 +2,16 @@ package survivor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/failuretoload/datamonster/middleware"
 	"github.com/failuretoload/datamonster/request"
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
 	"github.com/failuretoload/datamonster/survivor/domain"
 	"github.com/gofrs/uuid/v5"
 
@@ -15,41 +21,94 @@ import (
 )
 
 type Repo interface {
-	All(ctx context.Context, settlementID uuid.UUID) ([]domain.Survivor, error)
+	List(ctx context.Context, settlementID uuid.UUID, q domain.ListQuery) (domain.SurvivorPage, error)
+	Get(ctx context.Context, settlementID, survivorID uuid.UUID) (*domain.Survivor, error)
 	Create(ctx context.Context, d domain.Survivor) (domain.Survivor, error)
-	Update(ctx context.Context, settlementID, survivorID uuid.UUID, updates domain.SurvivorUpdate) (domain.Survivor, error)
+	Birth(ctx context.Context, child domain.Survivor, parents []uuid.UUID) (domain.Survivor, error)
+	Lineage(ctx context.Context, settlementID uuid.UUID, q domain.LineageQuery) (domain.Lineage, error)
+	Update(ctx context.Context, settlementID, survivorID uuid.UUID, updates domain.SurvivorUpdate, rules domain.MilestoneRules) (domain.Survivor, []domain.Milestone, error)
+	BulkUpdate(ctx context.Context, settlementID uuid.UUID, update domain.BulkUpdate, rules domain.MilestoneRules) (domain.BulkResult, error)
+	PendingMilestones(ctx context.Context, settlementID, survivorID uuid.UUID) ([]domain.Milestone, error)
+	ResolveMilestone(ctx context.Context, settlementID, survivorID, milestoneID uuid.UUID) error
+	RecordFate(ctx context.Context, settlementID, survivorID uuid.UUID, fate domain.Fate, rules domain.MilestoneRules) (domain.Survivor, domain.Fate, error)
+	Memorial(ctx context.Context, settlementID uuid.UUID) ([]domain.MemorialEntry, error)
+}
+
+type Settlements interface {
+	Get(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Settlement, error)
+	Stats(ctx context.Context, userID string, settlementID uuid.UUID) (*settlementdomain.Stats, error)
+	FiredMilestones(ctx context.Context, settlementID uuid.UUID) ([]string, error)
 }
 
 type Controller struct {
-	db Repo
+	db          Repo
+	settlements Settlements
+	milestones  domain.MilestoneRules
+}
+
+type UpdateResult struct {
+	domain.Survivor
+	Resolved   map[string]int     `json:"resolved,omitempty"`
+	Milestones []domain.Milestone `json:"milestones"`
+}
+
+type FateResult struct {
+	Survivor   domain.Survivor              `json:"survivor"`
+	Fate       domain.Fate                  `json:"fate"`
+	DeathCount int                          `json:"deathCount"`
+	Milestones []settlementdomain.Milestone `json:"milestones"`
 }
 
-func NewController(r Repo) (*Controller, error) {
+func NewController(r Repo, s Settlements, milestones domain.MilestoneRules) (*Controller, error) {
 	if r == nil {
 		return nil, fmt.Errorf("repo cannot be nil")
 	}
-	return &Controller{db: r}, nil
+	if s == nil {
+		return nil, fmt.Errorf("settlements cannot be nil")
+	}
+	return &Controller{db: r, settlements: s, milestones: milestones}, nil
 }
 
 func (c Controller) RegisterRoutes(r chi.Router) {
 	r.Group(func(gr chi.Router) {
-		gr.Use(settlementIDToContext)
+		gr.Use(middleware.SettlementID)
+		gr.Use(middleware.RequireSettlement(c.settlements))
 		gr.Get("/settlements/{id}/survivors", c.getSurvivors)
 		gr.Post("/settlements/{id}/survivors", c.createSurvivor)
+		gr.Post("/settlements/{id}/survivors/birth", c.birthSurvivor)
+		gr.Post("/settlements/{id}/survivors/bulk", c.bulkUpdate)
 		gr.Patch("/settlements/{id}/survivors/{survivorID}", c.updateSurvivor)
+		gr.Post("/settlements/{id}/survivors/{survivorID}/fate", c.recordFate)
+		gr.Get("/settlements/{id}/memorial", c.getMemorial)
+		gr.Get("/settlements/{id}/lineage", c.getLineage)
+		gr.Get("/settlements/{id}/survivors/{survivorID}/milestones", c.getMilestones)
+		gr.Delete("/settlements/{id}/survivors/{survivorID}/milestones/{milestoneID}", c.resolveMilestone)
 	})
 }
 
 func (c Controller) getSurvivors(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	settlementID := request.SettlementID(ctx)
-	survivors, err := c.db.All(ctx, settlementID)
+	params := r.URL.Query()
+	q, err := domain.NewListQuery(
+		params["status"],
+		params.Get("name"),
+		params.Get("sort"),
+		params.Get("order"),
+		params.Get("limit"),
+		params.Get("cursor"),
+	)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	page, err := c.db.List(ctx, request.SettlementID(ctx), q)
 	if err != nil {
 		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving survivors: %w", err))
 		return
 	}
 
-	response.OK(ctx, w, survivors)
+	response.OK(ctx, w, page)
 }
 
 func (c Controller) createSurvivor(w http.ResponseWriter, r *http.Request) {
@@ -76,19 +135,129 @@ func (c Controller) createSurvivor(w http.ResponseWriter, r *http.Request) {
 	response.OK(ctx, w, survivor)
 }
 
+func (c Controller) birthSurvivor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body domain.BirthRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		response.BadRequest(ctx, w, fmt.Errorf("survivor name is required"))
+		return
+	}
+
+	if len(body.Parents) != 2 {
+		response.BadRequest(ctx, w, domain.ErrInvalidParents)
+		return
+	}
+
+	settlementID := request.SettlementID(ctx)
+	settlement := request.Settlement(ctx)
+
+	parents := make([]domain.Survivor, 0, len(body.Parents))
+	for _, parentID := range body.Parents {
+		parent, err := c.db.Get(ctx, settlementID, parentID)
+		if err != nil {
+			response.InternalServerError(ctx, w, fmt.Errorf("error retrieving parent: %w", err))
+			return
+		}
+		if parent == nil {
+			response.BadRequest(ctx, w, fmt.Errorf("parent %s not found", parentID))
+			return
+		}
+		parents = append(parents, *parent)
+	}
+
+	child, applied, err := domain.Newborn(body, settlement.CurrentYear, parents, domain.BirthRulesFor(settlement.Innovations))
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	born, err := c.db.Birth(ctx, child, body.Parents)
+	if errors.Is(err, domain.ErrDuplicateName) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error creating newborn: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, domain.BirthResult{Survivor: born, Parents: body.Parents, Applied: applied})
+}
+
+func (c Controller) getLineage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+
+	var root *uuid.UUID
+	if raw := params.Get("survivor"); raw != "" {
+		id, err := uuid.FromString(raw)
+		if err != nil {
+			response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+			return
+		}
+		root = &id
+	}
+
+	depth := 0
+	if raw := params.Get("depth"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			response.BadRequest(ctx, w, fmt.Errorf("invalid depth: %w", err))
+			return
+		}
+		depth = parsed
+	}
+
+	q, err := domain.NewLineageQuery(root, params.Get("direction"), depth)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	lineage, err := c.db.Lineage(ctx, request.SettlementID(ctx), q)
+	if errors.Is(err, domain.ErrSurvivorNotFound) {
+		response.NotFound(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving lineage: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, lineage)
+}
+
 func (c Controller) updateSurvivor(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	var updates domain.SurvivorUpdate
-	if err := request.DecodeJSON(r.Body, &updates); err != nil {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); !domain.PatchMediaType(mediaType) {
+		w.Header().Set("Accept-Patch", domain.MergePatchType+", application/json")
+		response.UnsupportedMediaType(ctx, w, fmt.Errorf("survivor patches must be sent as %s", domain.MergePatchType))
+		return
+	}
+
+	var patch domain.MergePatch
+	if err := request.DecodeJSON(r.Body, &patch); err != nil {
 		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
 		return
 	}
 
-	if updates.StatusUpdate != nil && !domain.ValidStatus(string(*updates.StatusUpdate)) {
-		response.BadRequest(ctx, w, fmt.Errorf("invalid status value: %s", *updates.StatusUpdate))
+	updates, err := patch.Update()
+	if err != nil {
+		response.BadRequest(ctx, w, err)
 		return
 	}
 
+	c.update(w, r, updates)
+}
+
+func (c Controller) update(w http.ResponseWriter, r *http.Request, updates domain.SurvivorUpdate) {
+	ctx := r.Context()
 	settlementID := request.SettlementID(ctx)
 	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
 	if err != nil {
@@ -96,25 +265,161 @@ func (c Controller) updateSurvivor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	survivor, err := c.db.Update(ctx, settlementID, survivorID, updates)
+	if updates.StatusUpdate != nil && domain.Fallen(*updates.StatusUpdate) {
+		updates.RecordFate(request.Settlement(ctx).CurrentYear, request.UserID(ctx))
+	}
+
+	survivor, milestones, err := c.db.Update(ctx, settlementID, survivorID, updates, c.milestones)
+	if errors.Is(err, domain.ErrSurvivorNotFound) {
+		response.NotFound(ctx, w, err)
+		return
+	}
 	if err != nil {
 		response.InternalServerError(ctx, w, fmt.Errorf("error updating survivor: %w", err))
 		return
 	}
 
-	response.OK(ctx, w, survivor)
+	response.OK(ctx, w, UpdateResult{
+		Survivor:   survivor,
+		Resolved:   domain.Resolved(survivor, updates.StatDeltas),
+		Milestones: milestones,
+	})
 }
 
-func settlementIDToContext(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		id, err := request.SettlementIDFromURL(r)
-		if err != nil {
-			response.InternalServerError(ctx, w, err)
-			return
-		}
+func (c Controller) bulkUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var update domain.BulkUpdate
+	if err := request.DecodeJSON(r.Body, &update); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	if err := update.Validate(); err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	result, err := c.db.BulkUpdate(ctx, request.SettlementID(ctx), update, c.milestones)
+	if errors.Is(err, domain.ErrSurvivorNotFound) {
+		response.NotFound(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error applying bulk update: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, result)
+}
 
-		ctx = request.SetSettlementID(ctx, id)
-		next.ServeHTTP(w, r.WithContext(ctx))
+func (c Controller) getMilestones(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	milestones, err := c.db.PendingMilestones(ctx, request.SettlementID(ctx), survivorID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving milestones: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, milestones)
+}
+
+func (c Controller) resolveMilestone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	milestoneID, err := uuid.FromString(chi.URLParam(r, "milestoneID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid milestone id"))
+		return
+	}
+
+	err = c.db.ResolveMilestone(ctx, request.SettlementID(ctx), survivorID, milestoneID)
+	if errors.Is(err, domain.ErrMilestoneNotFound) {
+		response.NotFound(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error resolving milestone: %w", err))
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (c Controller) recordFate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	survivorID, err := uuid.FromString(chi.URLParam(r, "survivorID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid survivor id"))
+		return
+	}
+
+	var body domain.FateRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	userID := request.UserID(ctx)
+	settlementID := request.SettlementID(ctx)
+	fate, err := body.Fate(request.Settlement(ctx).CurrentYear)
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+	fate.RecordedBy = userID
+
+	fallen, fate, err := c.db.RecordFate(ctx, settlementID, survivorID, fate, c.milestones)
+	if errors.Is(err, domain.ErrSurvivorNotFound) {
+		response.NotFound(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error recording fate: %w", err))
+		return
+	}
+
+	stats, err := c.settlements.Stats(ctx, userID, settlementID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving settlement stats: %w", err))
+		return
+	}
+	if stats == nil {
+		response.NotFound(ctx, w, fmt.Errorf("settlement not found"))
+		return
+	}
+
+	fired, err := c.settlements.FiredMilestones(ctx, settlementID)
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving settlement milestones: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, FateResult{
+		Survivor:   fallen,
+		Fate:       fate,
+		DeathCount: stats.DeathCount,
+		Milestones: settlementdomain.PendingMilestones(*stats, fired),
 	})
 }
+
+func (c Controller) getMemorial(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	entries, err := c.db.Memorial(ctx, request.SettlementID(ctx))
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving memorial: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, entries)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/failuretoload/datamonster/glossary"
 	"github.com/failuretoload/datamonster/server"
 	"github.com/failuretoload/datamonster/settlement"
 	settlementRepo "github.com/failuretoload/datamonster/settlement/repo"
@@ -38,16 +39,27 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	settlementController, err := settlement.NewController(settlementRepo)
+	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
 	if err != nil {
 		log.Fatal(err)
 	}
+	glossaryStub := testenv.NewGlossaryStub(fmt.Sprintf(`{"innovations":[
+		{"id":"%s","name":"Family","source":"core","keywords":"family"},
+		{"id":"%s","name":"Clan of Death","source":"core","keywords":"family"}
+	]}`, domain.InnovationFamily, domain.InnovationClanOfDeath))
+	defer glossaryStub.Close()
 
-	survivorRepo, err := survivorRepo.New(dbContainer.PGPool)
+	glossaryController, err := glossary.NewController(glossaryStub.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlementController, err := settlement.NewController(settlementRepo, glossaryController)
 	if err != nil {
 		log.Fatal(err)
 	}
-	survivorController, err := survivor.NewController(survivorRepo)
+
+	survivorController, err := survivor.NewController(survivorRepo, settlementRepo, domain.DefaultMilestoneRules)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -68,8 +80,14 @@ func TestGetSurvivors_Empty(t *testing.T) {
 	require.NoError(t, err)
 
 	body, status := requester.GetSurvivors(userID, settlementID)
-	assert.Equal(t, http.StatusOK, status)
-	assert.Equal(t, "null", body.String())
+	require.Equal(t, http.StatusOK, status)
+
+	var page domain.SurvivorPage
+	require.NoError(t, json.NewDecoder(body).Decode(&page))
+	assert.NotNil(t, page.Survivors)
+	assert.Empty(t, page.Survivors)
+	assert.Equal(t, 0, page.Total)
+	assert.Empty(t, page.NextCursor)
 }
 
 func TestGetSurvivors_ReturnsSurvivors(t *testing.T) {
@@ -86,12 +104,14 @@ func TestGetSurvivors_ReturnsSurvivors(t *testing.T) {
 	body, status := requester.GetSurvivors(userID, settlementID)
 	require.Equal(t, http.StatusOK, status)
 
-	var survivors []domain.Survivor
-	require.NoError(t, json.NewDecoder(body).Decode(&survivors))
-	require.Len(t, survivors, 2)
+	var page domain.SurvivorPage
+	require.NoError(t, json.NewDecoder(body).Decode(&page))
+	require.Len(t, page.Survivors, 2)
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, 2, page.Matched)
 
 	names := map[string]bool{}
-	for _, s := range survivors {
+	for _, s := range page.Survivors {
 		names[s.Name] = true
 		assert.NotEqual(t, uuid.Nil, s.ID)
 	}
@@ -99,6 +119,98 @@ func TestGetSurvivors_ReturnsSurvivors(t *testing.T) {
 	assert.True(t, names["Survivor Two"])
 }
 
+func listSurvivors(t *testing.T, userID, settlementID, query string) domain.SurvivorPage {
+	body, status := requester.ListSurvivors(userID, settlementID, query)
+	require.Equal(t, http.StatusOK, status)
+
+	var page domain.SurvivorPage
+	require.NoError(t, json.NewDecoder(body).Decode(&page))
+	return page
+}
+
+func pageNames(page domain.SurvivorPage) []string {
+	names := make([]string, 0, len(page.Survivors))
+	for _, s := range page.Survivors {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestGetSurvivors_FilterSortAndPaginate(t *testing.T) {
+	userID := "survivor-query-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for i, name := range []string{"Aldo", "Brin", "Cora", "Dell", "Edda"} {
+		raw, status := requester.CreateSurvivor(userID, settlementID, name)
+		require.Equal(t, http.StatusOK, status)
+
+		var s domain.Survivor
+		require.NoError(t, json.NewDecoder(raw).Decode(&s))
+		_, status = requester.UpdateSurvivor(userID, settlementID, s.ID.String(), fmt.Sprintf(`{"strength":%d}`, 5-i))
+		require.Equal(t, http.StatusOK, status)
+
+		if name == "Brin" || name == "Dell" {
+			_, status = requester.UpdateSurvivor(userID, settlementID, s.ID.String(), `{"status":"Dead"}`)
+			require.Equal(t, http.StatusOK, status)
+		}
+	}
+
+	t.Run("status filter", func(t *testing.T) {
+		page := listSurvivors(t, userID, settlementID, "status=Dead&sort=name")
+		assert.Equal(t, []string{"Brin", "Dell"}, pageNames(page))
+		assert.Equal(t, 5, page.Total)
+		assert.Equal(t, 2, page.Matched)
+
+		page = listSurvivors(t, userID, settlementID, "status=Dead,Alive")
+		assert.Equal(t, 5, page.Matched)
+	})
+
+	t.Run("name search", func(t *testing.T) {
+		page := listSurvivors(t, userID, settlementID, "name=d&sort=name")
+		assert.Equal(t, []string{"Aldo", "Dell", "Edda"}, pageNames(page))
+
+		page = listSurvivors(t, userID, settlementID, "name=%25")
+		assert.Empty(t, page.Survivors)
+	})
+
+	t.Run("stat sort", func(t *testing.T) {
+		page := listSurvivors(t, userID, settlementID, "sort=strength")
+		assert.Equal(t, []string{"Edda", "Dell", "Cora", "Brin", "Aldo"}, pageNames(page))
+
+		page = listSurvivors(t, userID, settlementID, "sort=strength&order=desc")
+		assert.Equal(t, []string{"Aldo", "Brin", "Cora", "Dell", "Edda"}, pageNames(page))
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		var names []string
+		query := "sort=name&limit=2"
+		for range 3 {
+			page := listSurvivors(t, userID, settlementID, query)
+			assert.Equal(t, 5, page.Matched)
+			names = append(names, pageNames(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			query = "sort=name&limit=2&cursor=" + page.NextCursor
+		}
+		assert.Equal(t, []string{"Aldo", "Brin", "Cora", "Dell", "Edda"}, names)
+	})
+}
+
+func TestGetSurvivors_InvalidQuery(t *testing.T) {
+	userID := "survivor-invalid-query-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for _, query := range []string{"status=Sleeping", "sort=hair", "order=up", "limit=0", "limit=many", "cursor=garbage"} {
+		_, status := requester.ListSurvivors(userID, settlementID, query)
+		assert.Equal(t, http.StatusBadRequest, status, query)
+	}
+}
+
 func TestGetSurvivors_InvalidSettlementID(t *testing.T) {
 	_, status := requester.GetSurvivors("invalid-id-user", "not-a-uuid")
 	assert.Equal(t, http.StatusInternalServerError, status)
@@ -201,7 +313,7 @@ func TestUpdateSurvivor_UpdateExisting(t *testing.T) {
 	var existing domain.Survivor
 	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
 
-	body := `{"statUpdates":{"huntxp":10,"survival":8,"movement":7,"accuracy":3,"strength":4,"evasion":2,"luck":5,"speed":3,"insanity":6,"systemicPressure":2,"torment":3,"lumi":4,"courage":7,"understanding":9}}`
+	body := `{"huntxp":10,"survival":8,"movement":7,"accuracy":3,"strength":4,"evasion":2,"luck":5,"speed":3,"insanity":6,"systemicPressure":2,"torment":3,"lumi":4,"courage":7,"understanding":9}`
 	respBody, status := requester.UpdateSurvivor(userID,
 		settlementID,
 		existing.ID.String(),
@@ -244,7 +356,7 @@ func TestUpdateSurvivor_UpdateStatus(t *testing.T) {
 	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
 	assert.Equal(t, domain.StatusAlive, existing.Status)
 
-	body := `{"statusUpdate":"Dead"}`
+	body := `{"status":"Dead"}`
 	respBody, status := requester.UpdateSurvivor(userID,
 		settlementID,
 		existing.ID.String(),
@@ -269,7 +381,7 @@ func TestUpdateSurvivor_UpdateStatusAndStats(t *testing.T) {
 	var existing domain.Survivor
 	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
 
-	body := `{"statUpdates":{"huntxp":5,"courage":3},"statusUpdate":"Retired"}`
+	body := `{"huntxp":5,"courage":3,"status":"Retired"}`
 	respBody, status := requester.UpdateSurvivor(userID,
 		settlementID,
 		existing.ID.String(),
@@ -296,7 +408,7 @@ func TestUpdateSurvivor_InvalidStatus(t *testing.T) {
 	var existing domain.Survivor
 	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
 
-	body := `{"statusUpdate":"NotAValidStatus"}`
+	body := `{"status":"NotAValidStatus"}`
 	_, status = requester.UpdateSurvivor(userID,
 		settlementID,
 		existing.ID.String(),
@@ -317,21 +429,21 @@ func TestUpdateSurvivor_NonExistentSurvivor(t *testing.T) {
 		survivorID,
 		`{"disorders":[],"fightingArt":null,"secretFightingArt":null}`,
 	)
-	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, http.StatusNotFound, status)
 }
 
 func TestUpdateSurvivor_InvalidSettlementID(t *testing.T) {
 	_, status := requester.UpdateSurvivor("upsert-invalid-settlement-user",
 		"not-a-uuid",
 		testenv.UUIDString(),
-		`{"statUpdates":{"huntxp":5}}`,
+		`{"huntxp":5}`,
 	)
 	assert.Equal(t, http.StatusInternalServerError, status)
 }
 
 func TestUpdateSurvivor_Unauthorized(t *testing.T) {
 	t.Cleanup(requester.Unauthorized())
-	_, status := requester.UpdateSurvivor("unauthorized", testenv.UUIDString(), testenv.UUIDString(), `{"statUpdates":{"huntxp":5}}`)
+	_, status := requester.UpdateSurvivor("unauthorized", testenv.UUIDString(), testenv.UUIDString(), `{"huntxp":5}`)
 
 	assert.Equal(t, http.StatusUnauthorized, status)
 }
@@ -446,7 +558,7 @@ func TestUpdateSurvivor_DisordersWithStats(t *testing.T) {
 	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
 
 	disorderID := "019412a0-0001-7000-8000-000000000001"
-	body := fmt.Sprintf(`{"statUpdates":{"insanity":5},"disorders":["%s"]}`, disorderID)
+	body := fmt.Sprintf(`{"insanity":5,"disorders":["%s"]}`, disorderID)
 	respBody, status := requester.UpdateSurvivor(userID,
 		settlementID,
 		existing.ID.String(),
@@ -567,7 +679,7 @@ func TestUpdateSurvivor_FightingArtsWithStats(t *testing.T) {
 
 	fightingArtID := "019412a0-0001-7000-8000-000000000010"
 	secretFightingArtID := "019412a0-0001-7000-8000-000000000020"
-	body := fmt.Sprintf(`{"statUpdates":{"courage":5},"fightingArt":"%s","secretFightingArt":"%s"}`, fightingArtID, secretFightingArtID)
+	body := fmt.Sprintf(`{"courage":5,"fightingArt":"%s","secretFightingArt":"%s"}`, fightingArtID, secretFightingArtID)
 	respBody, status := requester.UpdateSurvivor(userID,
 		settlementID,
 		existing.ID.String(),
@@ -583,3 +695,825 @@ func TestUpdateSurvivor_FightingArtsWithStats(t *testing.T) {
 	require.NotNil(t, survivor.SecretFightingArt)
 	assert.Equal(t, secretFightingArtID, survivor.SecretFightingArt.String())
 }
+
+func TestUpdateSurvivor_TriggersMilestones(t *testing.T) {
+	userID := "update-triggers-milestones-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	rawSurvivor, status := requester.CreateSurvivor(userID, settlementID, "Milestone Test")
+	require.Equal(t, http.StatusOK, status)
+
+	var existing domain.Survivor
+	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
+
+	respBody, status := requester.UpdateSurvivor(userID,
+		settlementID,
+		existing.ID.String(),
+		`{"huntxp":2,"courage":3,"understanding":1}`,
+	)
+	require.Equal(t, http.StatusOK, status)
+
+	var result survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(respBody).Decode(&result))
+	assert.Equal(t, 2, result.HuntXP)
+	require.Len(t, result.Milestones, 2)
+
+	events := map[string]bool{}
+	for _, m := range result.Milestones {
+		events[m.Event] = true
+		assert.Equal(t, existing.ID, m.SurvivorID)
+	}
+	assert.True(t, events["Age"])
+	assert.True(t, events["Bold"])
+
+	body, status := requester.GetSurvivorMilestones(userID, settlementID, existing.ID.String())
+	require.Equal(t, http.StatusOK, status)
+
+	var pending []domain.Milestone
+	require.NoError(t, json.NewDecoder(body).Decode(&pending))
+	assert.Len(t, pending, 2)
+}
+
+func TestUpdateSurvivor_MilestonesFireOnce(t *testing.T) {
+	userID := "milestones-fire-once-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	rawSurvivor, status := requester.CreateSurvivor(userID, settlementID, "Fire Once Test")
+	require.Equal(t, http.StatusOK, status)
+
+	var existing domain.Survivor
+	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
+
+	for _, courage := range []int{3, 0, 3} {
+		_, status = requester.UpdateSurvivor(userID,
+			settlementID,
+			existing.ID.String(),
+			fmt.Sprintf(`{"courage":%d}`, courage),
+		)
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	body, status := requester.GetSurvivorMilestones(userID, settlementID, existing.ID.String())
+	require.Equal(t, http.StatusOK, status)
+
+	var pending []domain.Milestone
+	require.NoError(t, json.NewDecoder(body).Decode(&pending))
+	require.Len(t, pending, 1)
+	assert.Equal(t, "Bold", pending[0].Event)
+}
+
+func TestResolveMilestone_RemovesFromQueue(t *testing.T) {
+	userID := "resolve-milestone-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	rawSurvivor, status := requester.CreateSurvivor(userID, settlementID, "Resolve Test")
+	require.Equal(t, http.StatusOK, status)
+
+	var existing domain.Survivor
+	require.NoError(t, json.NewDecoder(rawSurvivor).Decode(&existing))
+
+	respBody, status := requester.UpdateSurvivor(userID,
+		settlementID,
+		existing.ID.String(),
+		`{"understanding":3}`,
+	)
+	require.Equal(t, http.StatusOK, status)
+
+	var result survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(respBody).Decode(&result))
+	require.Len(t, result.Milestones, 1)
+	assert.Equal(t, "Insight", result.Milestones[0].Event)
+
+	_, status = requester.ResolveSurvivorMilestone(userID, settlementID, existing.ID.String(), result.Milestones[0].ID.String())
+	require.Equal(t, http.StatusNoContent, status)
+
+	body, status := requester.GetSurvivorMilestones(userID, settlementID, existing.ID.String())
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, "[]", body.String())
+
+	_, status = requester.ResolveSurvivorMilestone(userID, settlementID, existing.ID.String(), result.Milestones[0].ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func createParents(t *testing.T, userID, settlementID string) (domain.Survivor, domain.Survivor) {
+	var parents []domain.Survivor
+	for _, name := range []string{"Ada Ashborn", "Bram"} {
+		raw, status := requester.CreateSurvivor(userID, settlementID, name)
+		require.Equal(t, http.StatusOK, status)
+
+		var parent domain.Survivor
+		require.NoError(t, json.NewDecoder(raw).Decode(&parent))
+		parents = append(parents, parent)
+	}
+
+	return parents[0], parents[1]
+}
+
+func TestBirthSurvivor_NoInnovations(t *testing.T) {
+	userID := "birth-no-innovations-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	mother, father := createParents(t, userID, settlementID)
+
+	body := fmt.Sprintf(`{"parents":["%s","%s"],"name":"Cael","gender":"M"}`, mother.ID, father.ID)
+	respBody, status := requester.BirthSurvivor(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	var born domain.BirthResult
+	require.NoError(t, json.NewDecoder(respBody).Decode(&born))
+	assert.Equal(t, "Cael", born.Name)
+	assert.Equal(t, 0, born.Birth)
+	assert.Equal(t, domain.StatusAlive, born.Status)
+	assert.Equal(t, 5, born.Movement)
+	assert.Equal(t, 0, born.Strength)
+	assert.Equal(t, []uuid.UUID{mother.ID, father.ID}, born.Parents)
+	assert.Empty(t, born.Applied)
+}
+
+func TestBirthSurvivor_AppliesInnovations(t *testing.T) {
+	userID := "birth-innovations-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for _, id := range []string{domain.InnovationFamily, domain.InnovationClanOfDeath} {
+		_, status := requester.AddInnovation(userID, settlementID, id)
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	mother, father := createParents(t, userID, settlementID)
+	fightingArtID := "019412a0-0003-7000-8000-000000000003"
+	_, status := requester.UpdateSurvivor(userID, settlementID, father.ID.String(), fmt.Sprintf(`{"fightingArt":"%s"}`, fightingArtID))
+	require.Equal(t, http.StatusOK, status)
+
+	body := fmt.Sprintf(`{"parents":["%s","%s"],"name":"Cael","gender":"M"}`, mother.ID, father.ID)
+	respBody, status := requester.BirthSurvivor(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	var born domain.BirthResult
+	require.NoError(t, json.NewDecoder(respBody).Decode(&born))
+	assert.Equal(t, "Cael Ashborn", born.Name)
+	assert.Equal(t, 1, born.Accuracy)
+	assert.Equal(t, 1, born.Strength)
+	assert.Equal(t, 1, born.Evasion)
+	require.NotNil(t, born.FightingArt)
+	assert.Equal(t, fightingArtID, born.FightingArt.String())
+	assert.Equal(t, []string{"Family", "Clan of Death"}, born.Applied)
+}
+
+func TestBirthSurvivor_InheritedNameTaken(t *testing.T) {
+	userID := "birth-name-taken-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	_, status := requester.AddInnovation(userID, settlementID, domain.InnovationFamily)
+	require.Equal(t, http.StatusOK, status)
+	mother, father := createParents(t, userID, settlementID)
+
+	body := fmt.Sprintf(`{"parents":["%s","%s"],"name":"Cael","gender":"M"}`, mother.ID, father.ID)
+	_, status = requester.BirthSurvivor(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.BirthSurvivor(userID, settlementID, body)
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestBirthSurvivor_InvalidParents(t *testing.T) {
+	userID := "birth-invalid-parents-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	mother, father := createParents(t, userID, settlementID)
+
+	_, status := requester.BirthSurvivor(userID, settlementID, fmt.Sprintf(`{"parents":["%s"],"name":"Solo","gender":"F"}`, mother.ID))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.BirthSurvivor(userID, settlementID, fmt.Sprintf(`{"parents":["%s","%s"],"name":"Same","gender":"F"}`, mother.ID, mother.ID))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.BirthSurvivor(userID, settlementID, fmt.Sprintf(`{"parents":["%s","%s"],"name":"Ghost","gender":"F"}`, mother.ID, testenv.UUIDString()))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.UpdateSurvivor(userID, settlementID, father.ID.String(), `{"status":"Dead"}`)
+	require.Equal(t, http.StatusOK, status)
+
+	_, status = requester.BirthSurvivor(userID, settlementID, fmt.Sprintf(`{"parents":["%s","%s"],"name":"Orphan","gender":"F"}`, mother.ID, father.ID))
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestBirthSurvivor_MissingName(t *testing.T) {
+	userID := "birth-missing-name-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	mother, father := createParents(t, userID, settlementID)
+
+	_, status := requester.BirthSurvivor(userID, settlementID, fmt.Sprintf(`{"parents":["%s","%s"],"gender":"F"}`, mother.ID, father.ID))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.BirthSurvivor(userID, settlementID, fmt.Sprintf(`{"parents":["%s","%s"],"name":"   ","gender":"F"}`, mother.ID, father.ID))
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestBirthSurvivor_SettlementNotFound(t *testing.T) {
+	_, status := requester.BirthSurvivor("birth-not-found-user", testenv.UUIDString(), fmt.Sprintf(`{"parents":["%s","%s"],"name":"Nobody","gender":"F"}`, testenv.UUIDString(), testenv.UUIDString()))
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func birth(t *testing.T, userID, settlementID string, mother, father uuid.UUID, name string) domain.BirthResult {
+	body := fmt.Sprintf(`{"parents":["%s","%s"],"name":"%s","gender":"F"}`, mother, father, name)
+	raw, status := requester.BirthSurvivor(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	var born domain.BirthResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&born))
+	return born
+}
+
+func lineageNodes(t *testing.T, userID, settlementID, query string) map[uuid.UUID]domain.LineageNode {
+	raw, status := requester.GetLineage(userID, settlementID, query)
+	require.Equal(t, http.StatusOK, status)
+
+	var lineage domain.Lineage
+	require.NoError(t, json.NewDecoder(raw).Decode(&lineage))
+
+	nodes := map[uuid.UUID]domain.LineageNode{}
+	for _, n := range lineage.Survivors {
+		nodes[n.ID] = n
+	}
+	return nodes
+}
+
+func TestGetLineage(t *testing.T) {
+	userID := "lineage-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	mother, father := createParents(t, userID, settlementID)
+	child := birth(t, userID, settlementID, mother.ID, father.ID, "Cael")
+
+	raw, status := requester.CreateSurvivor(userID, settlementID, "Dara")
+	require.Equal(t, http.StatusOK, status)
+	var partner domain.Survivor
+	require.NoError(t, json.NewDecoder(raw).Decode(&partner))
+	grandchild := birth(t, userID, settlementID, child.ID, partner.ID, "Esk")
+
+	t.Run("whole settlement", func(t *testing.T) {
+		nodes := lineageNodes(t, userID, settlementID, "")
+		require.Len(t, nodes, 5)
+		assert.ElementsMatch(t, []uuid.UUID{mother.ID, father.ID}, nodes[child.ID].Parents)
+		assert.Equal(t, []uuid.UUID{father.ID}, nodes[mother.ID].Partners)
+		assert.Equal(t, []uuid.UUID{grandchild.ID}, nodes[partner.ID].Children)
+	})
+
+	t.Run("ancestors", func(t *testing.T) {
+		nodes := lineageNodes(t, userID, settlementID, "survivor="+grandchild.ID.String()+"&direction=ancestors")
+		require.Len(t, nodes, 5)
+		assert.Equal(t, 0, nodes[grandchild.ID].Generation)
+		assert.Equal(t, -1, nodes[child.ID].Generation)
+		assert.Equal(t, -1, nodes[partner.ID].Generation)
+		assert.Equal(t, -2, nodes[mother.ID].Generation)
+	})
+
+	t.Run("descendants", func(t *testing.T) {
+		nodes := lineageNodes(t, userID, settlementID, "survivor="+mother.ID.String()+"&direction=descendants")
+		require.Len(t, nodes, 3)
+		assert.Equal(t, 1, nodes[child.ID].Generation)
+		assert.Equal(t, 2, nodes[grandchild.ID].Generation)
+		assert.NotContains(t, nodes, father.ID)
+	})
+
+	t.Run("depth limit", func(t *testing.T) {
+		nodes := lineageNodes(t, userID, settlementID, "survivor="+grandchild.ID.String()+"&direction=ancestors&depth=1")
+		require.Len(t, nodes, 3)
+		assert.NotContains(t, nodes, mother.ID)
+	})
+}
+
+func TestGetLineage_InvalidQuery(t *testing.T) {
+	userID := "lineage-invalid-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for _, query := range []string{"survivor=nope", "direction=sideways", "depth=-1", "depth=many"} {
+		_, status := requester.GetLineage(userID, settlementID, query)
+		assert.Equal(t, http.StatusBadRequest, status, query)
+	}
+
+	_, status := requester.GetLineage(userID, settlementID, "survivor="+testenv.UUIDString())
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func createSurvivors(t *testing.T, userID, settlementID string, names ...string) []domain.Survivor {
+	survivors := make([]domain.Survivor, 0, len(names))
+	for _, name := range names {
+		raw, status := requester.CreateSurvivor(userID, settlementID, name)
+		require.Equal(t, http.StatusOK, status)
+
+		var s domain.Survivor
+		require.NoError(t, json.NewDecoder(raw).Decode(&s))
+		survivors = append(survivors, s)
+	}
+	return survivors
+}
+
+func bulkUpdate(t *testing.T, userID, settlementID, body string) domain.BulkResult {
+	raw, status := requester.BulkUpdateSurvivors(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	var result domain.BulkResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	return result
+}
+
+func TestBulkUpdate_ByStatus(t *testing.T) {
+	userID := "bulk-status-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	survivors := createSurvivors(t, userID, settlementID, "Aldo", "Brin", "Cora")
+
+	_, status := requester.UpdateSurvivor(userID, settlementID, survivors[2].ID.String(), `{"status":"Dead"}`)
+	require.Equal(t, http.StatusOK, status)
+
+	result := bulkUpdate(t, userID, settlementID, `{"selector":{"status":"Alive"},"statDeltas":{"insanity":1}}`)
+	require.Len(t, result.Survivors, 2)
+	for _, s := range result.Survivors {
+		assert.Equal(t, 1, s.Insanity)
+	}
+
+	result = bulkUpdate(t, userID, settlementID, `{"selector":{"status":"Alive"},"statDeltas":{"insanity":2,"survival":1}}`)
+	require.Len(t, result.Survivors, 2)
+	for _, s := range result.Survivors {
+		assert.Equal(t, 3, s.Insanity)
+		assert.Equal(t, 2, s.Survival)
+	}
+
+	page := listSurvivors(t, userID, settlementID, "status=Dead")
+	require.Len(t, page.Survivors, 1)
+	assert.Equal(t, 0, page.Survivors[0].Insanity)
+}
+
+func TestBulkUpdate_ByIDs(t *testing.T) {
+	userID := "bulk-ids-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	survivors := createSurvivors(t, userID, settlementID, "Aldo", "Brin", "Cora")
+
+	body := fmt.Sprintf(`{"selector":{"ids":["%s","%s"]},"statDeltas":{"strength":-1}}`, survivors[0].ID, survivors[2].ID)
+	result := bulkUpdate(t, userID, settlementID, body)
+	require.Len(t, result.Survivors, 2)
+	assert.Equal(t, -1, result.Survivors[0].Strength)
+	assert.Equal(t, -1, result.Survivors[1].Strength)
+
+	body = fmt.Sprintf(`{"selector":{"ids":["%s","%s"]},"statDeltas":{"strength":5}}`, survivors[1].ID, testenv.UUIDString())
+	_, status := requester.BulkUpdateSurvivors(userID, settlementID, body)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	page := listSurvivors(t, userID, settlementID, "name=Brin")
+	require.Len(t, page.Survivors, 1)
+	assert.Equal(t, 0, page.Survivors[0].Strength, "failed bulk update must not apply partially")
+}
+
+func TestBulkUpdate_Departing(t *testing.T) {
+	userID := "bulk-departing-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	survivors := createSurvivors(t, userID, settlementID, "Aldo", "Brin", "Cora")
+
+	for _, s := range survivors[:2] {
+		_, status := requester.UpdateSurvivor(userID, settlementID, s.ID.String(), `{"departing":true}`)
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	result := bulkUpdate(t, userID, settlementID, `{"selector":{"departing":true},"statDeltas":{"survival":2}}`)
+	require.Len(t, result.Survivors, 2)
+	for _, s := range result.Survivors {
+		assert.True(t, s.Departing)
+		assert.Equal(t, 3, s.Survival)
+	}
+}
+
+func TestBulkUpdate_QueuesMilestones(t *testing.T) {
+	userID := "bulk-milestone-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	createSurvivors(t, userID, settlementID, "Aldo", "Brin")
+
+	result := bulkUpdate(t, userID, settlementID, `{"selector":{"status":"Alive"},"statDeltas":{"huntxp":2}}`)
+	require.Len(t, result.Survivors, 2)
+	assert.Len(t, result.Milestones, 2)
+}
+
+func TestBulkUpdate_InvalidRequest(t *testing.T) {
+	userID := "bulk-invalid-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	for _, body := range []string{
+		`{"selector":{},"statDeltas":{"insanity":1}}`,
+		`{"selector":{"status":"Alive","departing":true},"statDeltas":{"insanity":1}}`,
+		`{"selector":{"status":"Sleeping"},"statDeltas":{"insanity":1}}`,
+		`{"selector":{"status":"Alive"},"statDeltas":{}}`,
+		`{"selector":{"status":"Alive"},"statDeltas":{"hair":1}}`,
+	} {
+		_, status := requester.BulkUpdateSurvivors(userID, settlementID, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+}
+
+func TestUpdateSurvivor_StatDeltas(t *testing.T) {
+	userID := "update-stat-deltas-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	existing := createSurvivors(t, userID, settlementID, "Delta")[0]
+
+	for range 2 {
+		_, status := requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), `{"statDeltas":{"strength":1}}`)
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	respBody, status := requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), `{"statDeltas":{"strength":1,"courage":20,"insanity":-4},"luck":2}`)
+	require.Equal(t, http.StatusOK, status)
+
+	var result survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(respBody).Decode(&result))
+	assert.Equal(t, 3, result.Strength)
+	assert.Equal(t, 9, result.Courage, "courage clamps to its ceiling")
+	assert.Equal(t, 0, result.Insanity, "insanity clamps to its floor")
+	assert.Equal(t, 2, result.Luck, "absolute writes apply in the same patch")
+	assert.Equal(t, map[string]int{"strength": 3, "courage": 9, "insanity": 0}, result.Resolved)
+}
+
+func TestUpdateSurvivor_StatDeltasInvalid(t *testing.T) {
+	userID := "update-stat-deltas-invalid-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	existing := createSurvivors(t, userID, settlementID, "Delta")[0]
+
+	for _, body := range []string{
+		`{"statDeltas":{"hair":1}}`,
+		`{"statDeltas":null}`,
+		`{"statDeltas":{"strength":1},"strength":2}`,
+	} {
+		_, status := requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+
+	_, status := requester.UpdateSurvivor(userID, settlementID, testenv.UUIDString(), `{"statDeltas":{"strength":1}}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestBulkUpdate_ClampsDeltas(t *testing.T) {
+	userID := "bulk-clamp-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	createSurvivors(t, userID, settlementID, "Aldo", "Brin")
+
+	result := bulkUpdate(t, userID, settlementID, `{"selector":{"status":"Alive"},"statDeltas":{"huntxp":40,"survival":-3}}`)
+	require.Len(t, result.Survivors, 2)
+	for _, s := range result.Survivors {
+		assert.Equal(t, 16, s.HuntXP)
+		assert.Equal(t, 0, s.Survival)
+	}
+}
+
+func TestUpdateSurvivor_PatchKeepsOmittedFields(t *testing.T) {
+	userID := "patch-keeps-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	disorderID := "019412a0-0001-7000-8000-000000000001"
+	fightingArtID := "019412a0-0003-7000-8000-000000000003"
+	body := fmt.Sprintf(`{"name":"Keeper","birth":1,"gender":"F","disorders":["%s"],"fightingArt":"%s"}`, disorderID, fightingArtID)
+	raw, status := requester.CreateSurvivorWithBody(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	var existing domain.Survivor
+	require.NoError(t, json.NewDecoder(raw).Decode(&existing))
+
+	raw, status = requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), `{"strength":2}`)
+	require.Equal(t, http.StatusOK, status)
+
+	var updated survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&updated))
+	assert.Equal(t, 2, updated.Strength)
+	require.Len(t, updated.Disorders, 1)
+	assert.Equal(t, disorderID, updated.Disorders[0].String())
+	require.NotNil(t, updated.FightingArt)
+	assert.Equal(t, fightingArtID, updated.FightingArt.String())
+}
+
+func TestUpdateSurvivor_PatchNullClears(t *testing.T) {
+	userID := "patch-null-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+
+	fightingArtID := "019412a0-0003-7000-8000-000000000003"
+	body := fmt.Sprintf(`{"name":"Clearer","birth":1,"gender":"F","fightingArt":"%s","secretFightingArt":"%s"}`, fightingArtID, fightingArtID)
+	raw, status := requester.CreateSurvivorWithBody(userID, settlementID, body)
+	require.Equal(t, http.StatusOK, status)
+
+	var existing domain.Survivor
+	require.NoError(t, json.NewDecoder(raw).Decode(&existing))
+
+	raw, status = requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), `{"fightingArt":null}`)
+	require.Equal(t, http.StatusOK, status)
+
+	var updated survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&updated))
+	assert.Nil(t, updated.FightingArt)
+	require.NotNil(t, updated.SecretFightingArt)
+	assert.Equal(t, fightingArtID, updated.SecretFightingArt.String())
+}
+
+func TestUpdateSurvivor_EmptyPatch(t *testing.T) {
+	userID := "patch-empty-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	existing := createSurvivors(t, userID, settlementID, "Unchanged")[0]
+
+	raw, status := requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), `{}`)
+	require.Equal(t, http.StatusOK, status)
+
+	var updated survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&updated))
+	assert.Equal(t, existing.ID, updated.ID)
+	assert.NotNil(t, updated.Milestones)
+}
+
+func TestUpdateSurvivor_PatchRejectsInvalidMembers(t *testing.T) {
+	userID := "update-unknown-stat-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	existing := createSurvivors(t, userID, settlementID, "Unknown")[0]
+
+	for _, body := range []string{
+		`{"hair":3}`,
+		`{"strength":null}`,
+		`{"status":null}`,
+		`{"name":"Renamed"}`,
+		`[]`,
+	} {
+		_, status := requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+}
+
+func TestUpdateSurvivor_MediaType(t *testing.T) {
+	userID := "patch-media-type-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	existing := createSurvivors(t, userID, settlementID, "Typed")[0]
+
+	raw, status := requester.PatchSurvivor(userID, settlementID, existing.ID.String(), "application/json", `{"statUpdates":{"strength":2},"luck":1}`)
+	require.Equal(t, http.StatusOK, status)
+	var result survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	assert.Equal(t, 2, result.Strength)
+	assert.Equal(t, 1, result.Luck)
+
+	_, status = requester.PatchSurvivor(userID, settlementID, existing.ID.String(), "text/plain", `{"strength":2}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, status)
+}
+
+func TestUpdateSurvivor_PatchNullClearsImpairments(t *testing.T) {
+	userID := "patch-impairments-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	existing := createSurvivors(t, userID, settlementID, "Scarred")[0]
+
+	raw, status := requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), `{"impairments":["Blind","Deaf"]}`)
+	require.Equal(t, http.StatusOK, status)
+
+	var updated survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&updated))
+	assert.Equal(t, []string{"Blind", "Deaf"}, updated.Impairments)
+
+	raw, status = requester.UpdateSurvivor(userID, settlementID, existing.ID.String(), `{"impairments":null}`)
+	require.Equal(t, http.StatusOK, status)
+
+	var cleared survivor.UpdateResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&cleared))
+	assert.Empty(t, cleared.Impairments)
+}
+
+func recordFate(t *testing.T, userID, settlementID string, survivorID uuid.UUID, body string) survivor.FateResult {
+	raw, status := requester.RecordSurvivorFate(userID, settlementID, survivorID.String(), body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var result survivor.FateResult
+	require.NoError(t, json.NewDecoder(raw).Decode(&result))
+	return result
+}
+
+func memorial(t *testing.T, userID, settlementID string) []domain.MemorialEntry {
+	raw, status := requester.GetMemorial(userID, settlementID)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var entries []domain.MemorialEntry
+	require.NoError(t, json.NewDecoder(raw).Decode(&entries))
+	return entries
+}
+
+func TestRecordFate_Death(t *testing.T) {
+	userID := "fate-death-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	survivors := createSurvivors(t, userID, settlementID, "Lucy", "Zachary")
+
+	assert.Empty(t, memorial(t, userID, settlementID))
+
+	result := recordFate(t, userID, settlementID, survivors[0].ID,
+		`{"status":"Dead","cause":{"kind":"monster","name":"White Lion"},"phase":"showdown","epitaph":"She held the line."}`)
+	assert.Equal(t, domain.StatusDead, result.Survivor.Status)
+	assert.Equal(t, 1, result.DeathCount)
+	assert.Equal(t, userID, result.Fate.RecordedBy)
+	assert.Equal(t, 1, result.Fate.Year)
+	require.NotNil(t, result.Fate.Cause)
+	assert.Equal(t, domain.Cause{Kind: domain.CauseMonster, Name: "White Lion"}, *result.Fate.Cause)
+
+	keys := make([]string, len(result.Milestones))
+	for i, m := range result.Milestones {
+		keys[i] = m.Key
+	}
+	assert.Contains(t, keys, "first-death")
+
+	entries := memorial(t, userID, settlementID)
+	require.Len(t, entries, 1)
+	assert.Equal(t, survivors[0].ID, entries[0].ID)
+	assert.Equal(t, "Lucy", entries[0].Name)
+	require.NotNil(t, entries[0].Fate)
+	assert.Equal(t, domain.PhaseShowdown, entries[0].Fate.Phase)
+	assert.Equal(t, "She held the line.", entries[0].Fate.Epitaph)
+
+	raw, status := requester.GetSettlementStats(userID, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var stats struct {
+		DeathCount int `json:"deathCount"`
+	}
+	require.NoError(t, json.NewDecoder(raw).Decode(&stats))
+	assert.Equal(t, 1, stats.DeathCount)
+}
+
+func TestRecordFate_Retirement(t *testing.T) {
+	userID := "fate-retire-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	veteran := createSurvivors(t, userID, settlementID, "Veteran")[0]
+
+	result := recordFate(t, userID, settlementID, veteran.ID, `{"status":"Retired","year":3}`)
+	assert.Equal(t, domain.StatusRetired, result.Survivor.Status)
+	assert.Equal(t, 3, result.Fate.Year)
+	assert.Nil(t, result.Fate.Cause)
+	assert.Zero(t, result.DeathCount)
+
+	entries := memorial(t, userID, settlementID)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.StatusRetired, entries[0].Status)
+}
+
+func TestRecordFate_RevivedSurvivorLeavesMemorial(t *testing.T) {
+	userID := "fate-revive-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	lucky := createSurvivors(t, userID, settlementID, "Lucky")[0]
+
+	recordFate(t, userID, settlementID, lucky.ID, `{"status":"Dead","cause":{"kind":"event","name":"Murder"}}`)
+	require.Len(t, memorial(t, userID, settlementID), 1)
+
+	_, status := requester.UpdateSurvivor(userID, settlementID, lucky.ID.String(), `{"status":"Alive"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, memorial(t, userID, settlementID))
+
+	_, status = requester.UpdateSurvivor(userID, settlementID, lucky.ID.String(), `{"status":"Ceased to exist"}`)
+	require.Equal(t, http.StatusOK, status)
+	entries := memorial(t, userID, settlementID)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Fate)
+	assert.Equal(t, domain.StatusCeasedToExist, entries[0].Fate.Status)
+	assert.Nil(t, entries[0].Fate.Cause, "the earlier death is replaced")
+}
+
+func TestUpdateSurvivor_FallingRecordsFate(t *testing.T) {
+	userID := "fate-patch-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	s := createSurvivors(t, userID, settlementID, "Patched")[0]
+
+	_, status := requester.UpdateSurvivor(userID, settlementID, s.ID.String(), `{"status":"Dead"}`)
+	require.Equal(t, http.StatusOK, status)
+
+	entries := memorial(t, userID, settlementID)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Fate)
+	assert.Equal(t, domain.StatusDead, entries[0].Fate.Status)
+	assert.Equal(t, 1, entries[0].Fate.Year)
+	assert.Equal(t, userID, entries[0].Fate.RecordedBy)
+
+	recordFate(t, userID, settlementID, s.ID, `{"status":"Dead","epitaph":"Remembered."}`)
+	_, status = requester.UpdateSurvivor(userID, settlementID, s.ID.String(), `{"status":"Dead"}`)
+	require.Equal(t, http.StatusOK, status)
+
+	entries = memorial(t, userID, settlementID)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Fate)
+	assert.Equal(t, "Remembered.", entries[0].Fate.Epitaph, "a repeated status does not overwrite the recorded fate")
+}
+
+func TestRecordFate_InvalidRequest(t *testing.T) {
+	userID := "fate-invalid-user"
+
+	settlementID, err := requester.CreateSettlement(userID)
+	require.NoError(t, err)
+	s := createSurvivors(t, userID, settlementID, "Invalid")[0]
+
+	for _, body := range []string{
+		`{"status":"Alive"}`,
+		`{"status":"Retired","cause":{"kind":"event","name":"Old age"}}`,
+		`{"status":"Dead","cause":{"kind":"boredom","name":"x"}}`,
+		`{"status":"Dead","phase":"brunch"}`,
+		`{"status":"Dead","mood":"grim"}`,
+	} {
+		_, status := requester.RecordSurvivorFate(userID, settlementID, s.ID.String(), body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+
+	_, status := requester.RecordSurvivorFate(userID, settlementID, "not-a-uuid", `{"status":"Dead"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status = requester.RecordSurvivorFate(userID, settlementID, testenv.UUIDString(), `{"status":"Dead"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestSurvivors_SettlementIsolation(t *testing.T) {
+	owner, intruder := "survivor-routes-owner", "survivor-routes-intruder"
+	settlementID, err := requester.CreateSettlement(owner)
+	require.NoError(t, err)
+	s := createSurvivors(t, owner, settlementID, "Guarded")[0]
+	id := s.ID.String()
+
+	_, status := requester.GetSurvivors(intruder, settlementID)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.BulkUpdateSurvivors(intruder, settlementID, `{"selector":{"status":"Alive"},"statDeltas":{"strength":5}}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.UpdateSurvivor(intruder, settlementID, id, `{"strength":5}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.GetLineage(intruder, settlementID, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.GetSurvivorMilestones(intruder, settlementID, id)
+	assert.Equal(t, http.StatusNotFound, status)
+	_, status = requester.ResolveSurvivorMilestone(intruder, settlementID, id, testenv.UUIDString())
+	assert.Equal(t, http.StatusNotFound, status)
+
+	raw, status := requester.GetSurvivors(owner, settlementID)
+	require.Equal(t, http.StatusOK, status)
+	var page domain.SurvivorPage
+	require.NoError(t, json.NewDecoder(raw).Decode(&page))
+	require.Len(t, page.Survivors, 1)
+	assert.Equal(t, s.Strength, page.Survivors[0].Strength)
+}
+
+func TestMemorial_SettlementIsolation(t *testing.T) {
+	settlementID, err := requester.CreateSettlement("memorial-owner")
+	require.NoError(t, err)
+	fallen := createSurvivors(t, "memorial-owner", settlementID, "Fallen")[0]
+	recordFate(t, "memorial-owner", settlementID, fallen.ID, `{"status":"Dead"}`)
+
+	_, status := requester.GetMemorial("memorial-intruder", settlementID)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, status = requester.RecordSurvivorFate("memorial-intruder", settlementID, fallen.ID.String(), `{"status":"Retired"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	otherID, err := requester.CreateSettlement("memorial-owner")
+	require.NoError(t, err)
+	_, status = requester.RecordSurvivorFate("memorial-owner", otherID, fallen.ID.String(), `{"status":"Retired"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Empty(t, memorial(t, "memorial-owner", otherID))
+}
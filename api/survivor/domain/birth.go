@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+
+	settlementdomain "github.com/failuretoload/datamonster/settlement/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+var ErrInvalidParents = errors.New("a newborn requires two distinct living parents from the settlement")
+
+type BirthRequest struct {
+	Parents []uuid.UUID `json:"parents"`
+	Name    string      `json:"name"`
+	Gender  string      `json:"gender"`
+}
+
+type BirthResult struct {
+	Survivor
+	Parents []uuid.UUID `json:"parents"`
+	Applied []string    `json:"applied"`
+}
+
+const (
+	InnovationFamily               = "019412a0-0026-7000-8000-000000000026"
+	InnovationClanOfDeath          = "019412a0-0027-7000-8000-000000000027"
+	InnovationSaga                 = "019412a0-0028-7000-8000-000000000028"
+	InnovationSurvivalOfTheFittest = settlementdomain.PrincipleSurvivalOfTheFittest
+	InnovationProtectTheYoung      = settlementdomain.PrincipleProtectTheYoung
+)
+
+type BirthRule struct {
+	InnovationID       uuid.UUID
+	Innovation         string
+	InheritSurname     bool
+	InheritFightingArt bool
+	Stats              map[string]int
+}
+
+var BirthRules = []BirthRule{
+	{InnovationID: uuid.Must(uuid.FromString(InnovationFamily)), Innovation: "Family", InheritSurname: true, InheritFightingArt: true},
+	{InnovationID: uuid.Must(uuid.FromString(InnovationClanOfDeath)), Innovation: "Clan of Death", Stats: map[string]int{"accuracy": 1, "strength": 1, "evasion": 1}},
+	{InnovationID: uuid.Must(uuid.FromString(InnovationSaga)), Innovation: "Saga", Stats: map[string]int{"huntxp": 2, "survival": 2}},
+	{InnovationID: uuid.Must(uuid.FromString(InnovationSurvivalOfTheFittest)), Innovation: "Survival of the Fittest", Stats: map[string]int{"strength": 1, "evasion": 1}},
+	{InnovationID: uuid.Must(uuid.FromString(InnovationProtectTheYoung)), Innovation: "Protect the Young", Stats: map[string]int{"survival": 1}},
+}
+
+func BirthRulesFor(innovations []uuid.UUID) []BirthRule {
+	held := make(map[uuid.UUID]bool, len(innovations))
+	for _, id := range innovations {
+		held[id] = true
+	}
+
+	var rules []BirthRule
+	for _, rule := range BirthRules {
+		if held[rule.InnovationID] {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func Living(s Survivor) bool {
+	return s.Status == StatusAlive || s.Status == StatusCannotDepart
+}
+
+func Newborn(req BirthRequest, year int, parents []Survivor, rules []BirthRule) (Survivor, []string, error) {
+	if len(parents) != 2 || parents[0].ID == parents[1].ID {
+		return Survivor{}, nil, ErrInvalidParents
+	}
+	for _, p := range parents {
+		if !Living(p) {
+			return Survivor{}, nil, ErrInvalidParents
+		}
+	}
+
+	child := Template(parents[0].SettlementID)
+	child.Name = strings.TrimSpace(req.Name)
+	child.Birth = year
+	child.Gender = req.Gender
+
+	var applied []string
+	for _, rule := range rules {
+		if rule.InheritSurname {
+			if surname := Surname(parents); surname != "" {
+				child.Name = child.Name + " " + surname
+			}
+		}
+
+		if rule.InheritFightingArt {
+			for _, p := range parents {
+				if p.FightingArt != nil {
+					art := *p.FightingArt
+					child.FightingArt = &art
+					break
+				}
+			}
+		}
+
+		for stat, delta := range rule.Stats {
+			child.addStat(stat, delta)
+		}
+
+		applied = append(applied, rule.Innovation)
+	}
+
+	return child, applied, nil
+}
+
+func Surname(parents []Survivor) string {
+	for _, p := range parents {
+		if fields := strings.Fields(p.Name); len(fields) > 1 {
+			return fields[len(fields)-1]
+		}
+	}
+	return ""
+}
+
+func (s *Survivor) addStat(key string, delta int) {
+	switch key {
+	case "huntxp":
+		s.HuntXP += delta
+	case "survival":
+		s.Survival += delta
+	case "movement":
+		s.Movement += delta
+	case "accuracy":
+		s.Accuracy += delta
+	case "strength":
+		s.Strength += delta
+	case "evasion":
+		s.Evasion += delta
+	case "luck":
+		s.Luck += delta
+	case "speed":
+		s.Speed += delta
+	case "insanity":
+		s.Insanity += delta
+	case "systemicPressure":
+		s.SystemicPressure += delta
+	case "torment":
+		s.Torment += delta
+	case "lumi":
+		s.Lumi += delta
+	case "courage":
+		s.Courage += delta
+	case "understanding":
+		s.Understanding += delta
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewborn_AppliesRulesInOrder(t *testing.T) {
+	parents := []Survivor{
+		{ID: uuid.Must(uuid.NewV7()), Name: "Bram", Status: StatusAlive},
+		{ID: uuid.Must(uuid.NewV7()), Name: "Ada Ashborn", Status: StatusCannotDepart},
+	}
+
+	child, applied, err := Newborn(
+		BirthRequest{Name: "Cael", Gender: "F"},
+		4,
+		parents,
+		BirthRulesFor([]uuid.UUID{
+			uuid.Must(uuid.FromString(InnovationSaga)),
+			uuid.Must(uuid.FromString(InnovationFamily)),
+			uuid.Must(uuid.NewV7()),
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Cael Ashborn", child.Name)
+	assert.Equal(t, 4, child.Birth)
+	assert.Equal(t, 2, child.HuntXP)
+	assert.Equal(t, 3, child.Survival)
+	assert.Equal(t, 5, child.Movement)
+	assert.Equal(t, []string{"Family", "Saga"}, applied)
+}
+
+func TestNewborn_RequiresLivingParents(t *testing.T) {
+	living := Survivor{ID: uuid.Must(uuid.NewV7()), Status: StatusAlive}
+	dead := Survivor{ID: uuid.Must(uuid.NewV7()), Status: StatusDead}
+
+	_, _, err := Newborn(BirthRequest{Name: "Cael"}, 1, []Survivor{living, dead}, nil)
+	require.ErrorIs(t, err, ErrInvalidParents)
+
+	_, _, err = Newborn(BirthRequest{Name: "Cael"}, 1, []Survivor{living, living}, nil)
+	require.ErrorIs(t, err, ErrInvalidParents)
+}
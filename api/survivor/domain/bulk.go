@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type BulkSelector struct {
+	IDs       []uuid.UUID     `json:"ids,omitempty"`
+	Status    *SurvivorStatus `json:"status,omitempty"`
+	Departing bool            `json:"departing,omitempty"`
+}
+
+type BulkUpdate struct {
+	Selector   BulkSelector   `json:"selector"`
+	StatDeltas map[string]int `json:"statDeltas"`
+}
+
+type BulkResult struct {
+	Survivors  []Survivor  `json:"survivors"`
+	Milestones []Milestone `json:"milestones"`
+}
+
+func (b BulkUpdate) Validate() error {
+	selected := 0
+	if len(b.Selector.IDs) > 0 {
+		selected++
+	}
+	if b.Selector.Status != nil {
+		selected++
+		if !ValidStatus(string(*b.Selector.Status)) {
+			return fmt.Errorf("invalid status value: %s", *b.Selector.Status)
+		}
+	}
+	if b.Selector.Departing {
+		selected++
+	}
+	if selected != 1 {
+		return fmt.Errorf("selector must set exactly one of ids, status or departing")
+	}
+
+	seen := make(map[uuid.UUID]bool, len(b.Selector.IDs))
+	for _, id := range b.Selector.IDs {
+		if seen[id] {
+			return fmt.Errorf("duplicate survivor id: %s", id)
+		}
+		seen[id] = true
+	}
+
+	if len(b.StatDeltas) == 0 {
+		return fmt.Errorf("statDeltas is required")
+	}
+	return ValidateStatDeltas(b.StatDeltas)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkUpdate_Validate(t *testing.T) {
+	alive := StatusAlive
+	sleeping := SurvivorStatus("Sleeping")
+	id := uuid.Must(uuid.NewV7())
+	deltas := map[string]int{"insanity": 1}
+
+	tests := []struct {
+		name   string
+		update BulkUpdate
+		valid  bool
+	}{
+		{"ids", BulkUpdate{Selector: BulkSelector{IDs: []uuid.UUID{id}}, StatDeltas: deltas}, true},
+		{"status", BulkUpdate{Selector: BulkSelector{Status: &alive}, StatDeltas: deltas}, true},
+		{"departing", BulkUpdate{Selector: BulkSelector{Departing: true}, StatDeltas: deltas}, true},
+		{"no selector", BulkUpdate{StatDeltas: deltas}, false},
+		{"two selectors", BulkUpdate{Selector: BulkSelector{Status: &alive, Departing: true}, StatDeltas: deltas}, false},
+		{"invalid status", BulkUpdate{Selector: BulkSelector{Status: &sleeping}, StatDeltas: deltas}, false},
+		{"duplicate ids", BulkUpdate{Selector: BulkSelector{IDs: []uuid.UUID{id, id}}, StatDeltas: deltas}, false},
+		{"no deltas", BulkUpdate{Selector: BulkSelector{Departing: true}}, false},
+		{"unknown stat", BulkUpdate{Selector: BulkSelector{Departing: true}, StatDeltas: map[string]int{"hair": 1}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.update.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+type StatRange struct {
+	Floor   int
+	Ceiling int
+}
+
+const unbounded = math.MaxInt32
+
+var StatRanges = map[string]StatRange{
+	"huntxp":           {Floor: 0, Ceiling: 16},
+	"survival":         {Floor: 0, Ceiling: unbounded},
+	"movement":         {Floor: 0, Ceiling: unbounded},
+	"insanity":         {Floor: 0, Ceiling: unbounded},
+	"systemicPressure": {Floor: 0, Ceiling: unbounded},
+	"torment":          {Floor: 0, Ceiling: unbounded},
+	"lumi":             {Floor: 0, Ceiling: unbounded},
+	"courage":          {Floor: 0, Ceiling: 9},
+	"understanding":    {Floor: 0, Ceiling: 9},
+}
+
+func RangeFor(stat string) StatRange {
+	if r, ok := StatRanges[stat]; ok {
+		return r
+	}
+	return StatRange{Floor: -unbounded, Ceiling: unbounded}
+}
+
+func (r StatRange) Clamp(value int) int {
+	return min(max(value, r.Floor), r.Ceiling)
+}
+
+func ValidateStatDeltas(deltas map[string]int) error {
+	for key, delta := range deltas {
+		if _, ok := (Survivor{}).Stat(key); !ok {
+			return fmt.Errorf("unknown stat: %s", key)
+		}
+		if delta < -unbounded || delta > unbounded {
+			return fmt.Errorf("delta out of range for %s", key)
+		}
+	}
+	return nil
+}
+
+func Resolved(s Survivor, deltas map[string]int) map[string]int {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	resolved := make(map[string]int, len(deltas))
+	for key := range deltas {
+		resolved[key], _ = s.Stat(key)
+	}
+	return resolved
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeFor(t *testing.T) {
+	assert.Equal(t, 9, RangeFor("courage").Clamp(12))
+	assert.Equal(t, 0, RangeFor("insanity").Clamp(-3))
+	assert.Equal(t, 16, RangeFor("huntxp").Clamp(17))
+	assert.Equal(t, -5, RangeFor("strength").Clamp(-5))
+}
+
+func TestValidateStatDeltas(t *testing.T) {
+	assert.NoError(t, ValidateStatDeltas(nil))
+	assert.NoError(t, ValidateStatDeltas(map[string]int{"strength": -1, "systemicPressure": 2}))
+	assert.Error(t, ValidateStatDeltas(map[string]int{"hair": 1}))
+}
+
+func TestResolved(t *testing.T) {
+	s := Survivor{Strength: 3, Courage: 9}
+
+	assert.Nil(t, Resolved(s, nil))
+	assert.Equal(t, map[string]int{"strength": 3, "courage": 9}, Resolved(s, map[string]int{"strength": 1, "courage": 4}))
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/gofrs/uuid/v5"
+import (
+	"errors"
+
+	"github.com/gofrs/uuid/v5"
+)
 
 type SurvivorStatus string
 
@@ -12,6 +16,8 @@ const (
 	StatusRetired       SurvivorStatus = "Retired"
 )
 
+var ErrDuplicateName = errors.New("a survivor with that name already exists")
+
 func ValidStatus(s string) bool {
 	switch SurvivorStatus(s) {
 	case StatusAlive, StatusDead, StatusCeasedToExist, StatusCannotDepart, StatusRetired:
@@ -44,12 +50,62 @@ type Survivor struct {
 	Disorders         []uuid.UUID    `json:"disorders,omitempty"`
 	FightingArt       *uuid.UUID     `json:"fightingArt,omitempty"`
 	SecretFightingArt *uuid.UUID     `json:"secretFightingArt,omitempty"`
+	Impairments       []string       `json:"impairments,omitempty"`
+	Departing         bool           `json:"departing"`
+}
+
+func Template(settlementID uuid.UUID) Survivor {
+	return Survivor{
+		SettlementID: settlementID,
+		Status:       StatusAlive,
+		Survival:     1,
+		Movement:     5,
+	}
+}
+
+func (s Survivor) Stat(key string) (int, bool) {
+	switch key {
+	case "huntxp":
+		return s.HuntXP, true
+	case "survival":
+		return s.Survival, true
+	case "movement":
+		return s.Movement, true
+	case "accuracy":
+		return s.Accuracy, true
+	case "strength":
+		return s.Strength, true
+	case "evasion":
+		return s.Evasion, true
+	case "luck":
+		return s.Luck, true
+	case "speed":
+		return s.Speed, true
+	case "insanity":
+		return s.Insanity, true
+	case "systemicPressure":
+		return s.SystemicPressure, true
+	case "torment":
+		return s.Torment, true
+	case "lumi":
+		return s.Lumi, true
+	case "courage":
+		return s.Courage, true
+	case "understanding":
+		return s.Understanding, true
+	}
+	return 0, false
 }
 
 type SurvivorUpdate struct {
-	StatUpdates       map[string]int  `json:"statUpdates,omitempty"`
-	StatusUpdate      *SurvivorStatus `json:"statusUpdate,omitempty"`
-	Disorders         []uuid.UUID     `json:"disorders,omitempty"`
-	FightingArt       *uuid.UUID      `json:"fightingArt,omitempty"`
-	SecretFightingArt *uuid.UUID      `json:"secretFightingArt,omitempty"`
+	StatUpdates       map[string]int
+	StatDeltas        map[string]int
+	StatusUpdate      *SurvivorStatus
+	DepartingUpdate   *bool
+	Disorders         Optional[[]uuid.UUID]
+	FightingArt       Optional[uuid.UUID]
+	SecretFightingArt Optional[uuid.UUID]
+	Impairments       Optional[[]string]
+	AddImpairments    []string
+	Fate              *Fate
 }
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	EffectStat       = "stat"
+	EffectStatus     = "status"
+	EffectImpairment = "impairment"
+)
+
+type Effect struct {
+	Type       string         `json:"type"`
+	Stat       string         `json:"stat,omitempty"`
+	Amount     int            `json:"amount,omitempty"`
+	Status     SurvivorStatus `json:"status,omitempty"`
+	Impairment string         `json:"impairment,omitempty"`
+}
+
+func (e Effect) Validate() error {
+	switch e.Type {
+	case EffectStat:
+		if _, ok := (Survivor{}).Stat(e.Stat); !ok {
+			return fmt.Errorf("unknown stat: %s", e.Stat)
+		}
+		if e.Amount == 0 {
+			return fmt.Errorf("stat effect on %s has no amount", e.Stat)
+		}
+	case EffectStatus:
+		if !ValidStatus(string(e.Status)) {
+			return fmt.Errorf("invalid status value: %s", e.Status)
+		}
+	case EffectImpairment:
+		if e.Impairment == "" {
+			return fmt.Errorf("impairment effect has no impairment")
+		}
+	default:
+		return fmt.Errorf("unknown effect type: %s", e.Type)
+	}
+	return nil
+}
+
+func UpdateFromEffects(effects []Effect) (SurvivorUpdate, error) {
+	var u SurvivorUpdate
+	for _, e := range effects {
+		if err := e.Validate(); err != nil {
+			return SurvivorUpdate{}, err
+		}
+
+		switch e.Type {
+		case EffectStat:
+			if u.StatDeltas == nil {
+				u.StatDeltas = map[string]int{}
+			}
+			u.StatDeltas[e.Stat] += e.Amount
+		case EffectStatus:
+			status := e.Status
+			u.StatusUpdate = &status
+		case EffectImpairment:
+			if !slices.Contains(u.AddImpairments, e.Impairment) {
+				u.AddImpairments = append(u.AddImpairments, e.Impairment)
+			}
+		}
+	}
+
+	return u, nil
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		effect  Effect
+		wantErr bool
+	}{
+		{"stat", Effect{Type: EffectStat, Stat: "movement", Amount: -1}, false},
+		{"unknown stat", Effect{Type: EffectStat, Stat: "charm", Amount: 1}, true},
+		{"zero amount", Effect{Type: EffectStat, Stat: "movement"}, true},
+		{"status", Effect{Type: EffectStatus, Status: StatusDead}, false},
+		{"invalid status", Effect{Type: EffectStatus, Status: "Sleepy"}, true},
+		{"impairment", Effect{Type: EffectImpairment, Impairment: "Blind"}, false},
+		{"empty impairment", Effect{Type: EffectImpairment}, true},
+		{"unknown type", Effect{Type: "curse"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.effect.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpdateFromEffects(t *testing.T) {
+	var effects []Effect
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"type":"stat","stat":"movement","amount":-1},
+		{"type":"stat","stat":"movement","amount":-1},
+		{"type":"stat","stat":"insanity","amount":3},
+		{"type":"status","status":"Cannot depart"},
+		{"type":"status","status":"Dead"},
+		{"type":"impairment","impairment":"Blind"},
+		{"type":"impairment","impairment":"Blind"},
+		{"type":"impairment","impairment":"Deaf"}
+	]`), &effects))
+
+	u, err := UpdateFromEffects(effects)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"movement": -2, "insanity": 3}, u.StatDeltas)
+	require.NotNil(t, u.StatusUpdate)
+	assert.Equal(t, StatusDead, *u.StatusUpdate)
+	assert.Equal(t, []string{"Blind", "Deaf"}, u.AddImpairments)
+	assert.NoError(t, u.Validate())
+}
+
+func TestUpdateFromEffects_Empty(t *testing.T) {
+	u, err := UpdateFromEffects(nil)
+	require.NoError(t, err)
+	assert.Equal(t, SurvivorUpdate{}, u)
+}
+
+func TestUpdateFromEffects_Invalid(t *testing.T) {
+	_, err := UpdateFromEffects([]Effect{{Type: EffectStat, Stat: "movement", Amount: 1}, {Type: "curse"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	CauseMonster      = "monster"
+	CauseEvent        = "event"
+	CauseSevereInjury = "severe-injury"
+	PhaseHunt         = "hunt"
+	PhaseShowdown     = "showdown"
+	PhaseSettlement   = "settlement"
+	MaxCauseNameLen   = 200
+	MaxEpitaphLen     = 1000
+)
+
+type Cause struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type Fate struct {
+	Status     SurvivorStatus `json:"status"`
+	Year       int            `json:"year"`
+	Cause      *Cause         `json:"cause,omitempty"`
+	Phase      string         `json:"phase,omitempty"`
+	Epitaph    string         `json:"epitaph,omitempty"`
+	RecordedBy string         `json:"recordedBy"`
+	RecordedAt time.Time      `json:"recordedAt"`
+}
+
+type FateRequest struct {
+	Status  SurvivorStatus `json:"status"`
+	Year    *int           `json:"year,omitempty"`
+	Cause   *Cause         `json:"cause,omitempty"`
+	Phase   string         `json:"phase,omitempty"`
+	Epitaph string         `json:"epitaph,omitempty"`
+}
+
+type MemorialEntry struct {
+	ID     uuid.UUID      `json:"id"`
+	Name   string         `json:"name"`
+	Birth  int            `json:"birth"`
+	Status SurvivorStatus `json:"status"`
+	Fate   *Fate          `json:"fate,omitempty"`
+}
+
+func Fallen(status SurvivorStatus) bool {
+	switch status {
+	case StatusDead, StatusRetired, StatusCeasedToExist:
+		return true
+	}
+	return false
+}
+
+func (u *SurvivorUpdate) RecordFate(year int, recordedBy string) {
+	if u.StatusUpdate == nil || !Fallen(*u.StatusUpdate) {
+		return
+	}
+	u.Fate = &Fate{Status: *u.StatusUpdate, Year: year, RecordedBy: recordedBy}
+}
+
+func ValidCause(kind string) bool {
+	switch kind {
+	case CauseMonster, CauseEvent, CauseSevereInjury:
+		return true
+	}
+	return false
+}
+
+func ValidPhase(phase string) bool {
+	switch phase {
+	case PhaseHunt, PhaseShowdown, PhaseSettlement:
+		return true
+	}
+	return false
+}
+
+func (r FateRequest) Fate(currentYear int) (Fate, error) {
+	if !Fallen(r.Status) {
+		return Fate{}, fmt.Errorf("status must be one of %s, %s or %s", StatusDead, StatusRetired, StatusCeasedToExist)
+	}
+
+	f := Fate{Status: r.Status, Year: currentYear, Phase: r.Phase, Epitaph: strings.TrimSpace(r.Epitaph)}
+	if r.Year != nil {
+		if *r.Year < 0 {
+			return Fate{}, fmt.Errorf("invalid year")
+		}
+		f.Year = *r.Year
+	}
+
+	if r.Cause != nil {
+		if r.Status == StatusRetired {
+			return Fate{}, fmt.Errorf("retirement has no cause")
+		}
+		if !ValidCause(r.Cause.Kind) {
+			return Fate{}, fmt.Errorf("invalid cause: %s", r.Cause.Kind)
+		}
+		name := strings.TrimSpace(r.Cause.Name)
+		if name == "" {
+			return Fate{}, fmt.Errorf("cause name is required")
+		}
+		if utf8.RuneCountInString(name) > MaxCauseNameLen {
+			return Fate{}, fmt.Errorf("cause name must be at most %d characters", MaxCauseNameLen)
+		}
+		f.Cause = &Cause{Kind: r.Cause.Kind, Name: name}
+	}
+
+	if f.Phase != "" && !ValidPhase(f.Phase) {
+		return Fate{}, fmt.Errorf("invalid phase: %s", f.Phase)
+	}
+	if utf8.RuneCountInString(f.Epitaph) > MaxEpitaphLen {
+		return Fate{}, fmt.Errorf("epitaph must be at most %d characters", MaxEpitaphLen)
+	}
+
+	return f, nil
+}
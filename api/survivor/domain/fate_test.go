@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFateDefaultsToCurrentYear(t *testing.T) {
+	f, err := FateRequest{
+		Status:  StatusDead,
+		Cause:   &Cause{Kind: CauseMonster, Name: " White Lion "},
+		Phase:   PhaseShowdown,
+		Epitaph: " Died swinging. ",
+	}.Fate(4)
+	require.NoError(t, err)
+	assert.Equal(t, Fate{
+		Status:  StatusDead,
+		Year:    4,
+		Cause:   &Cause{Kind: CauseMonster, Name: "White Lion"},
+		Phase:   PhaseShowdown,
+		Epitaph: "Died swinging.",
+	}, f)
+}
+
+func TestFateWithExplicitYear(t *testing.T) {
+	year := 2
+	f, err := FateRequest{Status: StatusRetired, Year: &year}.Fate(5)
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.Year)
+	assert.Nil(t, f.Cause)
+}
+
+func TestFateRejectsInvalid(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		name string
+		req  FateRequest
+	}{
+		{"alive", FateRequest{Status: StatusAlive}},
+		{"cannot depart", FateRequest{Status: StatusCannotDepart}},
+		{"negative year", FateRequest{Status: StatusDead, Year: &negative}},
+		{"retired with cause", FateRequest{Status: StatusRetired, Cause: &Cause{Kind: CauseEvent, Name: "Old age"}}},
+		{"unknown cause", FateRequest{Status: StatusDead, Cause: &Cause{Kind: "boredom", Name: "x"}}},
+		{"unnamed cause", FateRequest{Status: StatusDead, Cause: &Cause{Kind: CauseEvent, Name: " "}}},
+		{"long cause", FateRequest{Status: StatusDead, Cause: &Cause{Kind: CauseEvent, Name: strings.Repeat("a", MaxCauseNameLen+1)}}},
+		{"unknown phase", FateRequest{Status: StatusDead, Phase: "brunch"}},
+		{"long epitaph", FateRequest{Status: StatusDead, Epitaph: strings.Repeat("a", MaxEpitaphLen+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.req.Fate(1)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFallen(t *testing.T) {
+	assert.True(t, Fallen(StatusDead))
+	assert.True(t, Fallen(StatusRetired))
+	assert.True(t, Fallen(StatusCeasedToExist))
+	assert.False(t, Fallen(StatusAlive))
+	assert.False(t, Fallen(StatusCannotDepart))
+}
+
+func TestSurvivorUpdate_RecordFate(t *testing.T) {
+	dead, alive := StatusDead, StatusAlive
+
+	u := SurvivorUpdate{StatusUpdate: &dead}
+	u.RecordFate(4, "user")
+	require.NotNil(t, u.Fate)
+	assert.Equal(t, Fate{Status: StatusDead, Year: 4, RecordedBy: "user"}, *u.Fate)
+
+	u = SurvivorUpdate{StatusUpdate: &alive}
+	u.RecordFate(4, "user")
+	assert.Nil(t, u.Fate)
+
+	u = SurvivorUpdate{StatDeltas: map[string]int{"survival": 1}}
+	u.RecordFate(4, "user")
+	assert.Nil(t, u.Fate)
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	DirectionAncestors   = "ancestors"
+	DirectionDescendants = "descendants"
+	DirectionBoth        = "both"
+
+	DefaultLineageDepth = 10
+	MaxLineageDepth     = 50
+)
+
+var ErrSurvivorNotFound = errors.New("survivor not found")
+
+type LineageQuery struct {
+	Root      *uuid.UUID
+	Direction string
+	Depth     int
+}
+
+func NewLineageQuery(root *uuid.UUID, direction string, depth int) (LineageQuery, error) {
+	if direction == "" {
+		direction = DirectionBoth
+	}
+	switch direction {
+	case DirectionAncestors, DirectionDescendants, DirectionBoth:
+	default:
+		return LineageQuery{}, fmt.Errorf("invalid lineage direction: %s", direction)
+	}
+
+	if depth == 0 {
+		depth = DefaultLineageDepth
+	}
+	if depth < 0 || depth > MaxLineageDepth {
+		return LineageQuery{}, fmt.Errorf("lineage depth must be between 1 and %d", MaxLineageDepth)
+	}
+
+	return LineageQuery{Root: root, Direction: direction, Depth: depth}, nil
+}
+
+func (q LineageQuery) Ancestors() bool {
+	return q.Direction == DirectionAncestors || q.Direction == DirectionBoth
+}
+
+func (q LineageQuery) Descendants() bool {
+	return q.Direction == DirectionDescendants || q.Direction == DirectionBoth
+}
+
+type ParentLink struct {
+	ChildID  uuid.UUID
+	ParentID uuid.UUID
+}
+
+type LineageNode struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Birth      int            `json:"birth"`
+	Gender     string         `json:"gender"`
+	Status     SurvivorStatus `json:"status"`
+	Generation int            `json:"generation"`
+	Parents    []uuid.UUID    `json:"parents"`
+	Children   []uuid.UUID    `json:"children"`
+	Partners   []uuid.UUID    `json:"partners"`
+}
+
+type Lineage struct {
+	Root      *uuid.UUID    `json:"root,omitempty"`
+	Survivors []LineageNode `json:"survivors"`
+}
+
+func Generations(root uuid.UUID, links []ParentLink, q LineageQuery) map[uuid.UUID]int {
+	parents := map[uuid.UUID][]uuid.UUID{}
+	children := map[uuid.UUID][]uuid.UUID{}
+	for _, l := range links {
+		parents[l.ChildID] = append(parents[l.ChildID], l.ParentID)
+		children[l.ParentID] = append(children[l.ParentID], l.ChildID)
+	}
+
+	generations := map[uuid.UUID]int{root: 0}
+	if q.Ancestors() {
+		walkGenerations(root, parents, q.Depth, -1, generations)
+	}
+	if q.Descendants() {
+		walkGenerations(root, children, q.Depth, 1, generations)
+	}
+
+	return generations
+}
+
+func walkGenerations(root uuid.UUID, edges map[uuid.UUID][]uuid.UUID, depth, sign int, generations map[uuid.UUID]int) {
+	visited := map[uuid.UUID]bool{root: true}
+	frontier := []uuid.UUID{root}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var next []uuid.UUID
+		for _, id := range frontier {
+			for _, relative := range edges[id] {
+				if visited[relative] {
+					continue
+				}
+				visited[relative] = true
+				if _, seen := generations[relative]; !seen {
+					generations[relative] = sign * d
+				}
+				next = append(next, relative)
+			}
+		}
+		frontier = next
+	}
+}
+
+func BuildLineage(root *uuid.UUID, survivors []Survivor, links []ParentLink, generations map[uuid.UUID]int) Lineage {
+	nodes := make(map[uuid.UUID]*LineageNode, len(survivors))
+	order := make([]uuid.UUID, 0, len(survivors))
+	for _, s := range survivors {
+		nodes[s.ID] = &LineageNode{
+			ID:         s.ID,
+			Name:       s.Name,
+			Birth:      s.Birth,
+			Gender:     s.Gender,
+			Status:     s.Status,
+			Generation: generations[s.ID],
+			Parents:    []uuid.UUID{},
+			Children:   []uuid.UUID{},
+			Partners:   []uuid.UUID{},
+		}
+		order = append(order, s.ID)
+	}
+
+	parentsOf := map[uuid.UUID][]uuid.UUID{}
+	for _, l := range links {
+		child, okChild := nodes[l.ChildID]
+		parent, okParent := nodes[l.ParentID]
+		if !okChild || !okParent {
+			continue
+		}
+		child.Parents = appendUnique(child.Parents, l.ParentID)
+		parent.Children = appendUnique(parent.Children, l.ChildID)
+		parentsOf[l.ChildID] = append(parentsOf[l.ChildID], l.ParentID)
+	}
+
+	for _, parents := range parentsOf {
+		for _, a := range parents {
+			for _, b := range parents {
+				if a != b {
+					nodes[a].Partners = appendUnique(nodes[a].Partners, b)
+				}
+			}
+		}
+	}
+
+	result := Lineage{Root: root, Survivors: make([]LineageNode, 0, len(order))}
+	for _, id := range order {
+		result.Survivors = append(result.Survivors, *nodes[id])
+	}
+
+	return result
+}
+
+func appendUnique(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
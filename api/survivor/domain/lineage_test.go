@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLineageQuery(t *testing.T) {
+	q, err := NewLineageQuery(nil, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, DirectionBoth, q.Direction)
+	assert.Equal(t, DefaultLineageDepth, q.Depth)
+	assert.True(t, q.Ancestors())
+	assert.True(t, q.Descendants())
+
+	_, err = NewLineageQuery(nil, "sideways", 0)
+	assert.Error(t, err)
+
+	_, err = NewLineageQuery(nil, DirectionAncestors, MaxLineageDepth+1)
+	assert.Error(t, err)
+}
+
+func TestBuildLineage(t *testing.T) {
+	mother := Survivor{ID: uuid.Must(uuid.NewV7()), Name: "Ada"}
+	father := Survivor{ID: uuid.Must(uuid.NewV7()), Name: "Bram"}
+	child := Survivor{ID: uuid.Must(uuid.NewV7()), Name: "Cael"}
+	outsider := uuid.Must(uuid.NewV7())
+
+	links := []ParentLink{
+		{ChildID: child.ID, ParentID: mother.ID},
+		{ChildID: child.ID, ParentID: father.ID},
+		{ChildID: outsider, ParentID: mother.ID},
+	}
+	generations := map[uuid.UUID]int{child.ID: 0, mother.ID: -1, father.ID: -1}
+
+	lineage := BuildLineage(&child.ID, []Survivor{mother, father, child}, links, generations)
+	require.Len(t, lineage.Survivors, 3)
+
+	m, f, c := lineage.Survivors[0], lineage.Survivors[1], lineage.Survivors[2]
+	assert.Equal(t, []uuid.UUID{child.ID}, m.Children)
+	assert.Equal(t, []uuid.UUID{father.ID}, m.Partners)
+	assert.Equal(t, []uuid.UUID{mother.ID}, f.Partners)
+	assert.Equal(t, []uuid.UUID{mother.ID, father.ID}, c.Parents)
+	assert.Equal(t, -1, m.Generation)
+	assert.Equal(t, 0, c.Generation)
+}
+
+func TestGenerations(t *testing.T) {
+	// Every survivor in generation g has both parents in generation g-1, and
+	// each pair of parents has several children, so the number of ancestor
+	// paths from the root doubles with every generation.
+	const depth, width = 40, 6
+	gens := make([][]uuid.UUID, depth)
+	var links []ParentLink
+	for g := range depth {
+		gens[g] = make([]uuid.UUID, width)
+		for i := range width {
+			gens[g][i] = uuid.Must(uuid.NewV7())
+			if g > 0 {
+				links = append(links,
+					ParentLink{ChildID: gens[g][i], ParentID: gens[g-1][i]},
+					ParentLink{ChildID: gens[g][i], ParentID: gens[g-1][(i+1)%width]},
+				)
+			}
+		}
+	}
+	root := gens[depth-1][0]
+
+	q, err := NewLineageQuery(&root, DirectionAncestors, MaxLineageDepth)
+	require.NoError(t, err)
+	generations := Generations(root, links, q)
+	// Ancestors widen by one survivor per generation until a row is full.
+	want := 1
+	for g := 1; g < depth; g++ {
+		want += min(g+1, width)
+	}
+	assert.Len(t, generations, want)
+	assert.Equal(t, 0, generations[root])
+	assert.Equal(t, -1, generations[gens[depth-2][1]])
+	assert.Equal(t, -(depth - 1), generations[gens[0][3]])
+
+	q, err = NewLineageQuery(&root, DirectionAncestors, 2)
+	require.NoError(t, err)
+	generations = Generations(root, links, q)
+	assert.Len(t, generations, 1+2+3)
+	assert.NotContains(t, generations, gens[depth-4][0])
+
+	top := gens[0][0]
+	q, err = NewLineageQuery(&top, DirectionDescendants, 0)
+	require.NoError(t, err)
+	generations = Generations(top, links, q)
+	assert.Equal(t, 1, generations[gens[1][0]])
+	assert.Equal(t, DefaultLineageDepth, generations[gens[DefaultLineageDepth][0]])
+	assert.NotContains(t, generations, gens[DefaultLineageDepth+1][0])
+}
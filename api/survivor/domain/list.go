@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	SortName  = "name"
+	SortBirth = "birth"
+
+	MaxListLimit = 500
+)
+
+type ListQuery struct {
+	Statuses []SurvivorStatus
+	Name     string
+	Sort     string
+	Desc     bool
+	Limit    int
+	After    *Cursor
+}
+
+type Cursor struct {
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+type SurvivorPage struct {
+	Survivors  []Survivor `json:"survivors"`
+	Total      int        `json:"total"`
+	Matched    int        `json:"matched"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+func NewListQuery(statuses []string, name, sort, order, limit, cursor string) (ListQuery, error) {
+	q := ListQuery{Name: strings.TrimSpace(name), Sort: SortBirth}
+
+	for _, raw := range statuses {
+		for s := range strings.SplitSeq(raw, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			if !ValidStatus(s) {
+				return ListQuery{}, fmt.Errorf("invalid status filter: %s", s)
+			}
+			q.Statuses = append(q.Statuses, SurvivorStatus(s))
+		}
+	}
+
+	if sort != "" {
+		if !ValidSort(sort) {
+			return ListQuery{}, fmt.Errorf("invalid sort: %s", sort)
+		}
+		q.Sort = sort
+	}
+
+	switch order {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return ListQuery{}, fmt.Errorf("invalid order: %s", order)
+	}
+
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxListLimit {
+			return ListQuery{}, fmt.Errorf("limit must be between 1 and %d", MaxListLimit)
+		}
+		q.Limit = n
+	}
+
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return ListQuery{}, err
+		}
+		if q.Sort != SortName {
+			if _, err := strconv.Atoi(after.Value); err != nil {
+				return ListQuery{}, fmt.Errorf("cursor does not match sort")
+			}
+		}
+		q.After = &after
+	}
+
+	return q, nil
+}
+
+func ValidSort(key string) bool {
+	if key == SortName || key == SortBirth {
+		return true
+	}
+	_, ok := Survivor{}.Stat(key)
+	return ok
+}
+
+func (q ListQuery) CursorFor(s Survivor) string {
+	value := s.Name
+	switch q.Sort {
+	case SortName:
+	case SortBirth:
+		value = strconv.Itoa(s.Birth)
+	default:
+		stat, _ := s.Stat(q.Sort)
+		value = strconv.Itoa(stat)
+	}
+
+	return EncodeCursor(Cursor{Value: value, ID: s.ID})
+}
+
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+
+	return c, nil
+}
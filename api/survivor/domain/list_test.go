@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListQuery_Defaults(t *testing.T) {
+	q, err := NewListQuery(nil, "", "", "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, SortBirth, q.Sort)
+	assert.False(t, q.Desc)
+	assert.Zero(t, q.Limit)
+	assert.Nil(t, q.After)
+}
+
+func TestNewListQuery_Statuses(t *testing.T) {
+	q, err := NewListQuery([]string{"Alive,Dead", "Retired"}, "", "", "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []SurvivorStatus{StatusAlive, StatusDead, StatusRetired}, q.Statuses)
+
+	_, err = NewListQuery([]string{"Sleeping"}, "", "", "", "", "")
+	assert.Error(t, err)
+}
+
+func TestNewListQuery_Sort(t *testing.T) {
+	for _, key := range []string{"name", "birth", "huntxp", "systemicPressure"} {
+		_, err := NewListQuery(nil, "", key, "desc", "", "")
+		assert.NoError(t, err, key)
+	}
+
+	_, err := NewListQuery(nil, "", "hunt_xp", "", "", "")
+	assert.Error(t, err)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	s := Survivor{ID: uuid.Must(uuid.NewV7()), Name: "Aldo", Strength: 3}
+
+	q, err := NewListQuery(nil, "", "strength", "", "2", "")
+	require.NoError(t, err)
+
+	next, err := NewListQuery(nil, "", "strength", "", "2", q.CursorFor(s))
+	require.NoError(t, err)
+	require.NotNil(t, next.After)
+	assert.Equal(t, "3", next.After.Value)
+	assert.Equal(t, s.ID, next.After.ID)
+}
+
+func TestNewListQuery_CursorMismatch(t *testing.T) {
+	s := Survivor{ID: uuid.Must(uuid.NewV7()), Name: "Aldo"}
+	byName, err := NewListQuery(nil, "", "name", "", "", "")
+	require.NoError(t, err)
+
+	_, err = NewListQuery(nil, "", "strength", "", "", byName.CursorFor(s))
+	assert.Error(t, err)
+
+	_, err = NewListQuery(nil, "", "", "", "", "not-a-cursor")
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var ErrMilestoneNotFound = errors.New("milestone not found")
+
+type MilestoneRule struct {
+	Stat      string `json:"stat"`
+	Threshold int    `json:"threshold"`
+	Event     string `json:"event"`
+}
+
+type MilestoneRules []MilestoneRule
+
+var DefaultMilestoneRules = MilestoneRules{
+	{Stat: "huntxp", Threshold: 2, Event: "Age"},
+	{Stat: "huntxp", Threshold: 6, Event: "Age"},
+	{Stat: "huntxp", Threshold: 10, Event: "Age"},
+	{Stat: "huntxp", Threshold: 15, Event: "Age"},
+	{Stat: "huntxp", Threshold: 16, Event: "Retired"},
+	{Stat: "courage", Threshold: 3, Event: "Bold"},
+	{Stat: "courage", Threshold: 9, Event: "See the Truth"},
+	{Stat: "understanding", Threshold: 3, Event: "Insight"},
+	{Stat: "understanding", Threshold: 9, Event: "White Secret"},
+}
+
+type Milestone struct {
+	ID          uuid.UUID `json:"id"`
+	SurvivorID  uuid.UUID `json:"survivorId"`
+	Stat        string    `json:"stat"`
+	Threshold   int       `json:"threshold"`
+	Event       string    `json:"event"`
+	TriggeredAt time.Time `json:"triggeredAt"`
+}
+
+func ReadMilestoneRules(r io.Reader) (MilestoneRules, error) {
+	var rules MilestoneRules
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("unable to decode milestone rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if _, ok := (Survivor{}).Stat(rule.Stat); !ok {
+			return nil, fmt.Errorf("milestone rule %q references unknown stat %q", rule.Event, rule.Stat)
+		}
+		if rule.Event == "" {
+			return nil, fmt.Errorf("milestone rule for %s %d has no event", rule.Stat, rule.Threshold)
+		}
+	}
+
+	return rules, nil
+}
+
+func (rules MilestoneRules) Evaluate(before, after Survivor) []Milestone {
+	var triggered []Milestone
+	for _, rule := range rules {
+		was, _ := before.Stat(rule.Stat)
+		now, ok := after.Stat(rule.Stat)
+		if !ok {
+			continue
+		}
+
+		if was < rule.Threshold && now >= rule.Threshold {
+			triggered = append(triggered, Milestone{
+				SurvivorID: after.ID,
+				Stat:       rule.Stat,
+				Threshold:  rule.Threshold,
+				Event:      rule.Event,
+			})
+		}
+	}
+
+	return triggered
+}
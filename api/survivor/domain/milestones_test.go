@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate_CrossingThresholds(t *testing.T) {
+	before := Survivor{HuntXP: 1, Courage: 2}
+	after := Survivor{HuntXP: 7, Courage: 2, Understanding: 3}
+
+	triggered := DefaultMilestoneRules.Evaluate(before, after)
+	require.Len(t, triggered, 3)
+	assert.Equal(t, MilestoneRule{Stat: "huntxp", Threshold: 2, Event: "Age"}, ruleOf(triggered[0]))
+	assert.Equal(t, MilestoneRule{Stat: "huntxp", Threshold: 6, Event: "Age"}, ruleOf(triggered[1]))
+	assert.Equal(t, MilestoneRule{Stat: "understanding", Threshold: 3, Event: "Insight"}, ruleOf(triggered[2]))
+}
+
+func TestEvaluate_IgnoresDecreases(t *testing.T) {
+	before := Survivor{Courage: 9}
+	after := Survivor{Courage: 3}
+
+	assert.Empty(t, DefaultMilestoneRules.Evaluate(before, after))
+}
+
+func TestReadMilestoneRules(t *testing.T) {
+	rules, err := ReadMilestoneRules(strings.NewReader(`[{"stat":"lumi","threshold":5,"event":"Glow"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, MilestoneRules{{Stat: "lumi", Threshold: 5, Event: "Glow"}}, rules)
+
+	_, err = ReadMilestoneRules(strings.NewReader(`[{"stat":"charisma","threshold":5,"event":"Glow"}]`))
+	require.Error(t, err)
+
+	_, err = ReadMilestoneRules(strings.NewReader(`[{"stat":"lumi","threshold":5}]`))
+	require.Error(t, err)
+}
+
+func ruleOf(m Milestone) MilestoneRule {
+	return MilestoneRule{Stat: m.Stat, Threshold: m.Threshold, Event: m.Event}
+}
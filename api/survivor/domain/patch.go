@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+const MergePatchType = "application/merge-patch+json"
+
+func PatchMediaType(mediaType string) bool {
+	return mediaType == MergePatchType || mediaType == "application/json"
+}
+
+type MergePatch map[string]json.RawMessage
+
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Set: true, Value: &v}
+}
+
+func Null[T any]() Optional[T] {
+	return Optional[T]{Set: true}
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.Value = &v
+	return nil
+}
+
+func (p MergePatch) Update() (SurvivorUpdate, error) {
+	var u SurvivorUpdate
+	for key, raw := range p {
+		var err error
+		switch key {
+		case "status", "statusUpdate":
+			if u.StatusUpdate != nil {
+				return SurvivorUpdate{}, fmt.Errorf("status is set twice")
+			}
+			u.StatusUpdate = new(SurvivorStatus)
+			err = decodeRequired(raw, u.StatusUpdate)
+		case "departing", "departingUpdate":
+			if u.DepartingUpdate != nil {
+				return SurvivorUpdate{}, fmt.Errorf("departing is set twice")
+			}
+			u.DepartingUpdate = new(bool)
+			err = decodeRequired(raw, u.DepartingUpdate)
+		case "statDeltas":
+			err = decodeRequired(raw, &u.StatDeltas)
+		case "statUpdates":
+			var stats map[string]int
+			if err = decodeRequired(raw, &stats); err == nil {
+				for stat, value := range stats {
+					if err = u.setStat(stat, value); err != nil {
+						break
+					}
+				}
+			}
+		case "disorders":
+			err = json.Unmarshal(raw, &u.Disorders)
+		case "fightingArt":
+			err = json.Unmarshal(raw, &u.FightingArt)
+		case "secretFightingArt":
+			err = json.Unmarshal(raw, &u.SecretFightingArt)
+		case "impairments":
+			err = json.Unmarshal(raw, &u.Impairments)
+		case "id", "settlementId", "name", "birth", "gender":
+			return SurvivorUpdate{}, fmt.Errorf("%s cannot be patched", key)
+		default:
+			if _, ok := (Survivor{}).Stat(key); !ok {
+				return SurvivorUpdate{}, fmt.Errorf("unknown survivor member: %s", key)
+			}
+			var value int
+			if err = decodeRequired(raw, &value); err == nil {
+				err = u.setStat(key, value)
+			}
+		}
+		if err != nil {
+			return SurvivorUpdate{}, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	return u, u.Validate()
+}
+
+func (u *SurvivorUpdate) setStat(key string, value int) error {
+	if _, ok := u.StatUpdates[key]; ok {
+		return fmt.Errorf("stat %s is set twice", key)
+	}
+	if u.StatUpdates == nil {
+		u.StatUpdates = map[string]int{}
+	}
+	u.StatUpdates[key] = value
+	return nil
+}
+
+func decodeRequired(raw json.RawMessage, v any) error {
+	if string(raw) == "null" {
+		return fmt.Errorf("cannot be removed")
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func (u SurvivorUpdate) Validate() error {
+	if u.StatusUpdate != nil && !ValidStatus(string(*u.StatusUpdate)) {
+		return fmt.Errorf("invalid status value: %s", *u.StatusUpdate)
+	}
+
+	for key := range u.StatUpdates {
+		if _, ok := (Survivor{}).Stat(key); !ok {
+			return fmt.Errorf("unknown stat: %s", key)
+		}
+	}
+
+	if err := ValidateStatDeltas(u.StatDeltas); err != nil {
+		return err
+	}
+	for key := range u.StatDeltas {
+		if _, ok := u.StatUpdates[key]; ok {
+			return fmt.Errorf("stat %s cannot be both set and incremented", key)
+		}
+	}
+
+	if u.Impairments.Set && len(u.AddImpairments) > 0 {
+		return fmt.Errorf("impairments cannot be both replaced and added to")
+	}
+	if slices.Contains(u.AddImpairments, "") || (u.Impairments.Value != nil && slices.Contains(*u.Impairments.Value, "")) {
+		return fmt.Errorf("impairment cannot be empty")
+	}
+
+	return nil
+}
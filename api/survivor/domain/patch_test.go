@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func patchUpdate(t *testing.T, body string) (SurvivorUpdate, error) {
+	var p MergePatch
+	require.NoError(t, json.Unmarshal([]byte(body), &p))
+	return p.Update()
+}
+
+func TestMergePatch_Update(t *testing.T) {
+	id := uuid.Must(uuid.NewV7())
+
+	absent, err := patchUpdate(t, `{"strength":1}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"strength": 1}, absent.StatUpdates)
+	assert.False(t, absent.FightingArt.Set)
+	assert.False(t, absent.Disorders.Set)
+	assert.False(t, absent.Impairments.Set)
+	assert.Nil(t, absent.StatusUpdate)
+
+	cleared, err := patchUpdate(t, `{"fightingArt":null,"disorders":null,"impairments":null}`)
+	require.NoError(t, err)
+	assert.Equal(t, Null[uuid.UUID](), cleared.FightingArt)
+	assert.Equal(t, Null[[]uuid.UUID](), cleared.Disorders)
+	assert.Equal(t, Null[[]string](), cleared.Impairments)
+
+	set, err := patchUpdate(t, `{"fightingArt":"`+id.String()+`","disorders":[],"status":"Dead","departing":true}`)
+	require.NoError(t, err)
+	assert.Equal(t, Some(id), set.FightingArt)
+	require.NotNil(t, set.Disorders.Value)
+	assert.Empty(t, *set.Disorders.Value)
+	require.NotNil(t, set.StatusUpdate)
+	assert.Equal(t, StatusDead, *set.StatusUpdate)
+	require.NotNil(t, set.DepartingUpdate)
+	assert.True(t, *set.DepartingUpdate)
+}
+
+func TestMergePatch_UpdateLegacyMembers(t *testing.T) {
+	u, err := patchUpdate(t, `{"statUpdates":{"strength":2,"luck":1},"statusUpdate":"Dead","departingUpdate":false}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"strength": 2, "luck": 1}, u.StatUpdates)
+	require.NotNil(t, u.StatusUpdate)
+	assert.Equal(t, StatusDead, *u.StatusUpdate)
+	require.NotNil(t, u.DepartingUpdate)
+	assert.False(t, *u.DepartingUpdate)
+}
+
+func TestMergePatch_UpdateStatDeltas(t *testing.T) {
+	u, err := patchUpdate(t, `{"statDeltas":{"strength":1,"courage":-2},"luck":3,"fightingArt":null}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"strength": 1, "courage": -2}, u.StatDeltas)
+	assert.Equal(t, map[string]int{"luck": 3}, u.StatUpdates)
+	assert.Equal(t, Null[uuid.UUID](), u.FightingArt)
+}
+
+func TestMergePatch_UpdateRejects(t *testing.T) {
+	for _, body := range []string{
+		`{"strength":null}`,
+		`{"status":null}`,
+		`{"departing":null}`,
+		`{"status":"Sleeping"}`,
+		`{"hair":1}`,
+		`{"statUpdates":{"hair":1}}`,
+		`{"statUpdates":null}`,
+		`{"statDeltas":null}`,
+		`{"statDeltas":{"hair":1}}`,
+		`{"statDeltas":{"strength":1},"strength":2}`,
+		`{"statUpdates":{"strength":1},"strength":2}`,
+		`{"status":"Dead","statusUpdate":"Alive"}`,
+		`{"name":"Renamed"}`,
+		`{"id":null}`,
+		`{"strength":"strong"}`,
+		`{"impairments":[""]}`,
+	} {
+		_, err := patchUpdate(t, body)
+		assert.Error(t, err, body)
+	}
+}
+
+func TestSurvivorUpdate_Validate(t *testing.T) {
+	dead := StatusDead
+	sleeping := SurvivorStatus("Sleeping")
+
+	assert.NoError(t, SurvivorUpdate{StatUpdates: map[string]int{"strength": 1}, StatusUpdate: &dead}.Validate())
+	assert.Error(t, SurvivorUpdate{StatusUpdate: &sleeping}.Validate())
+	assert.Error(t, SurvivorUpdate{StatUpdates: map[string]int{"hair": 1}}.Validate())
+	assert.Error(t, SurvivorUpdate{StatUpdates: map[string]int{"luck": 1}, StatDeltas: map[string]int{"luck": 1}}.Validate())
+	assert.Error(t, SurvivorUpdate{Impairments: Null[[]string](), AddImpairments: []string{"Blind"}}.Validate())
+	assert.NoError(t, SurvivorUpdate{FightingArt: Null[uuid.UUID]()}.Validate())
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/store/postgres"
 	"github.com/failuretoload/datamonster/survivor/domain"
 	"github.com/gofrs/uuid/v5"
 	"github.com/jackc/pgx/v5"
@@ -14,7 +19,7 @@ import (
 )
 
 func ErrDuplicateName(name string) error {
-	return fmt.Errorf("survivor with name %s already exists", name)
+	return fmt.Errorf("%w: %s", domain.ErrDuplicateName, name)
 }
 
 type Postgres struct {
@@ -28,10 +33,18 @@ func New(p *pgxpool.Pool) (*Postgres, error) {
 	return &Postgres{db: p}, nil
 }
 
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
 func (r Postgres) Create(ctx context.Context, d domain.Survivor) (domain.Survivor, error) {
+	return insertSurvivor(ctx, r.db, d)
+}
+
+func insertSurvivor(ctx context.Context, q querier, d domain.Survivor) (domain.Survivor, error) {
 	s := fromDTO(d)
 
-	rows, err := r.db.Query(ctx, createSurvivor,
+	rows, err := q.Query(ctx, createSurvivor,
 		s.SettlementID,
 		s.Name,
 		s.Birth,
@@ -55,7 +68,7 @@ func (r Postgres) Create(ctx context.Context, d domain.Survivor) (domain.Survivo
 		s.SecretFightingArt,
 	)
 	if err != nil {
-		if IsDuplicateKeyError(err) {
+		if postgres.IsUniqueViolation(err) {
 			logger.Error(ctx, fmt.Sprintf("survivor named %s exists", s.Name),
 				logger.SettlementID(s.SettlementID.String()),
 			)
@@ -71,6 +84,9 @@ func (r Postgres) Create(ctx context.Context, d domain.Survivor) (domain.Survivo
 	}
 
 	inserted, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[survivor])
+	if postgres.IsUniqueViolation(err) {
+		return domain.Survivor{}, ErrDuplicateName(s.Name)
+	}
 	if err != nil {
 		safeErr := fmt.Errorf("unable to read creation result")
 		logger.Error(ctx, safeErr.Error(),
@@ -105,18 +121,43 @@ var jsonToColumn = map[string]string{
 	"secretFightingArt": "secret_fighting_art",
 }
 
-func (r Postgres) Update(ctx context.Context, settlementID, survivorID uuid.UUID, updates domain.SurvivorUpdate) (domain.Survivor, error) {
+func (r Postgres) Update(ctx context.Context, settlementID, survivorID uuid.UUID, updates domain.SurvivorUpdate, rules domain.MilestoneRules) (domain.Survivor, []domain.Milestone, error) {
+	var result domain.Survivor
+	var triggered []domain.Milestone
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		result, triggered, err = r.UpdateTx(ctx, tx, settlementID, survivorID, updates, rules)
+		return err
+	})
+	if err != nil {
+		return domain.Survivor{}, nil, err
+	}
+
+	return result, triggered, nil
+}
+
+func (r Postgres) UpdateTx(ctx context.Context, tx pgx.Tx, settlementID, survivorID uuid.UUID, updates domain.SurvivorUpdate, rules domain.MilestoneRules) (domain.Survivor, []domain.Milestone, error) {
 	var setClauses []string
 	args := []any{settlementID, survivorID}
 	paramIdx := 3
 
-	for jsonKey, value := range updates.StatUpdates {
+	for _, jsonKey := range slices.Sorted(maps.Keys(updates.StatUpdates)) {
 		col, ok := jsonToColumn[jsonKey]
 		if !ok {
-			continue
+			return domain.Survivor{}, nil, fmt.Errorf("unknown stat: %s", jsonKey)
 		}
 		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, paramIdx))
-		args = append(args, value)
+		args = append(args, updates.StatUpdates[jsonKey])
+		paramIdx++
+	}
+
+	for _, jsonKey := range slices.Sorted(maps.Keys(updates.StatDeltas)) {
+		col, ok := jsonToColumn[jsonKey]
+		if !ok {
+			return domain.Survivor{}, nil, fmt.Errorf("unknown stat: %s", jsonKey)
+		}
+		setClauses = append(setClauses, deltaClause(col, jsonKey, paramIdx))
+		args = append(args, updates.StatDeltas[jsonKey])
 		paramIdx++
 	}
 
@@ -126,27 +167,63 @@ func (r Postgres) Update(ctx context.Context, settlementID, survivorID uuid.UUID
 		paramIdx++
 	}
 
-	setClauses = append(setClauses, fmt.Sprintf("disorders = $%d", paramIdx))
-	args = append(args, updates.Disorders)
-	paramIdx++
+	if updates.DepartingUpdate != nil {
+		setClauses = append(setClauses, fmt.Sprintf("departing = $%d", paramIdx))
+		args = append(args, *updates.DepartingUpdate)
+		paramIdx++
+	}
+
+	if updates.Disorders.Set {
+		setClauses = append(setClauses, fmt.Sprintf("disorders = $%d", paramIdx))
+		args = append(args, updates.Disorders.Value)
+		paramIdx++
+	}
+
+	if updates.FightingArt.Set {
+		setClauses = append(setClauses, fmt.Sprintf("fighting_art = $%d", paramIdx))
+		args = append(args, updates.FightingArt.Value)
+		paramIdx++
+	}
 
-	setClauses = append(setClauses, fmt.Sprintf("fighting_art = $%d", paramIdx))
-	args = append(args, updates.FightingArt)
-	paramIdx++
+	if updates.SecretFightingArt.Set {
+		setClauses = append(setClauses, fmt.Sprintf("secret_fighting_art = $%d", paramIdx))
+		args = append(args, updates.SecretFightingArt.Value)
+		paramIdx++
+	}
+
+	if updates.Impairments.Set {
+		impairments := []string{}
+		if updates.Impairments.Value != nil {
+			impairments = *updates.Impairments.Value
+		}
+		setClauses = append(setClauses, fmt.Sprintf("impairments = $%d", paramIdx))
+		args = append(args, impairments)
+		paramIdx++
+	}
 
-	setClauses = append(setClauses, fmt.Sprintf("secret_fighting_art = $%d", paramIdx))
-	args = append(args, updates.SecretFightingArt)
-	paramIdx++
+	if len(updates.AddImpairments) > 0 {
+		setClauses = append(setClauses, fmt.Sprintf("impairments = impairments || ARRAY(SELECT i FROM unnest($%d::text[]) AS i WHERE i <> ALL(impairments))", paramIdx))
+		args = append(args, updates.AddImpairments)
+		paramIdx++
+	}
 
 	query := fmt.Sprintf("UPDATE survivor SET %s WHERE settlement_id = $1 AND external_id = $2 RETURNING *", strings.Join(setClauses, ", "))
 
-	rows, err := r.db.Query(ctx, query, args...)
+	before, err := lockSurvivor(ctx, tx, settlementID, survivorID)
+	if err != nil {
+		return domain.Survivor{}, nil, err
+	}
+	if len(setClauses) == 0 {
+		return before, []domain.Milestone{}, nil
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		safeErr := fmt.Errorf("unable to update survivor")
 		logger.Error(ctx, safeErr.Error(),
 			logger.ErrorField(err),
 		)
-		return domain.Survivor{}, safeErr
+		return domain.Survivor{}, nil, safeErr
 	}
 
 	updated, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[survivor])
@@ -155,47 +232,554 @@ func (r Postgres) Update(ctx context.Context, settlementID, survivorID uuid.UUID
 		logger.Error(ctx, safeErr.Error(),
 			logger.ErrorField(err),
 		)
+		return domain.Survivor{}, nil, safeErr
+	}
+
+	result := toDTO(updated)
+	if updates.Fate != nil && domain.Fallen(result.Status) && before.Status != result.Status {
+		if _, err := writeFate(ctx, tx, settlementID, survivorID, *updates.Fate); err != nil {
+			return domain.Survivor{}, nil, err
+		}
+	}
+
+	triggered, err := queueMilestones(ctx, tx, settlementID, rules.Evaluate(before, result))
+	if err != nil {
+		return domain.Survivor{}, nil, err
+	}
+
+	return result, triggered, nil
+}
+
+func (r Postgres) BulkUpdate(ctx context.Context, settlementID uuid.UUID, update domain.BulkUpdate, rules domain.MilestoneRules) (domain.BulkResult, error) {
+	selector, selectorArg := bulkSelector(update.Selector)
+
+	keys := slices.Sorted(maps.Keys(update.StatDeltas))
+	setClauses := make([]string, 0, len(keys))
+	deltas := []any{settlementID}
+	for _, key := range keys {
+		deltas = append(deltas, update.StatDeltas[key])
+		setClauses = append(setClauses, deltaClause(jsonToColumn[key], key, len(deltas)))
+	}
+	deltas = append(deltas, nil)
+	query := fmt.Sprintf("UPDATE survivor SET %s WHERE settlement_id = $1 AND external_id = ANY($%d) RETURNING *", strings.Join(setClauses, ", "), len(deltas))
+
+	result := domain.BulkResult{Survivors: []domain.Survivor{}, Milestones: []domain.Milestone{}}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, lockSelected+selector+" ORDER BY id FOR UPDATE", settlementID, selectorArg)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to select survivors")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		locked, err := pgx.CollectRows(rows, pgx.RowToStructByName[survivor])
+		if err != nil {
+			safeErr := fmt.Errorf("unable to read selected survivors")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		if len(update.Selector.IDs) > 0 && len(locked) != len(update.Selector.IDs) {
+			return domain.ErrSurvivorNotFound
+		}
+		if len(locked) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(locked))
+		for i, l := range locked {
+			ids[i] = l.ExternalID
+		}
+		deltas[len(deltas)-1] = ids
+
+		rows, err = tx.Query(ctx, query, deltas...)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to update survivors")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		updated, err := pgx.CollectRows(rows, pgx.RowToStructByName[survivor])
+		if err != nil {
+			safeErr := fmt.Errorf("unable to read bulk update result")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return safeErr
+		}
+
+		byID := make(map[uuid.UUID]survivor, len(updated))
+		for _, u := range updated {
+			byID[u.ExternalID] = u
+		}
+
+		var candidates []domain.Milestone
+		for _, before := range locked {
+			after := toDTO(byID[before.ExternalID])
+			result.Survivors = append(result.Survivors, after)
+			candidates = append(candidates, rules.Evaluate(toDTO(before), after)...)
+		}
+
+		result.Milestones, err = queueMilestones(ctx, tx, settlementID, candidates)
+		return err
+	})
+	if err != nil {
+		return domain.BulkResult{}, err
+	}
+
+	return result, nil
+}
+
+// deltaClause adds the delta in bigint so large deltas clamp instead of
+// overflowing the integer column.
+func deltaClause(col, stat string, param int) string {
+	bounds := domain.RangeFor(stat)
+	return fmt.Sprintf("%s = LEAST(GREATEST(%s::bigint + $%d, %d), %d)", col, col, param, bounds.Floor, bounds.Ceiling)
+}
+
+func bulkSelector(s domain.BulkSelector) (string, any) {
+	switch {
+	case len(s.IDs) > 0:
+		return " AND external_id = ANY($2)", s.IDs
+	case s.Status != nil:
+		return " AND status = $2::text::survivor_status", string(*s.Status)
+	default:
+		return " AND departing = $2", true
+	}
+}
+
+func lockSurvivor(ctx context.Context, tx pgx.Tx, settlementID, survivorID uuid.UUID) (domain.Survivor, error) {
+	rows, err := tx.Query(ctx, getForUpdate, settlementID, survivorID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query survivor")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Survivor{}, safeErr
+	}
+
+	current, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[survivor])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Survivor{}, domain.ErrSurvivorNotFound
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read survivor")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
 		return domain.Survivor{}, safeErr
 	}
 
-	return toDTO(updated), nil
+	return toDTO(current), nil
 }
 
-func (r Postgres) All(ctx context.Context, settlement uuid.UUID) ([]domain.Survivor, error) {
-	rows, err := r.db.Query(ctx, getAll, settlement)
+func queueMilestones(ctx context.Context, tx pgx.Tx, settlementID uuid.UUID, candidates []domain.Milestone) ([]domain.Milestone, error) {
+	queued := []domain.Milestone{}
+	for _, m := range candidates {
+		rows, err := tx.Query(ctx, insertMilestone, settlementID, m.SurvivorID, m.Stat, m.Threshold, m.Event)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to queue survivor milestone")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return nil, safeErr
+		}
+
+		inserted, err := pgx.CollectRows(rows, pgx.RowToStructByName[milestone])
+		if err != nil {
+			safeErr := fmt.Errorf("unable to read queued survivor milestone")
+			logger.Error(ctx, safeErr.Error(),
+				logger.SettlementID(settlementID.String()),
+				logger.ErrorField(err),
+			)
+			return nil, safeErr
+		}
+
+		for _, i := range inserted {
+			queued = append(queued, milestoneToDTO(i))
+		}
+	}
+
+	return queued, nil
+}
+
+func (r Postgres) PendingMilestones(ctx context.Context, settlementID, survivorID uuid.UUID) ([]domain.Milestone, error) {
+	rows, err := r.db.Query(ctx, getPendingMilestones, settlementID, survivorID)
 	if err != nil {
-		safeErr := fmt.Errorf("unable to query survivors for settlement")
+		safeErr := fmt.Errorf("unable to query survivor milestones")
 		logger.Error(ctx, safeErr.Error(),
-			logger.SettlementID(settlement.String()),
+			logger.SettlementID(settlementID.String()),
 			logger.ErrorField(err),
 		)
 		return nil, safeErr
 	}
-	defer rows.Close()
 
-	survivors, err := pgx.CollectRows(rows, pgx.RowToStructByName[survivor])
+	pending, err := pgx.CollectRows(rows, pgx.RowToStructByName[milestone])
 	if err != nil {
-		safeErr := fmt.Errorf("unable to scan survivors for settlement")
+		safeErr := fmt.Errorf("unable to scan survivor milestones")
 		logger.Error(ctx, safeErr.Error(),
-			logger.SettlementID(settlement.String()),
+			logger.SettlementID(settlementID.String()),
 			logger.ErrorField(err),
 		)
 		return nil, safeErr
 	}
 
-	if len(survivors) == 0 {
+	dtos := make([]domain.Milestone, len(pending))
+	for i, m := range pending {
+		dtos[i] = milestoneToDTO(m)
+	}
+
+	return dtos, nil
+}
+
+func (r Postgres) ResolveMilestone(ctx context.Context, settlementID, survivorID, milestoneID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, resolveMilestone, settlementID, survivorID, milestoneID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to resolve survivor milestone")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return safeErr
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMilestoneNotFound
+	}
+
+	return nil
+}
+
+func (r Postgres) RecordFate(ctx context.Context, settlementID, survivorID uuid.UUID, fate domain.Fate, rules domain.MilestoneRules) (domain.Survivor, domain.Fate, error) {
+	var result domain.Survivor
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		result, _, err = r.UpdateTx(ctx, tx, settlementID, survivorID, domain.SurvivorUpdate{StatusUpdate: &fate.Status}, rules)
+		if err != nil {
+			return err
+		}
+
+		fate, err = writeFate(ctx, tx, settlementID, survivorID, fate)
+		return err
+	})
+	if err != nil {
+		return domain.Survivor{}, domain.Fate{}, err
+	}
+
+	return result, fate, nil
+}
+
+func writeFate(ctx context.Context, tx pgx.Tx, settlementID, survivorID uuid.UUID, fate domain.Fate) (domain.Fate, error) {
+	var causeKind, causeName *string
+	if fate.Cause != nil {
+		causeKind, causeName = &fate.Cause.Kind, &fate.Cause.Name
+	}
+	var phase *string
+	if fate.Phase != "" {
+		phase = &fate.Phase
+	}
+
+	err := tx.QueryRow(ctx, upsertFate,
+		settlementID,
+		survivorID,
+		string(fate.Status),
+		fate.Year,
+		causeKind,
+		causeName,
+		phase,
+		fate.Epitaph,
+		fate.RecordedBy,
+	).Scan(&fate.RecordedAt)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to record survivor fate")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Fate{}, safeErr
+	}
+
+	return fate, nil
+}
+
+func (r Postgres) Memorial(ctx context.Context, settlementID uuid.UUID) ([]domain.MemorialEntry, error) {
+	rows, err := r.db.Query(ctx, getMemorial, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query memorial")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	entries := []domain.MemorialEntry{}
+	var (
+		entry                       domain.MemorialEntry
+		status                      string
+		year                        *int
+		causeKind, causeName, phase *string
+		epitaph, recordedBy         *string
+		recordedAt                  *time.Time
+	)
+	_, err = pgx.ForEachRow(rows, []any{
+		&entry.ID, &entry.Name, &entry.Birth, &status,
+		&year, &causeKind, &causeName, &phase, &epitaph, &recordedBy, &recordedAt,
+	}, func() error {
+		e := domain.MemorialEntry{ID: entry.ID, Name: entry.Name, Birth: entry.Birth, Status: domain.SurvivorStatus(status)}
+		if year != nil {
+			f := domain.Fate{Status: e.Status, Year: *year, Epitaph: *epitaph, RecordedBy: *recordedBy, RecordedAt: *recordedAt}
+			if causeKind != nil {
+				f.Cause = &domain.Cause{Kind: *causeKind, Name: *causeName}
+			}
+			if phase != nil {
+				f.Phase = *phase
+			}
+			e.Fate = &f
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan memorial")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	return entries, nil
+}
+
+func (r Postgres) Get(ctx context.Context, settlementID, survivorID uuid.UUID) (*domain.Survivor, error) {
+	rows, err := r.db.Query(ctx, getOne, settlementID, survivorID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query survivor")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
+
+	found, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[survivor])
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read survivor")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, safeErr
+	}
 
-	return toDTOList(survivors), nil
+	result := toDTO(found)
+	return &result, nil
 }
 
-func IsDuplicateKeyError(err error) bool {
-	if err == nil {
-		return false
+func (r Postgres) Birth(ctx context.Context, child domain.Survivor, parents []uuid.UUID) (domain.Survivor, error) {
+	var born domain.Survivor
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		inserted, err := insertSurvivor(ctx, tx, child)
+		if err != nil {
+			return err
+		}
+
+		for _, parentID := range parents {
+			if _, err := tx.Exec(ctx, insertParent, inserted.SettlementID, inserted.ID, parentID); err != nil {
+				safeErr := fmt.Errorf("unable to record survivor parent")
+				logger.Error(ctx, safeErr.Error(),
+					logger.SettlementID(inserted.SettlementID.String()),
+					logger.ErrorField(err),
+				)
+				return safeErr
+			}
+		}
+
+		born = inserted
+		return nil
+	})
+
+	return born, err
+}
+
+func (r Postgres) Lineage(ctx context.Context, settlementID uuid.UUID, q domain.LineageQuery) (domain.Lineage, error) {
+	linkRows, err := r.db.Query(ctx, getParentLinks, settlementID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query parent links")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Lineage{}, safeErr
 	}
 
-	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+	links, err := pgx.CollectRows(linkRows, func(row pgx.CollectableRow) (domain.ParentLink, error) {
+		var l domain.ParentLink
+		err := row.Scan(&l.ChildID, &l.ParentID)
+		return l, err
+	})
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan parent links")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Lineage{}, safeErr
+	}
+
+	var rows pgx.Rows
+	generations := map[uuid.UUID]int{}
+	if q.Root == nil {
+		rows, err = r.db.Query(ctx, getLineageAll, settlementID)
+	} else {
+		root, getErr := r.Get(ctx, settlementID, *q.Root)
+		if getErr != nil {
+			return domain.Lineage{}, getErr
+		}
+		if root == nil {
+			return domain.Lineage{}, domain.ErrSurvivorNotFound
+		}
+
+		generations = domain.Generations(root.ID, links, q)
+		members := make([]uuid.UUID, 0, len(generations))
+		for id := range generations {
+			members = append(members, id)
+		}
+		rows, err = r.db.Query(ctx, getLineageByID, settlementID, members)
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query lineage survivors")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Lineage{}, safeErr
+	}
+
+	survivors, err := pgx.CollectRows(rows, pgx.RowToStructByName[survivor])
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan lineage survivors")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.Lineage{}, safeErr
+	}
+
+	return domain.BuildLineage(q.Root, toDTOList(survivors), links, generations), nil
+}
+
+func (r Postgres) List(ctx context.Context, settlementID uuid.UUID, q domain.ListQuery) (domain.SurvivorPage, error) {
+	args := []any{settlementID}
+	filters := ""
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, 0, len(q.Statuses))
+		for _, s := range q.Statuses {
+			statuses = append(statuses, string(s))
+		}
+		args = append(args, statuses)
+		filters += fmt.Sprintf(" AND status = ANY($%d::text[]::survivor_status[])", len(args))
+	}
+	if q.Name != "" {
+		args = append(args, likeEscaper.Replace(q.Name))
+		filters += fmt.Sprintf(" AND name ILIKE '%%' || $%d || '%%'", len(args))
+	}
+
+	var page domain.SurvivorPage
+	err := r.db.QueryRow(ctx, fmt.Sprintf(countSurvivors, filters), args...).Scan(&page.Total, &page.Matched)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to count survivors for settlement")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.SurvivorPage{}, safeErr
+	}
+
+	column := sortColumn(q.Sort)
+	direction, comparison := "ASC", ">"
+	if q.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if q.After != nil {
+		var value any = q.After.Value
+		if q.Sort != domain.SortName {
+			n, err := strconv.Atoi(q.After.Value)
+			if err != nil {
+				return domain.SurvivorPage{}, fmt.Errorf("cursor does not match sort")
+			}
+			value = n
+		}
+		args = append(args, value, q.After.ID)
+		filters += fmt.Sprintf(" AND (%s, external_id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args))
+	}
+
+	query := listSurvivors + filters + fmt.Sprintf(" ORDER BY %s %s, external_id %s", column, direction, direction)
+	if q.Limit > 0 {
+		args = append(args, q.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query survivors for settlement")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.SurvivorPage{}, safeErr
+	}
+
+	survivors, err := pgx.CollectRows(rows, pgx.RowToStructByName[survivor])
+	if err != nil {
+		safeErr := fmt.Errorf("unable to scan survivors for settlement")
+		logger.Error(ctx, safeErr.Error(),
+			logger.SettlementID(settlementID.String()),
+			logger.ErrorField(err),
+		)
+		return domain.SurvivorPage{}, safeErr
+	}
+
+	if q.Limit > 0 && len(survivors) > q.Limit {
+		survivors = survivors[:q.Limit]
+		page.NextCursor = q.CursorFor(toDTO(survivors[len(survivors)-1]))
+	}
+	page.Survivors = toDTOList(survivors)
+
+	return page, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func sortColumn(key string) string {
+	switch key {
+	case domain.SortName:
+		return "name"
+	case domain.SortBirth:
+		return "birth"
+	}
+	if column, ok := jsonToColumn[key]; ok {
+		return column
+	}
+	return "birth"
 }
 
 type survivor struct {
@@ -223,6 +807,31 @@ type survivor struct {
 	Disorders         []uuid.UUID `db:"disorders"`
 	FightingArt       *uuid.UUID  `db:"fighting_art"`
 	SecretFightingArt *uuid.UUID  `db:"secret_fighting_art"`
+	Impairments       []string    `db:"impairments"`
+	Departing         bool        `db:"departing"`
+}
+
+type milestone struct {
+	ID           int        `db:"id"`
+	ExternalID   uuid.UUID  `db:"external_id"`
+	SettlementID uuid.UUID  `db:"settlement_id"`
+	SurvivorID   uuid.UUID  `db:"survivor_id"`
+	Stat         string     `db:"stat"`
+	Threshold    int        `db:"threshold"`
+	Event        string     `db:"event"`
+	TriggeredAt  time.Time  `db:"triggered_at"`
+	ResolvedAt   *time.Time `db:"resolved_at"`
+}
+
+func milestoneToDTO(m milestone) domain.Milestone {
+	return domain.Milestone{
+		ID:          m.ExternalID,
+		SurvivorID:  m.SurvivorID,
+		Stat:        m.Stat,
+		Threshold:   m.Threshold,
+		Event:       m.Event,
+		TriggeredAt: m.TriggeredAt,
+	}
 }
 
 func toDTO(s survivor) domain.Survivor {
@@ -250,6 +859,8 @@ func toDTO(s survivor) domain.Survivor {
 		Disorders:         s.Disorders,
 		FightingArt:       s.FightingArt,
 		SecretFightingArt: s.SecretFightingArt,
+		Impairments:       s.Impairments,
+		Departing:         s.Departing,
 	}
 }
 
@@ -288,5 +899,7 @@ func fromDTO(s domain.Survivor) survivor {
 		Disorders:         s.Disorders,
 		FightingArt:       s.FightingArt,
 		SecretFightingArt: s.SecretFightingArt,
+		Impairments:       s.Impairments,
+		Departing:         s.Departing,
 	}
 }
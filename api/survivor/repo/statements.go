@@ -49,5 +49,44 @@ VALUES (
 )
 RETURNING *
 `
-	getAll = "SELECT * FROM survivor where settlement_id = $1"
+	listSurvivors  = "SELECT * FROM survivor WHERE settlement_id = $1"
+	countSurvivors = "SELECT COUNT(*), COUNT(*) FILTER (WHERE TRUE%s) FROM survivor WHERE settlement_id = $1"
+	getOne         = "SELECT * FROM survivor WHERE settlement_id = $1 AND external_id = $2"
+	getForUpdate   = "SELECT * FROM survivor WHERE settlement_id = $1 AND external_id = $2 FOR UPDATE"
+	lockSelected   = "SELECT * FROM survivor WHERE settlement_id = $1"
+
+	getParentLinks = "SELECT child_id, parent_id FROM survivor_parent WHERE settlement_id = $1"
+	getLineageAll  = "SELECT * FROM survivor WHERE settlement_id = $1 ORDER BY birth, id"
+	getLineageByID = "SELECT * FROM survivor WHERE settlement_id = $1 AND external_id = ANY($2) ORDER BY birth, id"
+
+	insertParent = "INSERT INTO survivor_parent (settlement_id, child_id, parent_id) VALUES ($1, $2, $3)"
+
+	insertMilestone = `INSERT INTO survivor_milestone (settlement_id, survivor_id, stat, threshold, event)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (survivor_id, stat, threshold) DO NOTHING
+RETURNING *`
+	getPendingMilestones = `SELECT * FROM survivor_milestone
+WHERE settlement_id = $1 AND survivor_id = $2 AND resolved_at IS NULL
+ORDER BY triggered_at, threshold`
+	resolveMilestone = `UPDATE survivor_milestone SET resolved_at = NOW()
+WHERE settlement_id = $1 AND survivor_id = $2 AND external_id = $3 AND resolved_at IS NULL`
+
+	upsertFate = `INSERT INTO survivor_fate (survivor_id, settlement_id, status, year, cause_kind, cause, phase, epitaph, recorded_by)
+VALUES ($2, $1, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (survivor_id) DO UPDATE SET
+	status = EXCLUDED.status,
+	year = EXCLUDED.year,
+	cause_kind = EXCLUDED.cause_kind,
+	cause = EXCLUDED.cause,
+	phase = EXCLUDED.phase,
+	epitaph = EXCLUDED.epitaph,
+	recorded_by = EXCLUDED.recorded_by,
+	recorded_at = NOW()
+RETURNING recorded_at`
+	getMemorial = `SELECT sv.external_id, sv.name, sv.birth, sv.status::text,
+	f.year, f.cause_kind, f.cause, f.phase, f.epitaph, f.recorded_by, f.recorded_at
+FROM survivor sv
+LEFT JOIN survivor_fate f ON f.survivor_id = sv.external_id AND f.status = sv.status::text
+WHERE sv.settlement_id = $1 AND sv.status IN ('Dead', 'Retired', 'Ceased to exist')
+ORDER BY f.year DESC NULLS LAST, sv.name, sv.id`
 )
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -29,6 +30,11 @@ type GlossaryContainer struct {
 	URL       string
 }
 
+type ValkeyContainer struct {
+	container testcontainers.Container
+	Addr      string
+}
+
 type Requester struct {
 	authorizer *AuthorizerFake
 	handler    http.Handler
@@ -116,6 +122,41 @@ func (g *GlossaryContainer) Cleanup(ctx context.Context) {
 	}
 }
 
+func NewValkeyContainer(ctx context.Context) (*ValkeyContainer, error) {
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "valkey/valkey:8-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections"),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start valkey container: %w", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container host: %w", err)
+	}
+
+	port, err := container.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container port: %w", err)
+	}
+
+	return &ValkeyContainer{
+		container: container,
+		Addr:      fmt.Sprintf("%s:%s", host, port.Port()),
+	}, nil
+}
+
+func (v *ValkeyContainer) Cleanup(ctx context.Context) {
+	if err := v.container.Terminate(ctx); err != nil {
+		log.Fatalf("failed to terminate valkey container: %s", err)
+	}
+}
+
 func NewRequester(controllers []server.Controller) (*Requester, error) {
 	authorizer := &AuthorizerFake{
 		authorized: true,
@@ -168,6 +209,352 @@ func (r Requester) CreateSettlementWithBody(userID, body string) (*bytes.Buffer,
 	return w.Body, w.Code
 }
 
+func (r Requester) GetTimeline(userID string, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/timeline", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetEventDeck(userID string, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/events/deck", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) ShuffleEventDeck(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	return r.shuffleEventDeck(userID, settlementID, strings.NewReader(body))
+}
+
+func (r Requester) ShuffleEventDeckChunked(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	return r.shuffleEventDeck(userID, settlementID, io.MultiReader(strings.NewReader(body)))
+}
+
+func (r Requester) shuffleEventDeck(userID string, settlementID string, body io.Reader) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/events/deck", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) EventDeckAction(userID string, settlementID string, action string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/events/deck/"+action, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetEventHistory(userID string, settlementID string, query string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/events/history"+query, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) Roll(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/rolls", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetRolls(userID string, settlementID string, query string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/rolls"+query, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetEndeavors(userID string, settlementID string, query string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	target := "/api/settlements/" + settlementID + "/endeavors"
+	if query != "" {
+		target += "?" + query
+	}
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) RecordEndeavor(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/endeavors", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) CloseDeparture(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/departures/close", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetLocations(userID string, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/locations", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) SetLocation(userID string, settlementID string, locationID string, built bool) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	body := fmt.Sprintf(`{"built":%t}`, built)
+	req := httptest.NewRequest(http.MethodPut, "/api/settlements/"+settlementID+"/locations/"+locationID, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetStorage(userID string, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/storage", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) AdjustStorage(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/storage", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) Craft(userID string, settlementID string, recipeID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	body := fmt.Sprintf(`{"recipe":"%s"}`, recipeID)
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/craft", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetLoadout(userID, settlementID, survivorID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/loadout", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) SaveLoadout(userID, settlementID, survivorID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/loadout", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) ApplyLoadoutPreset(userID, settlementID, survivorID, presetID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	body := fmt.Sprintf(`{"preset":"%s"}`, presetID)
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/loadout/preset", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetLoadoutPresets(userID, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/loadouts/presets", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) CreateLoadoutPreset(userID, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/loadouts/presets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) DeleteLoadoutPreset(userID, settlementID, presetID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/settlements/"+settlementID+"/loadouts/presets/"+presetID, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetMonsters(userID, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/monsters", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetHuntable(userID, settlementID string, year string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	url := "/api/settlements/" + settlementID + "/monsters/huntable"
+	if year != "" {
+		url += "?year=" + year
+	}
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) UnlockMonster(userID, settlementID, monsterID string, unlocked bool) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	body := fmt.Sprintf(`{"unlocked":%t}`, unlocked)
+	req := httptest.NewRequest(http.MethodPut, "/api/settlements/"+settlementID+"/monsters/"+monsterID, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) ScheduleEncounter(userID, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/monsters/encounters", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) RecordVictory(userID, settlementID, monsterID string, level int) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	body := fmt.Sprintf(`{"monster":"%s","level":%d}`, monsterID, level)
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/monsters/defeats", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) SearchJournal(userID, settlementID string, query string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/journal?"+query, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) CreateJournalEntry(userID, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/journal", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetJournalEntry(userID, settlementID, entryID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/journal/"+entryID, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) UpdateJournalEntry(userID, settlementID, entryID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/settlements/"+settlementID+"/journal/"+entryID, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) DeleteJournalEntry(userID, settlementID, entryID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/settlements/"+settlementID+"/journal/"+entryID, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
 func (r Requester) GetSettlements(userID string) (*bytes.Buffer, int) {
 	r.authorizer.ExpectUserID(userID)
 
@@ -188,6 +575,38 @@ func (r Requester) GetSettlement(userID string, settlementID string) (*bytes.Buf
 	return w.Body, w.Code
 }
 
+func (r Requester) GetSettlementStats(userID string, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/stats", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) RecordMilestone(userID string, settlementID string, milestone string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/milestones/"+milestone, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) AddInnovation(userID string, settlementID string, innovationID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	body := fmt.Sprintf(`{"innovationId":"%s"}`, innovationID)
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/innovations", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
 func (r Requester) CreateSurvivor(userID string, settlementID string, name string) (*bytes.Buffer, int) {
 	body := fmt.Sprintf(`{"name":"%s","birth":1,"gender":"M","huntxp":0,"survival":1,"movement":5,"accuracy":0,"strength":0,"evasion":0,"luck":0,"speed":0,"insanity":0,"systemicPressure":0,"torment":0,"lumi":0,"courage":0,"understanding":0}`, name)
 	return r.CreateSurvivorWithBody(userID, settlementID, body)
@@ -204,10 +623,54 @@ func (r Requester) CreateSurvivorWithBody(userID string, settlementID string, bo
 	return w.Body, w.Code
 }
 
+func (r Requester) BirthSurvivor(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/survivors/birth", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetLineage(userID string, settlementID string, query string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	target := "/api/settlements/" + settlementID + "/lineage"
+	if query != "" {
+		target += "?" + query
+	}
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) BulkUpdateSurvivors(userID string, settlementID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/survivors/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
 func (r Requester) GetSurvivors(userID string, settlementID string) (*bytes.Buffer, int) {
+	return r.ListSurvivors(userID, settlementID, "")
+}
+
+func (r Requester) ListSurvivors(userID string, settlementID string, query string) (*bytes.Buffer, int) {
 	r.authorizer.ExpectUserID(userID)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/survivors", nil)
+	target := "/api/settlements/" + settlementID + "/survivors"
+	if query != "" {
+		target += "?" + query
+	}
+	req := httptest.NewRequest(http.MethodGet, target, nil)
 	w := httptest.NewRecorder()
 	r.DoRequest(w, req)
 
@@ -215,9 +678,44 @@ func (r Requester) GetSurvivors(userID string, settlementID string) (*bytes.Buff
 }
 
 func (r Requester) UpdateSurvivor(userID, settlementID, survivorID string, body string) (*bytes.Buffer, int) {
+	return r.PatchSurvivor(userID, settlementID, survivorID, "application/merge-patch+json", body)
+}
+
+func (r Requester) PatchSurvivor(userID, settlementID, survivorID, contentType string, body string) (*bytes.Buffer, int) {
 	r.authorizer.ExpectUserID(userID)
 
 	req := httptest.NewRequest(http.MethodPatch, "/api/settlements/"+settlementID+"/survivors/"+survivorID, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) GetSurvivorMilestones(userID, settlementID, survivorID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/milestones", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) ResolveSurvivorMilestone(userID, settlementID, survivorID, milestoneID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/milestones/"+milestoneID, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) RecordSurvivorFate(userID, settlementID, survivorID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/fate", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.DoRequest(w, req)
@@ -225,6 +723,47 @@ func (r Requester) UpdateSurvivor(userID, settlementID, survivorID string, body
 	return w.Body, w.Code
 }
 
+func (r Requester) GetMemorial(userID, settlementID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/memorial", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) ListTokens(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tokens", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) CreateToken(userID string, body string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) RevokeToken(userID, tokenID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/tokens/"+tokenID, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
 func UUIDString() string {
 	return UUID().String()
 }
@@ -297,6 +836,78 @@ func (r Requester) GetKnowledge(userID, id string) (*bytes.Buffer, int) {
 	return w.Body, w.Code
 }
 
+func (r Requester) GetAllCampaigns(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/campaigns", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetCampaign(userID, id string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/campaigns/"+id, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetAllSettlementEvents(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/settlementevents", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetAllRollTables(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/rolltables", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetAllLocations(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/locations", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetAllResources(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/resources", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetAllRecipes(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/recipes", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetAllGear(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/gear", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
+func (r Requester) GetAllMonsters(userID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+	req := httptest.NewRequest(http.MethodGet, "/api/glossary/monsters", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+	return w.Body, w.Code
+}
+
 func (r Requester) GetGlossary(userID string) (*bytes.Buffer, int) {
 	r.authorizer.ExpectUserID(userID)
 	req := httptest.NewRequest(http.MethodGet, "/api/glossary", nil)
@@ -304,3 +915,45 @@ func (r Requester) GetGlossary(userID string) (*bytes.Buffer, int) {
 	r.DoRequest(w, req)
 	return w.Body, w.Code
 }
+
+func (r Requester) GetSurvivorKnowledge(userID, settlementID, survivorID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/knowledge", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) LearnKnowledge(userID, settlementID, survivorID, knowledgeID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	body := fmt.Sprintf(`{"knowledgeId":"%s"}`, knowledgeID)
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/knowledge", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) ObserveKnowledge(userID, settlementID, survivorID, knowledgeID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/knowledge/"+knowledgeID+"/observations", nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
+
+func (r Requester) ForgetKnowledge(userID, settlementID, survivorID, knowledgeID string) (*bytes.Buffer, int) {
+	r.authorizer.ExpectUserID(userID)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/settlements/"+settlementID+"/survivors/"+survivorID+"/knowledge/"+knowledgeID, nil)
+	w := httptest.NewRecorder()
+	r.DoRequest(w, req)
+
+	return w.Body, w.Code
+}
@@ -2,6 +2,7 @@ package testenv
 
 import (
 	"net/http"
+	"net/http/httptest"
 
 	"github.com/failuretoload/datamonster/request"
 	"github.com/go-chi/chi/v5"
@@ -39,3 +40,14 @@ func (m *AuthorizerFake) AuthorizeRequest(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func NewGlossaryStub(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/glossary" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
@@ -0,0 +1,103 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/failuretoload/datamonster/middleware"
+	"github.com/failuretoload/datamonster/request"
+	"github.com/failuretoload/datamonster/response"
+	"github.com/failuretoload/datamonster/token/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	Repo interface {
+		Create(ctx context.Context, userID string, req domain.CreateRequest) (domain.Created, error)
+		List(ctx context.Context, userID string) ([]domain.Token, error)
+		Revoke(ctx context.Context, userID string, tokenID uuid.UUID) error
+	}
+	Controller struct {
+		tokens Repo
+	}
+)
+
+func NewController(r Repo) (*Controller, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+
+	return &Controller{tokens: r}, nil
+}
+
+func (c Controller) RegisterRoutes(r chi.Router) {
+	r.Group(func(gr chi.Router) {
+		gr.Use(middleware.RequireSession)
+		gr.Get("/tokens", c.listTokens)
+		gr.Post("/tokens", c.createToken)
+		gr.Delete("/tokens/{tokenID}", c.revokeToken)
+	})
+}
+
+func (c Controller) listTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tokens, err := c.tokens.List(ctx, request.UserID(ctx))
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error retrieving access tokens: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, tokens)
+}
+
+func (c Controller) createToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body domain.CreateRequest
+	if err := request.DecodeJSON(r.Body, &body); err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("unable to decode request body: %w", err))
+		return
+	}
+
+	req, err := body.Normalize(time.Now())
+	if err != nil {
+		response.BadRequest(ctx, w, err)
+		return
+	}
+
+	created, err := c.tokens.Create(ctx, request.UserID(ctx), req)
+	if errors.Is(err, domain.ErrTooManyTokens) {
+		response.Conflict(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error creating access token: %w", err))
+		return
+	}
+
+	response.OK(ctx, w, created)
+}
+
+func (c Controller) revokeToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tokenID, err := uuid.FromString(chi.URLParam(r, "tokenID"))
+	if err != nil {
+		response.BadRequest(ctx, w, fmt.Errorf("invalid token id"))
+		return
+	}
+
+	err = c.tokens.Revoke(ctx, request.UserID(ctx), tokenID)
+	if errors.Is(err, domain.ErrTokenNotFound) {
+		response.NotFound(ctx, w, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(ctx, w, fmt.Errorf("error revoking access token: %w", err))
+		return
+	}
+
+	response.NoContent(w)
+}
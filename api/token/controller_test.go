@@ -0,0 +1,188 @@
+package token_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/failuretoload/datamonster/server"
+	"github.com/failuretoload/datamonster/testenv"
+	"github.com/failuretoload/datamonster/token"
+	"github.com/failuretoload/datamonster/token/domain"
+	tokenRepo "github.com/failuretoload/datamonster/token/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	requester *testenv.Requester
+	tokens    *tokenRepo.Postgres
+)
+
+func TestMain(m *testing.M) {
+	dbContainer, err := testenv.NewDBContainer(context.Background())
+	if err != nil {
+		log.Fatalf("unable to set up test env for token tests: %v", err)
+	}
+	defer dbContainer.Cleanup()
+
+	tokens, err = tokenRepo.New(dbContainer.PGPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tokenController, err := token.NewController(tokens)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requester, err = testenv.NewRequester([]server.Controller{tokenController})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func create(t *testing.T, userID, body string) domain.Created {
+	raw, status := requester.CreateToken(userID, body)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var created domain.Created
+	require.NoError(t, json.NewDecoder(raw).Decode(&created))
+	return created
+}
+
+func list(t *testing.T, userID string) []domain.Token {
+	raw, status := requester.ListTokens(userID)
+	require.Equal(t, http.StatusOK, status, raw.String())
+
+	var found []domain.Token
+	require.NoError(t, json.NewDecoder(raw).Decode(&found))
+	return found
+}
+
+func TestTokens_Lifecycle(t *testing.T) {
+	userID := "token-lifecycle-user"
+	assert.Empty(t, list(t, userID))
+
+	expires := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	created := create(t, userID, fmt.Sprintf(`{"name":" kitchen display ","scope":"read","expiresAt":%q}`, expires.Format(time.RFC3339)))
+	assert.Equal(t, "kitchen display", created.Name)
+	assert.Equal(t, domain.ScopeRead, created.Scope)
+	assert.True(t, domain.IsSecret(created.Secret))
+	assert.Equal(t, created.Secret[:len(created.Prefix)], created.Prefix)
+	require.NotNil(t, created.ExpiresAt)
+	assert.True(t, expires.Equal(*created.ExpiresAt))
+
+	found := list(t, userID)
+	require.Len(t, found, 1)
+	assert.Equal(t, created.ID, found[0].ID)
+	assert.Nil(t, found[0].LastUsedAt)
+
+	raw, _ := requester.ListTokens(userID)
+	assert.NotContains(t, raw.String(), created.Secret, "secrets are only shown once")
+
+	_, status := requester.RevokeToken(userID, created.ID.String())
+	require.Equal(t, http.StatusNoContent, status)
+	assert.Empty(t, list(t, userID))
+
+	_, status = requester.RevokeToken(userID, created.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestTokens_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	userID := "token-authenticate-user"
+	created := create(t, userID, `{"name":"cli","scope":"read-write"}`)
+
+	found, err := tokens.Authenticate(ctx, created.Secret)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, userID, found.UserID)
+	assert.Equal(t, domain.ScopeReadWrite, found.Scope)
+	require.NotNil(t, found.LastUsedAt)
+
+	listed := list(t, userID)
+	require.Len(t, listed, 1)
+	assert.NotNil(t, listed[0].LastUsedAt)
+
+	found, err = tokens.Authenticate(ctx, created.Secret+"x")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	_, status := requester.RevokeToken(userID, created.ID.String())
+	require.Equal(t, http.StatusNoContent, status)
+	found, err = tokens.Authenticate(ctx, created.Secret)
+	require.NoError(t, err)
+	assert.Nil(t, found, "revoked tokens no longer authenticate")
+}
+
+func TestTokens_ExpiredTokenDoesNotAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	userID := "token-expired-user"
+	soon := time.Now().Add(2 * time.Second)
+
+	created, err := tokens.Create(ctx, userID, domain.CreateRequest{Name: "brief", Scope: domain.ScopeRead, ExpiresAt: &soon})
+	require.NoError(t, err)
+
+	found, err := tokens.Authenticate(ctx, created.Secret)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	time.Sleep(time.Until(soon) + 100*time.Millisecond)
+	found, err = tokens.Authenticate(ctx, created.Secret)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestTokens_InvalidRequest(t *testing.T) {
+	userID := "token-invalid-user"
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	for _, body := range []string{
+		`{"name":"","scope":"read"}`,
+		`{"name":"x","scope":"admin"}`,
+		`{"name":"x"}`,
+		fmt.Sprintf(`{"name":"x","scope":"read","expiresAt":%q}`, past),
+		`{"name":"x","scope":"read","expiresAt":"tomorrow"}`,
+		`{"name":"x","scope":"read","secret":"mine"}`,
+	} {
+		_, status := requester.CreateToken(userID, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+
+	_, status := requester.RevokeToken(userID, "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Empty(t, list(t, userID))
+}
+
+func TestTokens_Limit(t *testing.T) {
+	userID := "token-limit-user"
+	for i := range domain.MaxTokens {
+		create(t, userID, fmt.Sprintf(`{"name":"token %d","scope":"read"}`, i))
+	}
+
+	_, status := requester.CreateToken(userID, `{"name":"one too many","scope":"read"}`)
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestTokens_UserIsolation(t *testing.T) {
+	created := create(t, "token-owner", `{"name":"mine","scope":"read"}`)
+
+	assert.Empty(t, list(t, "token-intruder"))
+	_, status := requester.RevokeToken("token-intruder", created.ID.String())
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Len(t, list(t, "token-owner"), 1)
+}
+
+func TestTokens_Unauthorized(t *testing.T) {
+	t.Cleanup(requester.Unauthorized())
+	_, status := requester.ListTokens("unauthorized")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	ScopeRead      = "read"
+	ScopeReadWrite = "read-write"
+	SecretPrefix   = "dmp_"
+	MaxNameLen     = 100
+	MaxTokens      = 25
+	prefixLen      = len(SecretPrefix) + 6
+)
+
+var (
+	ErrTokenNotFound = errors.New("access token not found")
+	ErrTooManyTokens = fmt.Errorf("a user may hold at most %d access tokens", MaxTokens)
+)
+
+type Token struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	UserID     string     `json:"-"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreateRequest struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type Created struct {
+	Token
+	Secret string `json:"token"`
+}
+
+func ValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeReadWrite
+}
+
+func (r CreateRequest) Normalize(now time.Time) (CreateRequest, error) {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return CreateRequest{}, fmt.Errorf("token name is required")
+	}
+	if utf8.RuneCountInString(r.Name) > MaxNameLen {
+		return CreateRequest{}, fmt.Errorf("token name must be at most %d characters", MaxNameLen)
+	}
+	if !ValidScope(r.Scope) {
+		return CreateRequest{}, fmt.Errorf("scope must be %s or %s", ScopeRead, ScopeReadWrite)
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(now) {
+		return CreateRequest{}, fmt.Errorf("expiry must be in the future")
+	}
+
+	return r, nil
+}
+
+func Allows(scope, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ValidScope(scope)
+	}
+	return scope == ScopeReadWrite
+}
+
+func NewSecret() (secret, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	secret = SecretPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return secret, secret[:prefixLen], nil
+}
+
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func IsSecret(s string) bool {
+	return strings.HasPrefix(s, SecretPrefix)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	r, err := CreateRequest{Name: " kitchen display ", Scope: ScopeRead, ExpiresAt: &later}.Normalize(now)
+	require.NoError(t, err)
+	assert.Equal(t, "kitchen display", r.Name)
+
+	_, err = CreateRequest{Name: "cli", Scope: ScopeReadWrite}.Normalize(now)
+	assert.NoError(t, err, "expiry is optional")
+}
+
+func TestNormalizeRejectsInvalid(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		req  CreateRequest
+	}{
+		{"blank name", CreateRequest{Name: " ", Scope: ScopeRead}},
+		{"long name", CreateRequest{Name: strings.Repeat("a", MaxNameLen+1), Scope: ScopeRead}},
+		{"unknown scope", CreateRequest{Name: "x", Scope: "admin"}},
+		{"missing scope", CreateRequest{Name: "x"}},
+		{"expired", CreateRequest{Name: "x", Scope: ScopeRead, ExpiresAt: &earlier}},
+		{"expires now", CreateRequest{Name: "x", Scope: ScopeRead, ExpiresAt: &now}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.req.Normalize(now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	assert.True(t, Allows(ScopeRead, http.MethodGet))
+	assert.True(t, Allows(ScopeRead, http.MethodHead))
+	assert.False(t, Allows(ScopeRead, http.MethodPost))
+	assert.False(t, Allows(ScopeRead, http.MethodPatch))
+	assert.False(t, Allows(ScopeRead, http.MethodDelete))
+	assert.True(t, Allows(ScopeReadWrite, http.MethodGet))
+	assert.True(t, Allows(ScopeReadWrite, http.MethodPut))
+	assert.False(t, Allows("", http.MethodGet))
+}
+
+func TestNewSecret(t *testing.T) {
+	secret, prefix, err := NewSecret()
+	require.NoError(t, err)
+	assert.True(t, IsSecret(secret))
+	assert.True(t, strings.HasPrefix(secret, prefix))
+	assert.Len(t, prefix, prefixLen)
+
+	other, _, err := NewSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+	assert.NotEqual(t, Hash(secret), Hash(other))
+	assert.Len(t, Hash(secret), 64)
+	assert.False(t, IsSecret("eyJhbGciOi"))
+}
@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/failuretoload/datamonster/logger"
+	"github.com/failuretoload/datamonster/token/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	tokenColumns = `external_id, user_id, name, prefix, scope, expires_at, last_used_at, created_at`
+	lockUser     = `SELECT pg_advisory_xact_lock(hashtext($1))`
+	countTokens  = `SELECT COUNT(*) FROM access_token WHERE user_id = $1`
+	insertToken  = `INSERT INTO access_token (user_id, name, prefix, token_hash, scope, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + tokenColumns
+	listTokens = `SELECT ` + tokenColumns + ` FROM access_token
+WHERE user_id = $1
+ORDER BY created_at, id`
+	deleteToken       = `DELETE FROM access_token WHERE user_id = $1 AND external_id = $2`
+	authenticateToken = `UPDATE access_token SET last_used_at = NOW()
+WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW())
+RETURNING ` + tokenColumns
+)
+
+type Postgres struct {
+	db *pgxpool.Pool
+}
+
+func New(p *pgxpool.Pool) (*Postgres, error) {
+	if p == nil {
+		return nil, errors.New("token repo: pgx connection pool is required")
+	}
+	return &Postgres{db: p}, nil
+}
+
+func (r Postgres) Create(ctx context.Context, userID string, req domain.CreateRequest) (domain.Created, error) {
+	secret, prefix, err := domain.NewSecret()
+	if err != nil {
+		safeErr := fmt.Errorf("unable to generate access token")
+		logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+		return domain.Created{}, safeErr
+	}
+
+	var created domain.Token
+	err = pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockUser, userID); err != nil {
+			safeErr := fmt.Errorf("unable to lock access tokens")
+			logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+			return safeErr
+		}
+
+		var count int
+		if err := tx.QueryRow(ctx, countTokens, userID).Scan(&count); err != nil {
+			safeErr := fmt.Errorf("unable to count access tokens")
+			logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+			return safeErr
+		}
+		if count >= domain.MaxTokens {
+			return domain.ErrTooManyTokens
+		}
+
+		rows, err := tx.Query(ctx, insertToken, userID, req.Name, prefix, domain.Hash(secret), req.Scope, req.ExpiresAt)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to create access token")
+			logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+			return safeErr
+		}
+
+		created, err = pgx.CollectExactlyOneRow(rows, scanToken)
+		if err != nil {
+			safeErr := fmt.Errorf("unable to read created access token")
+			logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+			return safeErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Created{}, err
+	}
+
+	return domain.Created{Token: created, Secret: secret}, nil
+}
+
+func (r Postgres) List(ctx context.Context, userID string) ([]domain.Token, error) {
+	rows, err := r.db.Query(ctx, listTokens, userID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query access tokens")
+		logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+		return nil, safeErr
+	}
+
+	tokens, err := pgx.CollectRows(rows, scanToken)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read access tokens")
+		logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+		return nil, safeErr
+	}
+
+	return tokens, nil
+}
+
+func (r Postgres) Revoke(ctx context.Context, userID string, tokenID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, deleteToken, userID, tokenID)
+	if err != nil {
+		safeErr := fmt.Errorf("unable to revoke access token")
+		logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+		return safeErr
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+func (r Postgres) Authenticate(ctx context.Context, secret string) (*domain.Token, error) {
+	rows, err := r.db.Query(ctx, authenticateToken, domain.Hash(secret))
+	if err != nil {
+		safeErr := fmt.Errorf("unable to query access token")
+		logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+		return nil, safeErr
+	}
+
+	found, err := pgx.CollectExactlyOneRow(rows, scanToken)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		safeErr := fmt.Errorf("unable to read access token")
+		logger.Error(ctx, safeErr.Error(), logger.ErrorField(err))
+		return nil, safeErr
+	}
+
+	return &found, nil
+}
+
+func scanToken(row pgx.CollectableRow) (domain.Token, error) {
+	var t domain.Token
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Prefix, &t.Scope, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt)
+	return t, err
+}
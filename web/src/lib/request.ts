@@ -14,7 +14,7 @@ export function PostJSON(url: string, data:object): Promise<Response> {
 export function PatchJSON(url: string, data: Record<string, unknown>): Promise<Response> {
   return fetch(url, {
         method: "PATCH",
-        headers: { "Content-Type": "application/json" },
+        headers: { "Content-Type": "application/merge-patch+json" },
         body: JSON.stringify(data),
         credentials: "include",
       })
@@ -6,12 +6,11 @@ import {
   TentIcon,
   SignOutIcon,
 } from "@phosphor-icons/react";
-import { Link, Outlet, useLocation, useNavigate } from "react-router";
+import { Link, Outlet, useLocation } from "react-router";
 import styles from "./page.module.css";
 
 function useNavItems() {
   const { pathname } = useLocation();
-  const navigate = useNavigate();
   const timelineKey = "timeline";
   const populationKey = "population";
   const storageKey = "storage";
@@ -27,18 +26,12 @@ function useNavItems() {
     return props;
   };
 
-  const handleLogout = async () => {
-    await fetch("/api/auth/logout", { credentials: "include" });
-    navigate("/");
-  };
-
   return {
     timelineKey,
     populationKey,
     storageKey,
     pathname,
     getProps,
-    handleLogout,
   };
 }
 
@@ -57,7 +50,7 @@ function getActiveIcon(pathname: string): React.ReactNode {
 }
 
 function NavBar() {
-  const { timelineKey, populationKey, storageKey, pathname, handleLogout } = useNavItems();
+  const { timelineKey, populationKey, storageKey, pathname } = useNavItems();
   const pageTitle = getPageTitle(pathname);
 
   return (
@@ -106,9 +99,9 @@ function NavBar() {
         </div>
         <div className={styles.navbarEnd}>
           <div className={styles.tooltip} data-tip="Logout">
-            <button onClick={handleLogout} className={styles.navLink} aria-label="Logout">
+            <a href="/auth/logout" className={styles.navLink} aria-label="Logout">
               <SignOutIcon size={24} />
-            </button>
+            </a>
           </div>
         </div>
       </div>
@@ -17,9 +17,8 @@ type SurvivorDialogProps = {
   onSuccess: () => void;
 };
 
-export type SurvivorUpdateRequest = {
-  statUpdates?: Record<string, number>;
-  statusUpdate?: SurvivorStatus;
+export type SurvivorPatch = Record<string, unknown> & {
+  status?: SurvivorStatus;
   disorders: string[];
   fightingArt: string | null;
   secretFightingArt: string | null;
@@ -112,25 +111,21 @@ export default function SurvivorDialog({data, settlementId, onClose, onSuccess}:
           ['understanding', 'understanding', 'understanding'],
         ];
 
-        const statUpdates = statFields.reduce<Record<string, number>>((acc, [formKey, survivorKey, apiKey]) => {
+        const changedStats = statFields.reduce<Record<string, number>>((acc, [formKey, survivorKey, apiKey]) => {
           if (parsed[formKey] !== data[survivorKey]) {
             acc[apiKey] = parsed[formKey] as number;
           }
           return acc;
         }, {});
 
-        const statusUpdate = parsed.status !== data.status ? parsed.status : undefined;
-
-        const payload: SurvivorUpdateRequest = {
+        const payload: SurvivorPatch = {
+          ...changedStats,
           disorders,
           fightingArt: parsed.fightingArt,
           secretFightingArt: parsed.secretFightingArt,
         };
-        if (Object.keys(statUpdates).length > 0) {
-          payload.statUpdates = statUpdates;
-        }
-        if (statusUpdate) {
-          payload.statusUpdate = statusUpdate;
+        if (parsed.status !== data.status) {
+          payload.status = parsed.status;
         }
 
         const response = await PatchJSON(`/api/settlements/${settlementId}/survivors/${data.id}`, payload);
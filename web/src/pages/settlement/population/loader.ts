@@ -5,5 +5,6 @@ export async function loadSurvivors({ params }: LoaderFunctionArgs) {
     const res = await Get(`/api/settlements/${params.settlementId}/survivors`);
     if (res.status === 401) return redirect('/');
     if (!res.ok) throw new Response('Failed to load survivors', { status: res.status });
-    return res.json();
+    const page = await res.json();
+    return page.survivors;
 }
\ No newline at end of file
@@ -41,11 +41,6 @@ export function createMockFetch(state: MockApiState) {
         : new Response(null, { status: 401 });
     }
 
-    if (url === "/api/auth/logout" && method === "POST") {
-      state.authenticated = false;
-      return new Response(null, { status: 200 });
-    }
-
     if (url === "/api/glossary") {
       return Response.json(state.glossary);
     }
@@ -69,7 +64,12 @@ export function createMockFetch(state: MockApiState) {
       const settlementId = survivorMatch[1];
 
       if (method === "GET") {
-        return Response.json(state.survivors.get(settlementId) ?? []);
+        const survivors = state.survivors.get(settlementId) ?? [];
+        return Response.json({
+          survivors,
+          total: survivors.length,
+          matched: survivors.length,
+        });
       }
 
       if (method === "POST") {
@@ -116,13 +116,7 @@ export function createMockFetch(state: MockApiState) {
         return new Response(null, { status: 404 });
       }
       const body = JSON.parse(init?.body as string);
-      const updated = { ...survivors[index], ...body.statUpdates };
-      if (body.statusUpdate) updated.status = body.statusUpdate;
-      if (body.disorders) updated.disorders = body.disorders;
-      if (body.fightingArt !== undefined)
-        updated.fightingArt = body.fightingArt;
-      if (body.secretFightingArt !== undefined)
-        updated.secretFightingArt = body.secretFightingArt;
+      const updated = { ...survivors[index], ...body };
       survivors[index] = updated;
       return Response.json(updated);
     }